@@ -20,6 +20,7 @@ type Config struct {
 	Order       dist.Order          `toml:"order"`
 	Stack       StackConfig         `toml:"stack"`
 	Lifecycle   LifecycleConfig     `toml:"lifecycle"`
+	Targets     []TargetConfig      `toml:"targets"`
 }
 
 // BuildpackCollection is a list of BuildpackConfigs
@@ -53,6 +54,14 @@ type LifecycleConfig struct {
 	Version string `toml:"version"`
 }
 
+// TargetConfig details the configuration of a builder for a platform. The build image and lifecycle
+// default to those of the stack and lifecycle configuration when not set.
+type TargetConfig struct {
+	dist.Target
+	BuildImage string          `toml:"build-image,omitempty"`
+	Lifecycle  LifecycleConfig `toml:"lifecycle,omitempty"`
+}
+
 // ReadConfig reads a builder configuration from the file path provided and returns the
 // configuration along with any warnings encountered while parsing
 func ReadConfig(path string) (config Config, warnings []string, err error) {
@@ -81,13 +90,39 @@ func ValidateConfig(c Config) error {
 	}
 
 	if c.Stack.BuildImage == "" {
-		return errors.New("stack.build-image is required")
+		targetBuildImages := len(c.Targets) > 0
+		for _, target := range c.Targets {
+			if target.BuildImage == "" {
+				targetBuildImages = false
+			}
+		}
+
+		if !targetBuildImages {
+			return errors.New("stack.build-image is required")
+		}
 	}
 
 	if c.Stack.RunImage == "" {
 		return errors.New("stack.run-image is required")
 	}
 
+	seenTargets := map[string]bool{}
+	for _, target := range c.Targets {
+		if target.OS != "linux" && target.OS != "windows" {
+			return errors.Errorf("invalid targets.os %s, only %s and %s are permitted",
+				style.Symbol(target.OS), style.Symbol("linux"), style.Symbol("windows"))
+		}
+
+		if target.Arch == "" {
+			return errors.Errorf("targets.arch is required for target with os %s", style.Symbol(target.OS))
+		}
+
+		if seenTargets[target.String()] {
+			return errors.Errorf("target %s is configured more than once", style.Symbol(target.String()))
+		}
+		seenTargets[target.String()] = true
+	}
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/pkg/dist"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -55,6 +56,14 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 [[order]]
 [[order.group]]
   id = "buildpack/1"
+
+[[targets]]
+  os = "linux"
+  arch = "arm64"
+  build-image = "example.com/build:arm64"
+
+[targets.lifecycle]
+  uri = "https://example.com/lifecycle-arm64.tgz"
 `), 0666))
 			})
 
@@ -77,6 +86,10 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, builderConfig.Buildpacks[2].ImageName, "")
 
 				h.AssertEq(t, builderConfig.Order[0].Group[0].ID, "buildpack/1")
+
+				h.AssertEq(t, builderConfig.Targets[0].String(), "linux/arm64")
+				h.AssertEq(t, builderConfig.Targets[0].BuildImage, "example.com/build:arm64")
+				h.AssertEq(t, builderConfig.Targets[0].Lifecycle.URI, "https://example.com/lifecycle-arm64.tgz")
 			})
 		})
 
@@ -193,5 +206,75 @@ uri = "noop-buildpack.tgz"
 				}}
 			h.AssertError(t, builder.ValidateConfig(config), "stack.run-image is required")
 		})
+
+		when("targets are configured", func() {
+			var linuxAMD64, linuxARM64 builder.TargetConfig
+
+			it.Before(func() {
+				linuxAMD64 = builder.TargetConfig{Target: dist.Target{OS: "linux", Arch: "amd64"}}
+				linuxARM64 = builder.TargetConfig{Target: dist.Target{OS: "linux", Arch: "arm64"}}
+			})
+
+			it("returns no error when every target has a build image", func() {
+				linuxAMD64.BuildImage = testBuildImage + "-amd64"
+				linuxARM64.BuildImage = testBuildImage + "-arm64"
+				config := builder.Config{
+					Stack: builder.StackConfig{
+						ID:       testID,
+						RunImage: testRunImage,
+					},
+					Targets: []builder.TargetConfig{linuxAMD64, linuxARM64},
+				}
+				h.AssertNil(t, builder.ValidateConfig(config))
+			})
+
+			it("returns error if a target has no build image", func() {
+				linuxAMD64.BuildImage = testBuildImage + "-amd64"
+				config := builder.Config{
+					Stack: builder.StackConfig{
+						ID:       testID,
+						RunImage: testRunImage,
+					},
+					Targets: []builder.TargetConfig{linuxAMD64, linuxARM64},
+				}
+				h.AssertError(t, builder.ValidateConfig(config), "stack.build-image is required")
+			})
+
+			it("returns error if a target has no arch", func() {
+				config := builder.Config{
+					Stack: builder.StackConfig{
+						ID:         testID,
+						BuildImage: testBuildImage,
+						RunImage:   testRunImage,
+					},
+					Targets: []builder.TargetConfig{{Target: dist.Target{OS: "linux"}}},
+				}
+				h.AssertError(t, builder.ValidateConfig(config), "targets.arch is required for target with os 'linux'")
+			})
+
+			it("returns error if a target has an invalid os", func() {
+				config := builder.Config{
+					Stack: builder.StackConfig{
+						ID:         testID,
+						BuildImage: testBuildImage,
+						RunImage:   testRunImage,
+					},
+					Targets: []builder.TargetConfig{{Target: dist.Target{OS: "darwin", Arch: "arm64"}}},
+				}
+				h.AssertError(t, builder.ValidateConfig(config), "invalid targets.os 'darwin'")
+			})
+
+			it("returns error if a target is repeated", func() {
+				config := builder.Config{
+					Stack: builder.StackConfig{
+						ID:         testID,
+						BuildImage: testBuildImage,
+						RunImage:   testRunImage,
+					},
+					Targets: []builder.TargetConfig{linuxAMD64, linuxAMD64},
+				}
+				h.AssertError(t, builder.ValidateConfig(config), "target 'linux/amd64' is configured more than once")
+			})
+		})
 	})
 }
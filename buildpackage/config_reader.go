@@ -19,6 +19,14 @@ type Config struct {
 	Buildpack    dist.BuildpackURI `toml:"buildpack"`
 	Dependencies []dist.ImageOrURI `toml:"dependencies"`
 	Platform     dist.Platform     `toml:"platform"`
+	Targets      []Target          `toml:"targets"`
+}
+
+// Target is a platform to package the buildpack for. When URI is set, it is used
+// instead of the buildpack URI, such as for buildpacks that contain native binaries.
+type Target struct {
+	dist.Target
+	URI string `toml:"uri,omitempty"`
 }
 
 func DefaultConfig() Config {
@@ -65,13 +73,18 @@ func (r *ConfigReader) Read(path string) (Config, error) {
 		return packageConfig, errors.Errorf("missing %s configuration", style.Symbol("buildpack.uri"))
 	}
 
-	if packageConfig.Platform.OS == "" {
+	if len(packageConfig.Targets) > 0 && packageConfig.Platform.OS != "" {
+		return packageConfig, errors.Errorf("%s cannot be used with %s", style.Symbol("platform.os"), style.Symbol("targets"))
+	}
+
+	if packageConfig.Platform.OS == "" && len(packageConfig.Targets) == 0 {
 		packageConfig.Platform.OS = defaultOS
 	}
 
-	if packageConfig.Platform.OS != "linux" && packageConfig.Platform.OS != "windows" {
-		return packageConfig, errors.Errorf("invalid %s configuration: only [%s, %s] is permitted, found %s",
-			style.Symbol("platform.os"), style.Symbol("linux"), style.Symbol("windows"), style.Symbol(packageConfig.Platform.OS))
+	if packageConfig.Platform.OS != "" {
+		if err := validateOS("platform.os", packageConfig.Platform.OS); err != nil {
+			return packageConfig, err
+		}
 	}
 
 	configDir, err := filepath.Abs(filepath.Dir(path))
@@ -83,6 +96,28 @@ func (r *ConfigReader) Read(path string) (Config, error) {
 		return packageConfig, err
 	}
 
+	seenTargets := map[string]bool{}
+	for _, target := range packageConfig.Targets {
+		if err := validateOS("targets.os", target.OS); err != nil {
+			return packageConfig, err
+		}
+
+		if target.Arch == "" {
+			return packageConfig, errors.Errorf("missing %s configuration for target with os %s", style.Symbol("targets.arch"), style.Symbol(target.OS))
+		}
+
+		if seenTargets[target.String()] {
+			return packageConfig, errors.Errorf("target %s is configured more than once", style.Symbol(target.String()))
+		}
+		seenTargets[target.String()] = true
+
+		if target.URI != "" {
+			if err := validateURI(target.URI, configDir); err != nil {
+				return packageConfig, err
+			}
+		}
+	}
+
 	for _, dep := range packageConfig.Dependencies {
 		if dep.URI != "" && dep.ImageName != "" {
 			return packageConfig, errors.Errorf(
@@ -102,6 +137,15 @@ func (r *ConfigReader) Read(path string) (Config, error) {
 	return packageConfig, nil
 }
 
+func validateOS(key, os string) error {
+	if os != "linux" && os != "windows" {
+		return errors.Errorf("invalid %s configuration: only [%s, %s] is permitted, found %s",
+			style.Symbol(key), style.Symbol("linux"), style.Symbol("windows"), style.Symbol(os))
+	}
+
+	return nil
+}
+
 func validateURI(uri, relativeBaseDir string) error {
 	locatorType, err := buildpack.GetLocatorType(uri, relativeBaseDir, nil)
 	if err != nil {
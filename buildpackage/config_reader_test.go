@@ -193,6 +193,85 @@ func testBuildpackageConfigReader(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNotNil(t, err)
 			h.AssertError(t, err, "missing 'buildpack.uri' configuration")
 		})
+		it("returns targets when configured", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(validTargetsPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			config, err := packageConfigReader.Read(configFile)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, config.Platform.OS, "")
+			h.AssertEq(t, len(config.Targets), 2)
+			h.AssertEq(t, config.Targets[0].String(), "linux/amd64")
+			h.AssertEq(t, config.Targets[0].URI, "")
+			h.AssertEq(t, config.Targets[1].String(), "linux/arm64/v8")
+			h.AssertEq(t, config.Targets[1].URI, "https://example.com/bp/a-arm64.tgz")
+		})
+
+		it("returns an error when targets are used with platform os", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(targetsWithPlatformPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertError(t, err, "'platform.os' cannot be used with 'targets'")
+		})
+
+		it("returns an error when target os is invalid", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(invalidTargetOSPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertError(t, err, "invalid 'targets.os' configuration")
+		})
+
+		it("returns an error when target arch is missing", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(missingTargetArchPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertError(t, err, "missing 'targets.arch' configuration for target with os 'linux'")
+		})
+
+		it("returns an error when a target is repeated", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(duplicateTargetsPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertError(t, err, "target 'linux/amd64' is configured more than once")
+		})
+
+		it("returns an error when target uri is invalid", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(invalidTargetURIPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertError(t, err, "invalid locator")
+			h.AssertError(t, err, "invalid/uri@version-is-invalid")
+		})
 	})
 }
 
@@ -278,3 +357,70 @@ const missingBuildpackPackageToml = `
 [[dependencies]]
 uri = "bp/b"
 `
+
+const validTargetsPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+
+[[targets]]
+os = "linux"
+arch = "arm64"
+variant = "v8"
+uri = "https://example.com/bp/a-arm64.tgz"
+`
+
+const targetsWithPlatformPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[platform]
+os = "linux"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+`
+
+const invalidTargetOSPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[[targets]]
+os = "some-incorrect-platform"
+arch = "amd64"
+`
+
+const missingTargetArchPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[[targets]]
+os = "linux"
+`
+
+const duplicateTargetsPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+`
+
+const invalidTargetURIPackageToml = `
+[buildpack]
+uri = "https://example.com/bp/a.tgz"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+uri = "invalid/uri@version-is-invalid"
+`
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"path/filepath"
+
+	dockerClient "github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -10,8 +14,10 @@ import (
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/config"
 	imagewriter "github.com/buildpacks/pack/internal/inspectimage/writer"
+	"github.com/buildpacks/pack/internal/signature"
 	"github.com/buildpacks/pack/internal/term"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/engine"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -77,6 +83,7 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	rootCmd.AddCommand(commands.NewBuildpackCommand(logger, cfg, packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.NewConfigCommand(logger, cfg, cfgPath, packClient))
 	rootCmd.AddCommand(commands.InspectImage(logger, imagewriter.NewFactory(), cfg, packClient))
+	rootCmd.AddCommand(commands.NewCacheCommand(logger, packClient))
 	rootCmd.AddCommand(commands.NewStackCommand(logger))
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewSBOMCommand(logger, cfg, packClient))
@@ -138,5 +145,23 @@ func initClient(logger logging.Logger, cfg config.Config) (*client.Client, error
 	if err != nil {
 		return nil, err
 	}
-	return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithDockerClient(dc))
+	packHome, err := config.PackHome()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting pack home")
+	}
+	opts := []client.Option{client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithDockerClient(dc), client.WithCacheUsageLog(filepath.Join(packHome, "cache-usage.json"))}
+	if len(cfg.VerifyPolicies) > 0 {
+		opts = append(opts, client.WithImageVerifier(signature.NewPolicyVerifier(cfg.VerifyPolicies, authn.DefaultKeychain)))
+	}
+	if cfg.ContainerEngine == config.PodmanContainerEngine {
+		// fall back to docker, so that commands not running containers, such as the one unsetting the container
+		// engine, don't fail when podman isn't available
+		podman, err := engine.NewPodman("", dockerClient.WithVersion(client.DockerAPIVersion))
+		if err != nil {
+			logger.Warnf("Using Docker as the container engine: creating podman client: %s", err)
+		} else {
+			opts = append(opts, client.WithContainerEngine(podman))
+		}
+	}
+	return client.NewClient(opts...)
 }
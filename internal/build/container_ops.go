@@ -1,33 +1,36 @@
 package build
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"runtime"
 
 	"github.com/BurntSushi/toml"
 	"github.com/buildpacks/lifecycle/platform"
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	darchive "github.com/docker/docker/pkg/archive"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/engine"
 )
 
-type ContainerOperation func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error
+type ContainerOperation func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error
 
 // CopyOut copies container directories to a handler function. The handler is responsible for closing the Reader.
 func CopyOut(handler func(closer io.ReadCloser) error, srcs ...string) ContainerOperation {
-	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
 		for _, src := range srcs {
 			reader, _, err := ctrClient.CopyFromContainer(ctx, containerID, src)
 			if err != nil {
@@ -44,6 +47,28 @@ func CopyOut(handler func(closer io.ReadCloser) error, srcs ...string) Container
 	}
 }
 
+// CopyOutFile copies a single container file to a handler function.
+func CopyOutFile(src string, handler func(reader io.Reader) error) ContainerOperation {
+	return CopyOut(func(reader io.ReadCloser) error {
+		defer reader.Close()
+
+		tr := tar.NewReader(reader)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return errors.Errorf("file %s not found in container", src)
+			}
+			if err != nil {
+				return err
+			}
+
+			if header.Typeflag == tar.TypeReg {
+				return handler(tr)
+			}
+		}
+	}, src)
+}
+
 func CopyOutTo(src, dest string) ContainerOperation {
 	return CopyOut(func(reader io.ReadCloser) error {
 		info := darchive.CopyInfo{
@@ -59,7 +84,7 @@ func CopyOutTo(src, dest string) ContainerOperation {
 // CopyDir copies a local directory (src) to the destination on the container while filtering files and changing it's UID/GID.
 // if includeRoot is set the UID/GID will be set on the dst directory.
 func CopyDir(src, dst string, uid, gid int, os string, includeRoot bool, fileFilter func(string) bool) ContainerOperation {
-	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
 		tarPath := dst
 		if os == "windows" {
 			tarPath = paths.WindowsToSlash(dst)
@@ -74,11 +99,71 @@ func CopyDir(src, dst string, uid, gid int, os string, includeRoot bool, fileFil
 		if os == "windows" {
 			return copyDirWindows(ctx, ctrClient, containerID, reader, dst, stdout, stderr)
 		}
-		return copyDir(ctx, ctrClient, containerID, reader)
+
+		if err := copyDir(ctx, ctrClient, containerID, reader); err != nil {
+			return err
+		}
+
+		return ensureOwnership(ctx, ctrClient, containerID, uid, gid, dst, includeRoot, stderr)
+	}
+}
+
+// ensureOwnership changes the ownership of the files copied to dst when the engine doesn't keep the ownership set in
+// the copied archive, as with rootless Podman. The files are changed from a container sharing the volumes of the
+// container, which must be mounted at dst.
+func ensureOwnership(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, uid, gid int, dst string, includeRoot bool, stderr io.Writer) error {
+	preserves, err := ctrClient.PreservesOwnership(ctx)
+	if err != nil {
+		return errors.Wrap(err, "checking ownership of copied files")
+	}
+	if preserves {
+		return nil
+	}
+
+	owner := fmt.Sprintf("%d:%d", uid, gid)
+	cmd := []string{"find", dst, "-mindepth", "1", "-exec", "chown", "-h", owner, "{}", "+"}
+	if includeRoot {
+		cmd = []string{"chown", "-R", "-h", owner, dst}
 	}
+
+	return runAsRoot(ctx, ctrClient, containerID, cmd, &dcontainer.HostConfig{VolumesFrom: []string{containerID}}, stderr)
 }
 
-func copyDir(ctx context.Context, ctrClient client.CommonAPIClient, containerID string, appReader io.Reader) error {
+// runAsRoot runs cmd as root in a container created from the image of the container with containerID.
+func runAsRoot(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, cmd []string, hostConfig *dcontainer.HostConfig, stderr io.Writer) error {
+	info, err := ctrClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	ctr, err := ctrClient.ContainerCreate(ctx,
+		&dcontainer.Config{
+			Image:      info.Image,
+			Entrypoint: []string{},
+			Cmd:        cmd,
+			WorkingDir: "/",
+			User:       "root",
+		},
+		hostConfig,
+		nil, nil, "",
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating ownership container")
+	}
+	defer ctrClient.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	return container.RunWithHandler(
+		ctx,
+		ctrClient,
+		ctr.ID,
+		container.DefaultHandler(
+			ioutil.Discard,
+			stderr,
+		),
+	)
+}
+
+func copyDir(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, appReader io.Reader) error {
 	var clientErr, err error
 
 	doneChan := make(chan interface{})
@@ -105,7 +190,7 @@ func copyDir(ctx context.Context, ctrClient client.CommonAPIClient, containerID
 // for Windows containers and does not work. Instead, we perform the copy from inside a container
 // using xcopy.
 // See: https://github.com/moby/moby/issues/40771
-func copyDirWindows(ctx context.Context, ctrClient client.CommonAPIClient, containerID string, reader io.Reader, dst string, stdout, stderr io.Writer) error {
+func copyDirWindows(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, reader io.Reader, dst string, stdout, stderr io.Writer) error {
 	info, err := ctrClient.ContainerInspect(ctx, containerID)
 	if err != nil {
 		return err
@@ -174,7 +259,7 @@ func findMount(info types.ContainerJSON, dst string) (types.MountPoint, error) {
 
 // WriteProjectMetadata
 func WriteProjectMetadata(p string, metadata platform.ProjectMetadata, os string) ContainerOperation {
-	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
 		buf := &bytes.Buffer{}
 		err := toml.NewEncoder(buf).Encode(metadata)
 		if err != nil {
@@ -201,9 +286,65 @@ func WriteProjectMetadata(p string, metadata platform.ProjectMetadata, os string
 	}
 }
 
+// CopySecrets copies each secret to <dir>/<id>, readable only by the build user with uid and gid. Secrets bind mounted
+// from the host would keep the owner and mode of the host file, which the build user often can't read.
+// The secrets are copied into the filesystem of the container, so they are removed along with it: engines don't copy
+// into tmpfs mounts, which only exist once the container runs. When the engine doesn't keep the ownership set in the
+// copied archive, as with rootless Podman, it gives the secrets to the user of the container instead, which the
+// phases reading secrets run as.
+func CopySecrets(dir string, uid, gid int, secrets ...Secret) ContainerOperation {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		preserves, err := ctrClient.PreservesOwnership(ctx)
+		if err != nil {
+			return errors.Wrap(err, "checking ownership of copied files")
+		}
+
+		var dirMode, fileMode int64 = 0500, 0400
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir,
+			Mode:     dirMode,
+			Uid:      uid,
+			Gid:      gid,
+			ModTime:  archive.NormalizedDateTime,
+		}); err != nil {
+			return errors.Wrap(err, "writing secrets")
+		}
+
+		for _, secret := range secrets {
+			contents, err := ioutil.ReadFile(secret.Source)
+			if err != nil {
+				return errors.Wrapf(err, "reading secret %s", style.Symbol(secret.ID))
+			}
+
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     path.Join(dir, secret.ID),
+				Size:     int64(len(contents)),
+				Mode:     fileMode,
+				Uid:      uid,
+				Gid:      gid,
+				ModTime:  archive.NormalizedDateTime,
+			}); err != nil {
+				return errors.Wrapf(err, "writing secret %s", style.Symbol(secret.ID))
+			}
+			if _, err := tw.Write(contents); err != nil {
+				return errors.Wrapf(err, "writing secret %s", style.Symbol(secret.ID))
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return errors.Wrap(err, "writing secrets")
+		}
+
+		return ctrClient.CopyToContainer(ctx, containerID, "/", buf, types.CopyToContainerOptions{CopyUIDGID: !preserves})
+	}
+}
+
 // WriteStackToml writes a `stack.toml` based on the StackMetadata provided to the destination path.
 func WriteStackToml(dstPath string, stack builder.StackMetadata, os string) ContainerOperation {
-	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
 		buf := &bytes.Buffer{}
 		err := toml.NewEncoder(buf).Encode(stack)
 		if err != nil {
@@ -252,10 +393,12 @@ func createReader(src, dst string, uid, gid int, includeRoot bool, fileFilter fu
 // When UID/GID are 0 it grants explicit full access to BUILTIN\Administrators and any other UID/GID grants full access to BUILTIN\Users
 // Changing permissions on volumes through stopped containers does not work on Docker for Windows so we start the container and make change using icacls
 // See: https://github.com/moby/moby/issues/40771
+// On Linux, volumes are only changed when the engine doesn't preserve ownership, as with rootless Podman, which creates
+// them owned by root. They are then changed to be owned by UID/GID.
 func EnsureVolumeAccess(uid, gid int, os string, volumeNames ...string) ContainerOperation {
-	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
 		if os != "windows" {
-			return nil
+			return ensureVolumeOwnership(ctx, ctrClient, containerID, uid, gid, volumeNames, stderr)
 		}
 
 		containerInfo, err := ctrClient.ContainerInspect(ctx, containerID)
@@ -313,3 +456,45 @@ func EnsureVolumeAccess(uid, gid int, os string, volumeNames ...string) Containe
 		)
 	}
 }
+
+// EnsureBindAccess grants the UID/GID-based user access to host directories bind mounted into containers, such as bind
+// caches, which are owned by the user running pack rather than by the user of the builder.
+// On Linux, they are changed to be owned by UID/GID from a container running as root. See EnsureVolumeAccess for Windows.
+func EnsureBindAccess(uid, gid int, os string, dirs ...string) ContainerOperation {
+	if os == "windows" {
+		return EnsureVolumeAccess(uid, gid, os, dirs...)
+	}
+
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		return chownMounts(ctx, ctrClient, containerID, uid, gid, dirs, stderr)
+	}
+}
+
+func ensureVolumeOwnership(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, uid, gid int, volumeNames []string, stderr io.Writer) error {
+	preserves, err := ctrClient.PreservesOwnership(ctx)
+	if err != nil {
+		return errors.Wrap(err, "checking ownership of volumes")
+	}
+	if preserves {
+		return nil
+	}
+
+	return chownMounts(ctx, ctrClient, containerID, uid, gid, volumeNames, stderr)
+}
+
+// chownMounts changes the owner of volumes or host directories (sources) to uid and gid, from a container running as root.
+func chownMounts(ctx context.Context, ctrClient engine.ContainerEngine, containerID string, uid, gid int, sources []string, stderr io.Writer) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	cmd := []string{"chown", "-R", "-h", fmt.Sprintf("%d:%d", uid, gid)}
+	var binds []string
+	for i, source := range sources {
+		containerPath := fmt.Sprintf("/volume-mnt-%d", i)
+		binds = append(binds, fmt.Sprintf("%s:%s", source, containerPath))
+		cmd = append(cmd, containerPath)
+	}
+
+	return runAsRoot(ctx, ctrClient, containerID, cmd, &dcontainer.HostConfig{Binds: binds}, stderr)
+}
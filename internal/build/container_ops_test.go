@@ -26,6 +26,7 @@ import (
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/pkg/engine"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -38,9 +39,9 @@ func TestContainerOperations(t *testing.T) {
 
 	h.RequireDocker(t)
 
-	var err error
-	ctrClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 	h.AssertNil(t, err)
+	ctrClient = engine.NewDocker(dockerClient)
 
 	spec.Run(t, "container-ops", testContainerOps, spec.Report(report.Terminal{}), spec.Sequential())
 }
@@ -305,6 +306,39 @@ drwsrwsrwt    2 123      456 (.*) some-vol
 		})
 	})
 
+	when("#CopySecrets", func() {
+		it("writes the secrets readable by the build user only", func() {
+			if osType == "windows" {
+				t.Skip("secrets are not supported for Windows builders")
+			}
+
+			tmpDir, err := ioutil.TempDir("", "secrets")
+			h.AssertNil(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			secretPath := filepath.Join(tmpDir, "npmrc")
+			h.AssertNil(t, ioutil.WriteFile(secretPath, []byte("some-token"), 0600))
+
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, "/some-vol", osType, "ls", "-aln", "/run/secrets")
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			copySecretsOp := build.CopySecrets("/run/secrets", 123, 456, build.Secret{ID: "npm", Source: secretPath})
+
+			var outBuf, errBuf bytes.Buffer
+			err = copySecretsOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf)
+			h.AssertNil(t, err)
+
+			err = container.RunWithHandler(ctx, ctrClient, ctr.ID, container.DefaultHandler(&outBuf, &errBuf))
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, errBuf.String(), "")
+			h.AssertContainsMatch(t, outBuf.String(), `dr-x------\s+2 123\s+456 .* \.`)
+			h.AssertContainsMatch(t, outBuf.String(), `-r--------\s+1 123\s+456\s+10 .* npm`)
+		})
+	})
+
 	when("#WriteStackToml", func() {
 		it("writes file", func() {
 			containerDir := "/layers-vol"
@@ -3,12 +3,15 @@ package build
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"strconv"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/buildpacks/lifecycle/auth"
-	"github.com/docker/docker/client"
+	"github.com/buildpacks/lifecycle/buildpack"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
@@ -17,6 +20,10 @@ import (
 	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
+	pcache "github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -27,16 +34,19 @@ const (
 
 type LifecycleExecution struct {
 	logger       logging.Logger
-	docker       client.CommonAPIClient
+	engine       engine.ContainerEngine
 	platformAPI  *api.Version
 	layersVolume string
 	appVolume    string
 	os           string
 	mountPaths   mountPaths
 	opts         LifecycleOptions
+
+	failedPhase     string
+	failedContainer string
 }
 
-func NewLifecycleExecution(logger logging.Logger, docker client.CommonAPIClient, opts LifecycleOptions) (*LifecycleExecution, error) {
+func NewLifecycleExecution(logger logging.Logger, containerEngine engine.ContainerEngine, opts LifecycleOptions) (*LifecycleExecution, error) {
 	latestSupportedPlatformAPI, err := findLatestSupported(append(
 		opts.Builder.LifecycleDescriptor().APIs.Platform.Deprecated,
 		opts.Builder.LifecycleDescriptor().APIs.Platform.Supported...,
@@ -52,7 +62,7 @@ func NewLifecycleExecution(logger logging.Logger, docker client.CommonAPIClient,
 
 	exec := &LifecycleExecution{
 		logger:       logger,
-		docker:       docker,
+		engine:       containerEngine,
 		layersVolume: paths.FilterReservedNames("pack-layers-" + randString(10)),
 		appVolume:    paths.FilterReservedNames("pack-app-" + randString(10)),
 		platformAPI:  latestSupportedPlatformAPI,
@@ -123,17 +133,33 @@ func (l *LifecycleExecution) PrevImageName() string {
 func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) error {
 	phaseFactory := phaseFactoryCreator(l)
 	var buildCache Cache
-	if l.opts.CacheImage != "" {
-		cacheImage, err := name.ParseReference(l.opts.CacheImage, name.WeakValidation)
+	switch {
+	case l.opts.CacheImage != "" || l.opts.Cache.Build.Format == pcache.CacheImage:
+		cacheImageName := l.opts.CacheImage
+		if cacheImageName == "" {
+			cacheImageName = l.opts.Cache.Build.Source
+		}
+		cacheImage, err := name.ParseReference(cacheImageName, name.WeakValidation)
 		if err != nil {
 			return fmt.Errorf("invalid cache image name: %s", err)
 		}
-		buildCache = cache.NewImageCache(cacheImage, l.docker)
-	} else {
-		buildCache = cache.NewVolumeCache(l.opts.Image, "build", l.docker)
+		buildCache = cache.NewImageCache(cacheImage, l.engine)
+	case l.opts.Cache.Build.Format == pcache.CacheBind:
+		bindCache, err := cache.NewBindCache(l.opts.Cache.Build.Source)
+		if err != nil {
+			return err
+		}
+		buildCache = bindCache
+	default:
+		buildCache = cache.NewVolumeCache(l.opts.Image, "build", l.engine)
 	}
 
 	l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	if l.opts.DetectOnly {
+		// detecting has no side effects, the cache isn't cleared
+		return l.detectOnly(ctx, buildCache, phaseFactory)
+	}
+
 	if l.opts.ClearCache {
 		if err := buildCache.Clear(ctx); err != nil {
 			return errors.Wrap(err, "clearing build cache")
@@ -141,7 +167,16 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 		l.logger.Debugf("Build cache %s cleared", style.Symbol(buildCache.Name()))
 	}
 
-	launchCache := cache.NewVolumeCache(l.opts.Image, "launch", l.docker)
+	var launchCache Cache
+	if l.opts.Cache.Launch.Format == pcache.CacheBind {
+		bindCache, err := cache.NewBindCache(l.opts.Cache.Launch.Source)
+		if err != nil {
+			return err
+		}
+		launchCache = bindCache
+	} else {
+		launchCache = cache.NewVolumeCache(l.opts.Image, "launch", l.engine)
+	}
 
 	if !l.opts.UseCreator {
 		if l.platformAPI.LessThan("0.7") {
@@ -185,17 +220,44 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 	return l.Create(ctx, l.opts.Publish, l.opts.DockerHost, l.opts.ClearCache, l.opts.RunImage, l.opts.Image.String(), l.opts.Network, buildCache, launchCache, l.opts.AdditionalTags, l.opts.Volumes, phaseFactory)
 }
 
+// detectOnly runs the phases required to select the buildpacks of the app, without building or exporting it.
+func (l *LifecycleExecution) detectOnly(ctx context.Context, buildCache Cache, phaseFactory PhaseFactory) error {
+	if !l.platformAPI.LessThan("0.7") {
+		l.logger.Info(style.Step("ANALYZING"))
+		if err := l.Analyze(ctx, l.opts.Image.String(), l.opts.Network, l.opts.Publish, l.opts.DockerHost, l.opts.ClearCache, l.opts.RunImage, l.opts.AdditionalTags, buildCache, phaseFactory); err != nil {
+			return err
+		}
+	}
+
+	l.logger.Info(style.Step("DETECTING"))
+	return l.Detect(ctx, l.opts.Network, l.opts.Volumes, phaseFactory)
+}
+
 func (l *LifecycleExecution) Cleanup() error {
+	if l.failedContainer != "" {
+		l.logger.Warnf("Kept the container %s of the failed %s phase, and the volumes %s and %s", style.Symbol(l.failedContainer), style.Symbol(l.failedPhase), style.Symbol(l.layersVolume), style.Symbol(l.appVolume))
+		l.logger.Infof("To start a shell with the environment of the failed phase, run: %s", style.Symbol("pack build debug "+l.failedContainer))
+		l.logger.Infof("To remove them, run: %s", style.Symbol("pack build debug --cleanup "+l.failedContainer))
+		l.logger.Infof("or: %s", style.Symbol(fmt.Sprintf("%[1]s rm %[2]s && %[1]s volume rm %[3]s %[4]s", l.engine.Name(), l.failedContainer, l.layersVolume, l.appVolume)))
+		return nil
+	}
+
 	var reterr error
-	if err := l.docker.VolumeRemove(context.Background(), l.layersVolume, true); err != nil {
+	if err := l.engine.VolumeRemove(context.Background(), l.layersVolume, true); err != nil {
 		reterr = errors.Wrapf(err, "failed to clean up layers volume %s", l.layersVolume)
 	}
-	if err := l.docker.VolumeRemove(context.Background(), l.appVolume, true); err != nil {
+	if err := l.engine.VolumeRemove(context.Background(), l.appVolume, true); err != nil {
 		reterr = errors.Wrapf(err, "failed to clean up app volume %s", l.appVolume)
 	}
 	return reterr
 }
 
+// keepFailedPhase records the container of a failed phase, whose volumes are then kept by Cleanup.
+func (l *LifecycleExecution) keepFailedPhase(phase, containerID string) {
+	l.failedPhase = phase
+	l.failedContainer = containerID
+}
+
 func (l *LifecycleExecution) Create(ctx context.Context, publish bool, dockerHost string, clearCache bool, runImage, repoName, networkMode string, buildCache, launchCache Cache, additionalTags, volumes []string, phaseFactory PhaseFactory) error {
 	flags := addTags([]string{
 		"-app", l.mountPaths.appDir(),
@@ -246,7 +308,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, publish bool, dockerHos
 	case cache.Image:
 		flags = append(flags, "-cache-image", buildCache.Name())
 		cacheOpts = WithBinds(volumes...)
-	case cache.Volume:
+	case cache.Volume, cache.Bind:
 		cacheOpts = WithBinds(append(volumes, fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))...)
 	}
 
@@ -255,6 +317,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, publish bool, dockerHos
 		WithArgs(repoName),
 		WithNetwork(networkMode),
 		cacheOpts,
+		l.withBindCacheAccess(buildCache),
 		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os)),
 		WithContainerOperations(CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter)),
 		If(l.opts.SBOMDestinationDir != "", WithPostContainerRunOperations(
@@ -263,6 +326,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, publish bool, dockerHos
 		If(l.opts.Interactive, WithPostContainerRunOperations(
 			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
 			CopyOut(l.opts.Termui.ReadLayers, l.mountPaths.layersDir(), l.mountPaths.appDir()))),
+		If(l.opts.EventHandler != nil, WithPostContainerRunOperations(l.emitDetectedBuildpacks())),
 	}
 
 	if publish {
@@ -277,6 +341,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, publish bool, dockerHos
 			WithDaemonAccess(dockerHost),
 			WithFlags("-daemon", "-launch-cache", l.mountPaths.launchCacheDir()),
 			WithBinds(fmt.Sprintf("%s:%s", launchCache.Name(), l.mountPaths.launchCacheDir())),
+			l.withBindCacheAccess(launchCache),
 		)
 	}
 
@@ -301,6 +366,9 @@ func (l *LifecycleExecution) Detect(ctx context.Context, networkMode string, vol
 			CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter),
 		),
 		WithFlags(flags...),
+		If(l.opts.EventHandler != nil, WithPostContainerRunOperations(l.emitDetectedBuildpacks())),
+		If(l.opts.DetectHandler != nil, WithPostContainerRunOperations(l.readDetectResult())),
+		If(len(l.opts.Secrets) > 0, WithSecrets(l.mountPaths.secretsDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.opts.Secrets...)),
 	)
 
 	detect := phaseFactory.New(configProvider)
@@ -314,8 +382,8 @@ func (l *LifecycleExecution) Restore(ctx context.Context, networkMode string, bu
 	switch buildCache.Type() {
 	case cache.Image:
 		flagsOpt = WithFlags("-cache-image", buildCache.Name())
-	case cache.Volume:
-		cacheOpt = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
+	case cache.Volume, cache.Bind:
+		cacheOpt = l.withCacheBind(buildCache)
 	}
 	if l.opts.GID >= overrideGID {
 		flagsOpt = WithFlags("-gid", strconv.Itoa(l.opts.GID))
@@ -372,9 +440,9 @@ func (l *LifecycleExecution) newAnalyze(repoName, networkMode string, publish bo
 		if !clearCache {
 			flagsOpt = WithFlags("-cache-image", buildCache.Name())
 		}
-	case cache.Volume:
+	case cache.Volume, cache.Bind:
 		if platformAPILessThan07 {
-			cacheOpt = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
+			cacheOpt = l.withCacheBind(buildCache)
 		}
 	}
 
@@ -483,6 +551,7 @@ func (l *LifecycleExecution) Build(ctx context.Context, networkMode string, volu
 		WithNetwork(networkMode),
 		WithBinds(volumes...),
 		WithFlags(flags...),
+		If(len(l.opts.Secrets) > 0, WithSecrets(l.mountPaths.secretsDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.opts.Secrets...)),
 	)
 
 	build := phaseFactory.New(configProvider)
@@ -524,8 +593,8 @@ func (l *LifecycleExecution) newExport(repoName, runImage string, publish bool,
 	switch buildCache.Type() {
 	case cache.Image:
 		flags = append(flags, "-cache-image", buildCache.Name())
-	case cache.Volume:
-		cacheOpt = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
+	case cache.Volume, cache.Bind:
+		cacheOpt = l.withCacheBind(buildCache)
 	}
 
 	opts := []PhaseConfigProviderOperation{
@@ -569,6 +638,7 @@ func (l *LifecycleExecution) newExport(repoName, runImage string, publish bool,
 			WithDaemonAccess(dockerHost),
 			WithFlags("-daemon", "-launch-cache", l.mountPaths.launchCacheDir()),
 			WithBinds(fmt.Sprintf("%s:%s", launchCache.Name(), l.mountPaths.launchCacheDir())),
+			l.withBindCacheAccess(launchCache),
 		)
 	}
 
@@ -584,6 +654,90 @@ func (l *LifecycleExecution) Export(ctx context.Context, repoName, runImage stri
 	return export.Run(ctx)
 }
 
+// emitDetectedBuildpacks reads the group selected during detection and emits an event for each of its buildpacks.
+// Failing to read the group is not fatal to the build.
+func (l *LifecycleExecution) emitDetectedBuildpacks() ContainerOperation {
+	readGroup := CopyOutFile(l.mountPaths.groupPath(), func(reader io.Reader) error {
+		var group buildpack.Group
+		if _, err := toml.NewDecoder(reader).Decode(&group); err != nil {
+			return errors.Wrap(err, "decoding group")
+		}
+
+		for _, bp := range group.Group {
+			l.emit(events.Event{
+				Type:      events.BuildpackDetected,
+				Buildpack: &dist.BuildpackInfo{ID: bp.ID, Version: bp.Version, Homepage: bp.Homepage},
+			})
+		}
+		return nil
+	})
+
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		if err := readGroup(ctrClient, ctx, containerID, stdout, stderr); err != nil {
+			l.logger.Debugf("Unable to read detected buildpacks: %s", err)
+		}
+		return nil
+	}
+}
+
+// readDetectResult reads the group and the build plan resolved during detection and passes them to the detect handler.
+func (l *LifecycleExecution) readDetectResult() ContainerOperation {
+	var result DetectResult
+	readGroup := CopyOutFile(l.mountPaths.groupPath(), func(reader io.Reader) error {
+		_, err := toml.NewDecoder(reader).Decode(&result.Group)
+		return errors.Wrap(err, "decoding group")
+	})
+	readPlan := CopyOutFile(l.mountPaths.planPath(), func(reader io.Reader) error {
+		_, err := toml.NewDecoder(reader).Decode(&result.Plan)
+		return errors.Wrap(err, "decoding build plan")
+	})
+
+	return func(ctrClient engine.ContainerEngine, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		if err := readGroup(ctrClient, ctx, containerID, stdout, stderr); err != nil {
+			return errors.Wrap(err, "reading detected group")
+		}
+		if err := readPlan(ctrClient, ctx, containerID, stdout, stderr); err != nil {
+			return errors.Wrap(err, "reading build plan")
+		}
+
+		l.opts.DetectHandler(result)
+		return nil
+	}
+}
+
+func (l *LifecycleExecution) emit(event events.Event) {
+	if l.opts.EventHandler == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	l.opts.EventHandler(event)
+}
+
+// withCacheBind mounts the cache at the cache directory of the lifecycle.
+func (l *LifecycleExecution) withCacheBind(buildCache Cache) PhaseConfigProviderOperation {
+	binds := WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
+	access := l.withBindCacheAccess(buildCache)
+	return func(provider *PhaseConfigProvider) {
+		binds(provider)
+		access(provider)
+	}
+}
+
+// withBindCacheAccess gives the host directories of bind caches to the build user before the container runs, as the
+// lifecycle writes to them without running as root.
+func (l *LifecycleExecution) withBindCacheAccess(caches ...Cache) PhaseConfigProviderOperation {
+	var dirs []string
+	for _, c := range caches {
+		if c.Type() == cache.Bind {
+			dirs = append(dirs, c.Name())
+		}
+	}
+	return If(len(dirs) > 0, WithContainerOperations(EnsureBindAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, dirs...)))
+}
+
 func (l *LifecycleExecution) withLogLevel(args ...string) []string {
 	if l.logger.IsVerbose() {
 		return append([]string{"-log-level", "debug"}, args...)
@@ -26,6 +26,9 @@ import (
 
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/build/fakes"
+	pcache "github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -109,7 +112,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakeBuilder      *fakes.FakeBuilder
 			outBuf           bytes.Buffer
 			logger           *logging.LogWithWriters
-			docker           *client.Client
+			docker           *engine.Docker
 			fakePhaseFactory *fakes.FakePhaseFactory
 			fakeTermui       *fakes.FakeTermui
 		)
@@ -124,8 +127,9 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakeBuilder, err = fakes.NewFakeBuilder(fakes.WithSupportedPlatformAPIs([]*api.Version{api.MustParse("0.3")}))
 			h.AssertNil(t, err)
 			logger = logging.NewLogWithWriters(&outBuf, &outBuf)
-			docker, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 			h.AssertNil(t, err)
+			docker = engine.NewDocker(dockerClient)
 			fakePhaseFactory = fakes.NewFakePhaseFactory()
 		})
 
@@ -258,6 +262,73 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("detect only", func() {
+				for _, tc := range []struct {
+					platformAPI    string
+					expectedPhases []string
+				}{
+					{platformAPI: "0.3", expectedPhases: []string{"detector"}},
+					{platformAPI: "0.7", expectedPhases: []string{"analyzer", "detector"}},
+				} {
+					tc := tc
+					it(fmt.Sprintf("only calls the phases required for detection (platform %s)", tc.platformAPI), func() {
+						fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithSupportedPlatformAPIs([]*api.Version{api.MustParse(tc.platformAPI)}))
+						h.AssertNil(t, err)
+
+						opts := build.LifecycleOptions{
+							RunImage:      "test",
+							Image:         imageName,
+							Builder:       fakeBuilder,
+							Termui:        fakeTermui,
+							DetectOnly:    true,
+							DetectHandler: func(build.DetectResult) {},
+						}
+
+						lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+						h.AssertNil(t, err)
+
+						err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+							return fakePhaseFactory
+						})
+						h.AssertNil(t, err)
+
+						h.AssertEq(t, len(fakePhaseFactory.NewCalledWithProvider), len(tc.expectedPhases))
+						for i, entry := range fakePhaseFactory.NewCalledWithProvider {
+							h.AssertEq(t, entry.Name(), tc.expectedPhases[i])
+						}
+					})
+				}
+
+				it("doesn't clear the cache", func() {
+					cacheDir, err := ioutil.TempDir("", "bind-cache")
+					h.AssertNil(t, err)
+					defer os.RemoveAll(cacheDir)
+
+					opts := build.LifecycleOptions{
+						RunImage:      "test",
+						Image:         imageName,
+						Builder:       fakeBuilder,
+						Termui:        fakeTermui,
+						ClearCache:    true,
+						Cache:         pcache.CacheOpts{Build: pcache.CacheInfo{Format: pcache.CacheBind, Source: cacheDir}},
+						DetectOnly:    true,
+						DetectHandler: func(build.DetectResult) {},
+					}
+					h.AssertNil(t, ioutil.WriteFile(filepath.Join(cacheDir, "committed"), []byte{}, 0600))
+
+					lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+					h.AssertNil(t, err)
+
+					err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+						return fakePhaseFactory
+					})
+					h.AssertNil(t, err)
+
+					_, err = os.Stat(filepath.Join(cacheDir, "committed"))
+					h.AssertNil(t, err)
+				})
+			})
+
 			it("succeeds", func() {
 				opts := build.LifecycleOptions{
 					Publish:      false,
@@ -370,6 +441,43 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakeLaunchCache.ReturnForName = "some-launch-cache"
 		})
 
+		when("using bind caches", func() {
+			it.Before(func() {
+				fakeBuildCache.ReturnForType = cache.Bind
+				fakeBuildCache.ReturnForName = "/some/build-cache-dir"
+				fakeLaunchCache.ReturnForType = cache.Bind
+				fakeLaunchCache.ReturnForName = "/some/launch-cache-dir"
+			})
+
+			it("configures the phase with the cache directories bound", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Create(context.Background(), false, "", false, "test", "test", "test", fakeBuildCache, fakeLaunchCache, []string{}, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/some/build-cache-dir:/cache", "/some/launch-cache-dir:/launch-cache")
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "-cache-image")
+			})
+
+			it("gives the cache directories to the build user", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Create(context.Background(), false, "", false, "test", "test", "test", fakeBuildCache, fakeLaunchCache, []string{}, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				containerOps := configProvider.ContainerOps()
+				h.AssertFunctionName(t, containerOps[0], "EnsureBindAccess")
+				h.AssertFunctionName(t, containerOps[len(containerOps)-1], "EnsureBindAccess")
+			})
+		})
+
 		it("creates a phase and then run it", func() {
 			lifecycle := newTestLifecycleExec(t, false)
 			fakePhase := &fakes.FakePhase{}
@@ -1119,6 +1227,70 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertFunctionName(t, configProvider.ContainerOps()[0], "EnsureVolumeAccess")
 			h.AssertFunctionName(t, configProvider.ContainerOps()[1], "CopyDir")
 		})
+
+		when("secrets are provided", func() {
+			it("copies the secrets into the container", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "npm", Source: "/some/npmrc"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				containerOps := configProvider.ContainerOps()
+				h.AssertFunctionName(t, containerOps[len(containerOps)-1], "CopySecrets")
+				for _, bind := range configProvider.HostConfig().Binds {
+					h.AssertNotContains(t, bind, "/run/secrets")
+				}
+			})
+		})
+
+		when("an event handler is provided", func() {
+			it("reads the detected group after the phase runs", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.EventHandler = func(events.Event) {}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 1)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "emitDetectedBuildpacks")
+			})
+		})
+
+		when("a detect handler is provided", func() {
+			it("reads the detect result after the phase runs", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.DetectHandler = func(build.DetectResult) {}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 1)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "readDetectResult")
+			})
+		})
+
+		when("no event handler is provided", func() {
+			it("does not read the detected group", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 0)
+			})
+		})
 	})
 
 	when("#Analyze", func() {
@@ -1729,6 +1901,37 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakeCache.ReturnForName = "some-cache"
 			fakeCache.ReturnForType = cache.Volume
 		})
+		when("using a bind cache", func() {
+			it("configures the phase with the cache directory bound", func() {
+				fakeCache.ReturnForType = cache.Bind
+				fakeCache.ReturnForName = "/some/build-cache-dir"
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Restore(context.Background(), "test", fakeCache, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/some/build-cache-dir:/cache")
+			})
+
+			it("gives the cache directory to the build user", func() {
+				fakeCache.ReturnForType = cache.Bind
+				fakeCache.ReturnForName = "/some/build-cache-dir"
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Restore(context.Background(), "test", fakeCache, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "EnsureBindAccess")
+			})
+		})
 		it("runs the phase with the lifecycle image", func() {
 			lifecycle := newTestLifecycleExec(t, true, func(options *build.LifecycleOptions) {
 				options.LifecycleImage = "some-lifecycle-image"
@@ -1974,6 +2177,25 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("secrets are provided", func() {
+			it("copies the secrets into the container", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "npm", Source: "/some/npmrc"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				containerOps := configProvider.ContainerOps()
+				h.AssertFunctionName(t, containerOps[len(containerOps)-1], "CopySecrets")
+				for _, bind := range configProvider.HostConfig().Binds {
+					h.AssertNotContains(t, bind, "/run/secrets")
+				}
+			})
+		})
 	})
 
 	when("#Export", func() {
@@ -1992,6 +2214,64 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakeLaunchCache.ReturnForName = "some-launch-cache"
 		})
 
+		when("using bind caches", func() {
+			it.Before(func() {
+				fakeBuildCache.ReturnForType = cache.Bind
+				fakeBuildCache.ReturnForName = "/some/build-cache-dir"
+				fakeLaunchCache.ReturnForType = cache.Bind
+				fakeLaunchCache.ReturnForName = "/some/launch-cache-dir"
+			})
+
+			it("configures the phase with the cache directories bound", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Export(context.Background(), "test", "test", false, "", "test", fakeBuildCache, fakeLaunchCache, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/some/build-cache-dir:/cache", "/some/launch-cache-dir:/launch-cache")
+			})
+
+			it("gives the cache directories to the build user", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Export(context.Background(), "test", "test", false, "", "test", fakeBuildCache, fakeLaunchCache, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				containerOps := configProvider.ContainerOps()
+				h.AssertFunctionName(t, containerOps[0], "EnsureBindAccess")
+				h.AssertFunctionName(t, containerOps[len(containerOps)-1], "EnsureBindAccess")
+			})
+		})
+
+		when("secrets are provided", func() {
+			it("doesn't copy them", func() {
+				withoutSecrets := fakes.NewFakePhaseFactory()
+				err := newTestLifecycleExec(t, false).Export(context.Background(), "test", "test", false, "", "test", fakeBuildCache, fakeLaunchCache, []string{}, withoutSecrets)
+				h.AssertNil(t, err)
+
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "npm", Source: "/some/npmrc"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err = lifecycle.Export(context.Background(), "test", "test", false, "", "test", fakeBuildCache, fakeLaunchCache, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[0]
+				h.AssertEq(t, len(configProvider.ContainerOps()), len(withoutSecrets.NewCalledWithProvider[0].ContainerOps()))
+				for _, bind := range configProvider.HostConfig().Binds {
+					h.AssertNotContains(t, bind, "/run/secrets")
+				}
+			})
+		})
+
 		it("creates a phase and then runs it", func() {
 			lifecycle := newTestLifecycleExec(t, false)
 			fakePhase := &fakes.FakePhase{}
@@ -2622,8 +2902,9 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 }
 
 func newTestLifecycleExecErr(t *testing.T, logVerbose bool, ops ...func(*build.LifecycleOptions)) (*build.LifecycleExecution, error) {
-	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 	h.AssertNil(t, err)
+	docker := engine.NewDocker(dockerClient)
 
 	var outBuf bytes.Buffer
 	logger := logging.NewLogWithWriters(&outBuf, &outBuf)
@@ -8,13 +8,17 @@ import (
 
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/lifecycle/api"
+	"github.com/buildpacks/lifecycle/buildpack"
 	"github.com/buildpacks/lifecycle/platform"
-	"github.com/docker/docker/client"
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/container"
+	pcache "github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
@@ -41,7 +45,7 @@ type Builder interface {
 
 type LifecycleExecutor struct {
 	logger logging.Logger
-	docker client.CommonAPIClient
+	engine engine.ContainerEngine
 }
 
 type Cache interface {
@@ -77,6 +81,7 @@ type LifecycleOptions struct {
 	Termui             Termui
 	DockerHost         string
 	CacheImage         string
+	Cache              pcache.CacheOpts
 	HTTPProxy          string
 	HTTPSProxy         string
 	NoProxy            string
@@ -89,14 +94,55 @@ type LifecycleOptions struct {
 	GID                int
 	PreviousImage      string
 	SBOMDestinationDir string
+	EventHandler       events.Handler
+	MeasureLayers      bool
+	DetectOnly         bool
+	DetectHandler      func(DetectResult)
+	KeepOnFailure      bool
+	Secrets            []Secret
+	Resources          dcontainer.Resources
+	PhaseTimeouts      map[string]time.Duration
 }
 
-func NewLifecycleExecutor(logger logging.Logger, docker client.CommonAPIClient) *LifecycleExecutor {
-	return &LifecycleExecutor{logger: logger, docker: docker}
+// PhaseNames are the names of the lifecycle phases, by which LifecycleOptions.PhaseTimeouts are keyed.
+var PhaseNames = []string{"analyze", "detect", "restore", "build", "export", "create"}
+
+// lifecyclePhases maps the lifecycle binaries to the names of the phases they run.
+var lifecyclePhases = map[string]string{
+	"analyzer": "analyze",
+	"detector": "detect",
+	"restorer": "restore",
+	"builder":  "build",
+	"exporter": "export",
+	"creator":  "create",
+}
+
+// phaseName returns the name of the phase run by the lifecycle binary.
+func phaseName(binary string) string {
+	if name, ok := lifecyclePhases[binary]; ok {
+		return name
+	}
+	return binary
+}
+
+// DetectResult is the group of buildpacks selected during detection and the build plan they resolved.
+type DetectResult struct {
+	Group buildpack.Group
+	Plan  platform.BuildPlan
+}
+
+// Secret is a file copied into the detect and build phases only, at a path named by its ID.
+type Secret struct {
+	ID     string
+	Source string
+}
+
+func NewLifecycleExecutor(logger logging.Logger, containerEngine engine.ContainerEngine) *LifecycleExecutor {
+	return &LifecycleExecutor{logger: logger, engine: containerEngine}
 }
 
 func (l *LifecycleExecutor) Execute(ctx context.Context, opts LifecycleOptions) error {
-	lifecycleExec, err := NewLifecycleExecution(l.logger, l.docker, opts)
+	lifecycleExec, err := NewLifecycleExecution(l.logger, l.engine, opts)
 	if err != nil {
 		return err
 	}
@@ -38,6 +38,14 @@ func (m mountPaths) stackPath() string {
 	return m.join(m.layersDir(), "stack.toml")
 }
 
+func (m mountPaths) groupPath() string {
+	return m.join(m.layersDir(), "group.toml")
+}
+
+func (m mountPaths) planPath() string {
+	return m.join(m.layersDir(), "plan.toml")
+}
+
 func (m mountPaths) projectPath() string {
 	return m.join(m.layersDir(), "project-metadata.toml")
 }
@@ -61,3 +69,7 @@ func (m mountPaths) launchCacheDir() string {
 func (m mountPaths) sbomDir() string {
 	return m.join(m.volume, "layers", "sbom")
 }
+
+func (m mountPaths) secretsDir() string {
+	return m.join(m.volume, "run", "secrets")
+}
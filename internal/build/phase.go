@@ -2,21 +2,25 @@ package build
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 )
 
 type Phase struct {
 	name                string
 	infoWriter          io.Writer
 	errorWriter         io.Writer
-	docker              client.CommonAPIClient
+	engine              engine.ContainerEngine
 	handler             container.Handler
 	ctrConf             *dcontainer.Config
 	hostConf            *dcontainer.HostConfig
@@ -26,17 +30,75 @@ type Phase struct {
 	containerOps        []ContainerOperation
 	postContainerRunOps []ContainerOperation
 	fileFilter          func(string) bool
+	eventHandler        events.Handler
+	layersVolume        string
+	measureLayers       bool
+	stats               events.PhaseStats
+	keepFailed          func(phase, containerID string)
+	kept                bool
+	timeout             time.Duration
+}
+
+// PhaseTimeoutError is returned when a phase runs longer than its timeout.
+type PhaseTimeoutError struct {
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("phase %s timed out after %s", style.Symbol(e.Phase), e.Timeout)
 }
 
 func (p *Phase) Run(ctx context.Context) error {
+	start := time.Now()
+	p.emit(events.Event{Type: events.PhaseStarted, Phase: p.name})
+
+	runCtx := ctx
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	docker := newPhaseStatsClient(p.engine)
+	err := p.run(runCtx, docker)
+	if runCtx.Err() != nil && p.ctr.ID != "" {
+		// the container keeps running once the context is done, so is stopped before being kept or removed
+		if stopErr := p.engine.ContainerStop(context.Background(), p.ctr.ID, nil); stopErr != nil {
+			fmt.Fprintf(p.errorWriter, "failed to stop %s container: %s\n", style.Symbol(p.name), stopErr)
+		}
+	}
+	if err != nil && ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+		err = PhaseTimeoutError{Phase: phaseName(p.name), Timeout: p.timeout}
+	}
+	if err != nil && p.keepFailed != nil && p.ctr.ID != "" {
+		p.kept = true
+		p.keepFailed(p.name, p.ctr.ID)
+	}
+
+	p.stats.ContainerStart = docker.startDuration
+	p.stats.ContainerWait = docker.waitDuration
+	p.stats.BytesCopied = docker.bytesCopied
+	if p.measureLayers {
+		p.stats.LayersSize = volumeSize(ctx, p.engine, p.layersVolume)
+	}
+
+	p.emitFinished(start, err)
+	return err
+}
+
+func (p *Phase) run(ctx context.Context, docker *phaseStatsClient) error {
 	var err error
-	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, nil, "")
+	createStart := time.Now()
+	p.ctr, err = docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, nil, "")
 	if err != nil {
 		return errors.Wrapf(err, "failed to create '%s' container", p.name)
 	}
+	p.stats.ContainerCreate = time.Since(createStart)
+	docker.containerID = p.ctr.ID
 
 	for _, containerOp := range p.containerOps {
-		if err := containerOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
+		if err := containerOp(docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
 			return err
 		}
 	}
@@ -48,15 +110,16 @@ func (p *Phase) Run(ctx context.Context) error {
 
 	err = container.RunWithHandler(
 		ctx,
-		p.docker,
+		docker,
 		p.ctr.ID,
 		handler)
+	docker.stopWaiting()
 	if err != nil {
 		return err
 	}
 
 	for _, containerOp := range p.postContainerRunOps {
-		if err := containerOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
+		if err := containerOp(docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
 			return err
 		}
 	}
@@ -64,6 +127,38 @@ func (p *Phase) Run(ctx context.Context) error {
 	return nil
 }
 
+func (p *Phase) emitFinished(start time.Time, err error) {
+	event := events.Event{
+		Type:     events.PhaseFinished,
+		Phase:    p.name,
+		Duration: time.Since(start),
+		Stats:    &p.stats,
+	}
+
+	var exitErr container.ExitError
+	switch {
+	case err == nil:
+		var exitCode int64
+		event.ExitCode = &exitCode
+	case errors.As(err, &exitErr):
+		event.ExitCode = &exitErr.StatusCode
+		event.Error = err.Error()
+	default:
+		event.Error = err.Error()
+	}
+
+	p.emit(event)
+}
+
+func (p *Phase) emit(event events.Event) {
+	if p.eventHandler != nil {
+		p.eventHandler(event)
+	}
+}
+
 func (p *Phase) Cleanup() error {
-	return p.docker.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true})
+	if p.kept {
+		return nil
+	}
+	return p.engine.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true})
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 
@@ -31,6 +32,7 @@ type PhaseConfigProvider struct {
 	infoWriter          io.Writer
 	errorWriter         io.Writer
 	handler             pcontainer.Handler
+	timeout             time.Duration
 }
 
 func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops ...PhaseConfigProviderOperation) *PhaseConfigProvider {
@@ -41,11 +43,14 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 		os:          lifecycleExec.os,
 		infoWriter:  logging.GetWriterForLevel(lifecycleExec.logger, logging.InfoLevel),
 		errorWriter: logging.GetWriterForLevel(lifecycleExec.logger, logging.ErrorLevel),
+		timeout:     lifecycleExec.opts.PhaseTimeouts[phaseName(name)],
 	}
 
 	provider.ctrConf.Image = lifecycleExec.opts.Builder.Name()
 	provider.ctrConf.Labels = map[string]string{"author": "pack"}
 
+	provider.hostConf.Resources = lifecycleExec.opts.Resources
+
 	if lifecycleExec.os == "windows" {
 		provider.hostConf.Isolation = container.IsolationProcess
 	}
@@ -108,6 +113,11 @@ func (p *PhaseConfigProvider) Name() string {
 	return p.name
 }
 
+// Timeout returns the longest time the phase may run for, or zero when it has no timeout.
+func (p *PhaseConfigProvider) Timeout() time.Duration {
+	return p.timeout
+}
+
 func (p *PhaseConfigProvider) ErrorWriter() io.Writer {
 	return p.errorWriter
 }
@@ -139,6 +149,11 @@ func WithBinds(binds ...string) PhaseConfigProviderOperation {
 	}
 }
 
+// WithSecrets copies each secret to <dir>/<id> before the phase runs, owned by the build user with uid and gid.
+func WithSecrets(dir string, uid, gid int, secrets ...Secret) PhaseConfigProviderOperation {
+	return WithContainerOperations(CopySecrets(dir, uid, gid, secrets...))
+}
+
 func WithDaemonAccess(dockerHost string) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		WithRoot()(provider)
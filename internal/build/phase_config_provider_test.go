@@ -18,6 +18,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/build/fakes"
+	"github.com/buildpacks/pack/pkg/engine"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -79,6 +80,35 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("resource limits are provided", func() {
+			it("sets the resources of the container", func() {
+				pidsLimit := int64(100)
+				resources := container.Resources{
+					Memory:    1024 * 1024 * 1024,
+					NanoCPUs:  1500000000,
+					PidsLimit: &pidsLimit,
+				}
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Resources = resources
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Resources, resources)
+			})
+		})
+
+		when("phase timeouts are provided", func() {
+			it("sets the timeout of the phase", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.PhaseTimeouts = map[string]time.Duration{"detect": 2 * time.Minute}
+				})
+
+				h.AssertEq(t, build.NewPhaseConfigProvider("detector", lifecycle).Timeout(), 2*time.Minute)
+				h.AssertEq(t, build.NewPhaseConfigProvider("builder", lifecycle).Timeout(), time.Duration(0))
+			})
+		})
+
 		when("building with interactive mode", func() {
 			it("returns a phase config provider with interactive args", func() {
 				handler := func(bodyChan <-chan container.ContainerWaitOKBody, errChan <-chan error, reader io.Reader) error {
@@ -300,8 +330,9 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 				var outBuf bytes.Buffer
 				logger := logging.NewLogWithWriters(&outBuf, &outBuf, logging.WithVerbose())
 
-				docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+				dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 				h.AssertNil(t, err)
+				docker := engine.NewDocker(dockerClient)
 
 				defaultBuilder, err := fakes.NewFakeBuilder()
 				h.AssertNil(t, err)
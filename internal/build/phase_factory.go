@@ -22,11 +22,11 @@ func NewDefaultPhaseFactory(lifecycleExec *LifecycleExecution) PhaseFactory {
 }
 
 func (m *DefaultPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
-	return &Phase{
+	phase := &Phase{
 		ctrConf:             provider.ContainerConfig(),
 		hostConf:            provider.HostConfig(),
 		name:                provider.Name(),
-		docker:              m.lifecycleExec.docker,
+		engine:              m.lifecycleExec.engine,
 		infoWriter:          provider.InfoWriter(),
 		errorWriter:         provider.ErrorWriter(),
 		handler:             provider.handler,
@@ -36,5 +36,13 @@ func (m *DefaultPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
 		containerOps:        provider.containerOps,
 		postContainerRunOps: provider.postContainerRunOps,
 		fileFilter:          m.lifecycleExec.opts.FileFilter,
+		eventHandler:        m.lifecycleExec.emit,
+		layersVolume:        m.lifecycleExec.layersVolume,
+		measureLayers:       m.lifecycleExec.opts.MeasureLayers,
+		timeout:             provider.Timeout(),
 	}
+	if m.lifecycleExec.opts.KeepOnFailure {
+		phase.keepFailed = m.lifecycleExec.keepFailedPhase
+	}
+	return phase
 }
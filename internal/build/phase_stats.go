@@ -0,0 +1,77 @@
+package build
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/buildpacks/pack/pkg/engine"
+)
+
+// phaseStatsClient wraps the container engine used by a phase to measure the latency of starting and waiting on the
+// phase container and the number of bytes copied into it.
+type phaseStatsClient struct {
+	engine.ContainerEngine
+
+	containerID   string
+	bytesCopied   int64
+	startDuration time.Duration
+	startedAt     time.Time
+	waitDuration  time.Duration
+}
+
+func newPhaseStatsClient(containerEngine engine.ContainerEngine) *phaseStatsClient {
+	return &phaseStatsClient{ContainerEngine: containerEngine}
+}
+
+func (c *phaseStatsClient) CopyToContainer(ctx context.Context, containerID, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+	reader := &countingReader{reader: content}
+	err := c.ContainerEngine.CopyToContainer(ctx, containerID, dstPath, reader, options)
+	atomic.AddInt64(&c.bytesCopied, reader.count)
+	return err
+}
+
+func (c *phaseStatsClient) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	start := time.Now()
+	err := c.ContainerEngine.ContainerStart(ctx, containerID, options)
+	if containerID == c.containerID {
+		c.startDuration = time.Since(start)
+		c.startedAt = time.Now()
+	}
+	return err
+}
+
+func (c *phaseStatsClient) stopWaiting() {
+	if !c.startedAt.IsZero() {
+		c.waitDuration = time.Since(c.startedAt)
+	}
+}
+
+// volumeSize returns the size of the named volume as reported by the daemon, or 0 when it cannot be determined.
+func volumeSize(ctx context.Context, containerEngine engine.ContainerEngine, volumeName string) int64 {
+	usage, err := containerEngine.DiskUsage(ctx)
+	if err != nil {
+		return 0
+	}
+
+	for _, volume := range usage.Volumes {
+		if volume.Name == volumeName && volume.UsageData != nil {
+			return volume.UsageData.Size
+		}
+	}
+	return 0
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
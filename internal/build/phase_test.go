@@ -19,11 +19,13 @@ import (
 
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/lifecycle/auth"
+	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/heroku/color"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -31,6 +33,8 @@ import (
 	"github.com/buildpacks/pack/internal/build/fakes"
 	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -39,7 +43,7 @@ const phaseName = "phase"
 
 var (
 	repoName  string
-	ctrClient client.CommonAPIClient
+	ctrClient *engine.Docker
 )
 
 // TestPhase is a integration test suite to ensure that the phase options are propagated to the container.
@@ -51,9 +55,9 @@ func TestPhase(t *testing.T) {
 
 	h.RequireDocker(t)
 
-	var err error
-	ctrClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 	h.AssertNil(t, err)
+	ctrClient = engine.NewDocker(dockerClient)
 
 	info, err := ctrClient.Info(context.TODO())
 	h.AssertNil(t, err)
@@ -73,7 +77,7 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 		lifecycleExec  *build.LifecycleExecution
 		phaseFactory   build.PhaseFactory
 		outBuf, errBuf bytes.Buffer
-		docker         client.CommonAPIClient
+		docker         *engine.Docker
 		logger         logging.Logger
 		osType         string
 	)
@@ -81,9 +85,9 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 	it.Before(func() {
 		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
 
-		var err error
-		docker, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+		dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 		h.AssertNil(t, err)
+		docker = engine.NewDocker(dockerClient)
 
 		info, err := ctrClient.Info(context.Background())
 		h.AssertNil(t, err)
@@ -193,6 +197,47 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 				h.AssertContains(t, outBuf.String(), "failed to read file")
 			})
 
+			it("emits events describing the phase", func() {
+				var receivedEvents []events.Event
+				builderImage, err := local.NewImage(repoName, docker, local.FromBaseImage(repoName))
+				h.AssertNil(t, err)
+				fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithUID(111), fakes.WithGID(222), fakes.WithImage(builderImage))
+				h.AssertNil(t, err)
+				eventsExec, err := build.NewLifecycleExecution(logger, docker, build.LifecycleOptions{
+					AppPath: lifecycleExec.AppPath(),
+					Builder: fakeBuilder,
+					EventHandler: func(e events.Event) {
+						receivedEvents = append(receivedEvents, e)
+					},
+				})
+				h.AssertNil(t, err)
+				defer func() { h.AssertNilE(t, eventsExec.Cleanup()) }()
+
+				configProvider := build.NewPhaseConfigProvider(
+					phaseName,
+					eventsExec,
+					build.WithArgs("read", "/workspace/fake-app-file"),
+					build.WithContainerOperations(
+						build.CopyDir(eventsExec.AppPath(), "/workspace", 111, 222, osType, false, nil),
+					),
+				)
+				phase := build.NewDefaultPhaseFactory(eventsExec).New(configProvider)
+				assertRunSucceeds(t, phase, &outBuf, &errBuf)
+
+				h.AssertEq(t, len(receivedEvents), 2)
+				h.AssertEq(t, receivedEvents[0].Type, events.PhaseStarted)
+				h.AssertEq(t, receivedEvents[0].Phase, phaseName)
+				h.AssertEq(t, receivedEvents[1].Type, events.PhaseFinished)
+				h.AssertEq(t, *receivedEvents[1].ExitCode, int64(0))
+				h.AssertNotNil(t, receivedEvents[1].Stats)
+				if receivedEvents[1].Stats.BytesCopied == 0 {
+					t.Fatalf("expected bytes to be copied into the container")
+				}
+				if receivedEvents[1].Stats.ContainerWait == 0 {
+					t.Fatalf("expected time spent waiting on the container to be recorded")
+				}
+			})
+
 			when("app is a dir", func() {
 				it("preserves original mod times", func() {
 					assertAppModTimePreserved(t, lifecycleExec, phaseFactory, &outBuf, &errBuf, osType)
@@ -453,6 +498,35 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("the phase times out", func() {
+		it.Before(func() {
+			opts, err := fakeLifecycleOptions(docker, filepath.Join("testdata", "fake-app"), repoName)
+			h.AssertNil(t, err)
+			opts.PhaseTimeouts = map[string]time.Duration{phaseName: time.Second}
+			lifecycleExec, err = build.NewLifecycleExecution(logger, docker, opts)
+			h.AssertNil(t, err)
+			phaseFactory = build.NewDefaultPhaseFactory(lifecycleExec)
+		})
+
+		it("stops the container and returns a timeout error", func() {
+			configProvider := build.NewPhaseConfigProvider(phaseName, lifecycleExec, build.WithArgs("sleep"))
+			phase := phaseFactory.New(configProvider)
+			defer phase.Cleanup()
+
+			err := phase.Run(context.TODO())
+			h.AssertError(t, err, "phase 'phase' timed out after 1s")
+			h.AssertTrue(t, errors.As(err, &build.PhaseTimeoutError{}))
+
+			body, err := docker.ContainerList(context.TODO(), types.ContainerListOptions{
+				All:     true,
+				Filters: filters.NewArgs(filters.Arg("volume", lifecycleExec.LayersVolume())),
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(body), 1)
+			h.AssertNotEq(t, body[0].State, "running")
+		})
+	})
+
 	when("#Cleanup", func() {
 		it.Before(func() {
 			configProvider := build.NewPhaseConfigProvider(phaseName, lifecycleExec)
@@ -482,6 +556,53 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, err)
 			h.AssertEq(t, len(body.Volumes), 0)
 		})
+
+		when("the build keeps failed containers", func() {
+			var containerID string
+
+			it.Before(func() {
+				opts, err := fakeLifecycleOptions(docker, filepath.Join("testdata", "fake-app"), repoName)
+				h.AssertNil(t, err)
+				opts.KeepOnFailure = true
+				lifecycleExec, err = build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+				phaseFactory = build.NewDefaultPhaseFactory(lifecycleExec)
+
+				configProvider := build.NewPhaseConfigProvider(phaseName, lifecycleExec, build.WithArgs("read", "/workspace/missing.txt"))
+				phase := phaseFactory.New(configProvider)
+				h.AssertNotNil(t, phase.Run(context.TODO()))
+				h.AssertNil(t, phase.Cleanup())
+
+				body, err := docker.ContainerList(context.TODO(), types.ContainerListOptions{
+					All:     true,
+					Filters: filters.NewArgs(filters.Arg("volume", lifecycleExec.LayersVolume())),
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(body), 1)
+				containerID = body[0].ID
+
+				h.AssertNil(t, lifecycleExec.Cleanup())
+			})
+
+			it.After(func() {
+				h.AssertNil(t, docker.ContainerRemove(context.TODO(), containerID, types.ContainerRemoveOptions{Force: true}))
+				h.AssertNil(t, docker.VolumeRemove(context.TODO(), lifecycleExec.LayersVolume(), true))
+				h.AssertNil(t, docker.VolumeRemove(context.TODO(), lifecycleExec.AppVolume(), true))
+			})
+
+			it("keeps the volumes of the failed phase", func() {
+				for _, volume := range []string{lifecycleExec.LayersVolume(), lifecycleExec.AppVolume()} {
+					body, err := docker.VolumeList(context.TODO(), filters.NewArgs(filters.Arg("name", volume)))
+					h.AssertNil(t, err)
+					h.AssertEq(t, len(body.Volumes), 1)
+				}
+			})
+
+			it("prints how to debug the failed phase", func() {
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("Kept the container '%s' of the failed 'phase' phase", containerID))
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("pack build debug %s", containerID))
+			})
+		})
 	})
 }
 
@@ -511,39 +632,41 @@ func assertRunSucceeds(t *testing.T, phase build.RunnerCleaner, outBuf *bytes.Bu
 	h.AssertNilE(t, phase.Cleanup())
 }
 
-func CreateFakeLifecycleExecution(logger logging.Logger, docker client.CommonAPIClient, appDir string, repoName string, handler ...container.Handler) (*build.LifecycleExecution, error) {
-	builderImage, err := local.NewImage(repoName, docker, local.FromBaseImage(repoName))
+func CreateFakeLifecycleExecution(logger logging.Logger, docker *engine.Docker, appDir string, repoName string, handler ...container.Handler) (*build.LifecycleExecution, error) {
+	opts, err := fakeLifecycleOptions(docker, appDir, repoName)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(handler) != 0 {
+		opts.Interactive = true
+		opts.Termui = &fakes.FakeTermui{HandlerFunc: handler[0]}
+	}
+
+	return build.NewLifecycleExecution(logger, docker, opts)
+}
+
+func fakeLifecycleOptions(docker *engine.Docker, appDir string, repoName string) (build.LifecycleOptions, error) {
+	builderImage, err := local.NewImage(repoName, docker, local.FromBaseImage(repoName))
+	if err != nil {
+		return build.LifecycleOptions{}, err
+	}
+
 	fakeBuilder, err := fakes.NewFakeBuilder(
 		fakes.WithUID(111), fakes.WithGID(222),
 		fakes.WithImage(builderImage),
 	)
 	if err != nil {
-		return nil, err
-	}
-
-	var (
-		interactive bool
-		termui      build.Termui
-	)
-
-	if len(handler) != 0 {
-		interactive = true
-		termui = &fakes.FakeTermui{HandlerFunc: handler[0]}
+		return build.LifecycleOptions{}, err
 	}
 
-	return build.NewLifecycleExecution(logger, docker, build.LifecycleOptions{
-		AppPath:     appDir,
-		Builder:     fakeBuilder,
-		HTTPProxy:   "some-http-proxy",
-		HTTPSProxy:  "some-https-proxy",
-		NoProxy:     "some-no-proxy",
-		Interactive: interactive,
-		Termui:      termui,
-	})
+	return build.LifecycleOptions{
+		AppPath:    appDir,
+		Builder:    fakeBuilder,
+		HTTPProxy:  "some-http-proxy",
+		HTTPSProxy: "some-https-proxy",
+		NoProxy:    "some-no-proxy",
+	}, nil
 }
 
 // helper function to expose standard UNIX socket `/var/run/docker.sock` via TCP localhost:PORT
@@ -10,6 +10,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/docker/docker/api/types"
@@ -53,6 +54,9 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "user" {
 		testUser()
 	}
+	if len(os.Args) > 1 && os.Args[1] == "sleep" {
+		testSleep()
+	}
 }
 
 func testWrite(filename, contents string) {
@@ -211,3 +215,8 @@ func testUser() {
 
 	fmt.Printf("current user is %s\n", user.Name)
 }
+
+func testSleep() {
+	fmt.Println("sleep test")
+	time.Sleep(time.Minute)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// bindCacheEntries are the directories the lifecycle creates in a cache directory.
+// Clearing a bind cache only removes these, leaving anything else in the user supplied host directory untouched.
+var bindCacheEntries = []string{"committed", "committed-backup", "staging"}
+
+type BindCache struct {
+	path string
+}
+
+// NewBindCache creates a cache backed by the host directory at path, which is bind-mounted into the build containers.
+// The directory is created when missing, rather than by the engine mounting it, which would create it owned by root.
+func NewBindCache(path string) (*BindCache, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, errors.Wrapf(err, "creating cache directory %s", style.Symbol(path))
+	}
+	return &BindCache{
+		path: path,
+	}, nil
+}
+
+func (c *BindCache) Name() string {
+	return c.path
+}
+
+// Clear removes the cache contents written by the lifecycle from the host directory.
+func (c *BindCache) Clear(ctx context.Context) error {
+	for _, entry := range bindCacheEntries {
+		if err := os.RemoveAll(filepath.Join(c.path, entry)); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(c.path, 0755)
+}
+
+func (c *BindCache) Type() Type {
+	return Bind
+}
@@ -0,0 +1,105 @@
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBindCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BindCache", testBindCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBindCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir   string
+		cacheDir string
+		subject  *cache.BindCache
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "bind-cache")
+		h.AssertNil(t, err)
+		cacheDir = filepath.Join(tmpDir, "some-cache")
+		subject, err = cache.NewBindCache(cacheDir)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#NewBindCache", func() {
+		it("creates the host directory when it's absent", func() {
+			info, err := os.Stat(cacheDir)
+			h.AssertNil(t, err)
+			h.AssertTrue(t, info.IsDir())
+		})
+
+		it("fails when the host directory can't be created", func() {
+			file := filepath.Join(tmpDir, "some-file")
+			h.AssertNil(t, ioutil.WriteFile(file, []byte{}, 0600))
+
+			_, err := cache.NewBindCache(filepath.Join(file, "some-cache"))
+			h.AssertError(t, err, "creating cache directory")
+		})
+	})
+
+	when("#Name", func() {
+		it("is the host directory", func() {
+			h.AssertEq(t, subject.Name(), cacheDir)
+		})
+	})
+
+	when("#Type", func() {
+		it("returns the bind cache type", func() {
+			h.AssertEq(t, subject.Type(), cache.Bind)
+		})
+	})
+
+	when("#Clear", func() {
+		it("removes the cache contents written by the lifecycle", func() {
+			for _, dir := range []string{"committed", "committed-backup", "staging"} {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(cacheDir, dir), 0755))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(cacheDir, dir, "some-layer.tar"), []byte("some-content"), 0600))
+			}
+
+			h.AssertNil(t, subject.Clear(context.TODO()))
+
+			entries, err := ioutil.ReadDir(cacheDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 0)
+		})
+
+		it("keeps other files of the host directory", func() {
+			h.AssertNil(t, os.MkdirAll(cacheDir, 0755))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(cacheDir, "some-file"), []byte("some-content"), 0600))
+
+			h.AssertNil(t, subject.Clear(context.TODO()))
+
+			_, err := os.Stat(filepath.Join(cacheDir, "some-file"))
+			h.AssertNil(t, err)
+		})
+
+		it("creates the host directory if it doesn't exist", func() {
+			h.AssertNil(t, subject.Clear(context.TODO()))
+
+			info, err := os.Stat(cacheDir)
+			h.AssertNil(t, err)
+			h.AssertTrue(t, info.IsDir())
+		})
+	})
+}
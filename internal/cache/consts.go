@@ -3,6 +3,7 @@ package cache
 const (
 	Image Type = iota
 	Volume
+	Bind
 )
 
 type Type int
@@ -6,17 +6,19 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpacks/pack/pkg/engine"
 )
 
 type ImageCache struct {
-	docker client.CommonAPIClient
+	engine engine.ContainerEngine
 	image  string
 }
 
-func NewImageCache(imageRef name.Reference, dockerClient client.CommonAPIClient) *ImageCache {
+func NewImageCache(imageRef name.Reference, containerEngine engine.ContainerEngine) *ImageCache {
 	return &ImageCache{
 		image:  imageRef.Name(),
-		docker: dockerClient,
+		engine: containerEngine,
 	}
 }
 
@@ -25,7 +27,7 @@ func (c *ImageCache) Name() string {
 }
 
 func (c *ImageCache) Clear(ctx context.Context) error {
-	_, err := c.docker.ImageRemove(ctx, c.Name(), types.ImageRemoveOptions{
+	_, err := c.engine.ImageRemove(ctx, c.Name(), types.ImageRemoveOptions{
 		Force: true,
 	})
 	if err != nil && !client.IsErrNotFound(err) {
@@ -16,6 +16,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/pkg/engine"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -30,12 +31,12 @@ func TestImageCache(t *testing.T) {
 
 func testImageCache(t *testing.T, when spec.G, it spec.S) {
 	when("#NewImageCache", func() {
-		var dockerClient client.CommonAPIClient
+		var dockerClient *engine.Docker
 
 		it.Before(func() {
-			var err error
-			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 			h.AssertNil(t, err)
+			dockerClient = engine.NewDocker(docker)
 		})
 
 		when("#Name", func() {
@@ -78,13 +79,13 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 
 	when("#Type", func() {
 		var (
-			dockerClient client.CommonAPIClient
+			dockerClient *engine.Docker
 		)
 
 		it.Before(func() {
-			var err error
-			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 			h.AssertNil(t, err)
+			dockerClient = engine.NewDocker(docker)
 		})
 
 		it("returns the cache type", func() {
@@ -99,15 +100,15 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 	when("#Clear", func() {
 		var (
 			imageName    string
-			dockerClient client.CommonAPIClient
+			dockerClient *engine.Docker
 			subject      *cache.ImageCache
 			ctx          context.Context
 		)
 
 		it.Before(func() {
-			var err error
-			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 			h.AssertNil(t, err)
+			dockerClient = engine.NewDocker(docker)
 			ctx = context.TODO()
 
 			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
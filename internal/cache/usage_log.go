@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UsageEntry records which app image a cache volume belongs to and when it was last used.
+type UsageEntry struct {
+	Image    string    `json:"image"`
+	Type     string    `json:"type"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// UsageLog records the cache volumes used by builds in a file on the host,
+// as the app image can't be recovered from a volume name and docker doesn't track when a volume was last used.
+// The log is locked with a lock file next to it while read or updated, as concurrent pack processes share it.
+type UsageLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewUsageLog(path string) *UsageLog {
+	return &UsageLog{path: path}
+}
+
+// Entries returns the recorded entries keyed by volume name.
+func (l *UsageLog) Entries() (map[string]UsageEntry, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return l.read()
+}
+
+// Record sets the entry for a volume, replacing any previous entry.
+func (l *UsageLog) Record(volume string, entry UsageEntry) error {
+	unlock, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	entries[volume] = entry
+	return l.write(entries)
+}
+
+// Remove deletes the entries of the given volumes.
+func (l *UsageLog) Remove(volumes ...string) error {
+	unlock, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		delete(entries, volume)
+	}
+	return l.write(entries)
+}
+
+// lock locks the log against other goroutines and other pack processes, and returns the function unlocking it.
+func (l *UsageLog) lock() (func(), error) {
+	l.mu.Lock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0750); err != nil {
+		l.mu.Unlock()
+		return nil, errors.Wrapf(err, "creating directory for cache usage log %s", l.path)
+	}
+
+	lockFilePath := l.path + ".lock"
+	f, err := os.OpenFile(filepath.Clean(lockFilePath), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		l.mu.Unlock()
+		return nil, errors.Wrapf(err, "opening lock file %s", lockFilePath)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		l.mu.Unlock()
+		return nil, errors.Wrapf(err, "locking cache usage log %s", l.path)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+		l.mu.Unlock()
+	}, nil
+}
+
+func (l *UsageLog) read() (map[string]UsageEntry, error) {
+	entries := map[string]UsageEntry{}
+
+	contents, err := ioutil.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading cache usage log %s", l.path)
+	}
+
+	if err := json.Unmarshal(contents, &entries); err != nil {
+		return nil, errors.Wrapf(err, "parsing cache usage log %s", l.path)
+	}
+	return entries, nil
+}
+
+func (l *UsageLog) write(entries map[string]UsageEntry) error {
+	contents, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0750); err != nil {
+		return errors.Wrapf(err, "creating directory for cache usage log %s", l.path)
+	}
+
+	// write to a temporary file first so that concurrent pack processes never read a partial log
+	tmpFile, err := ioutil.TempFile(filepath.Dir(l.path), filepath.Base(l.path))
+	if err != nil {
+		return errors.Wrapf(err, "writing cache usage log %s", l.path)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return errors.Wrapf(err, "writing cache usage log %s", l.path)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrapf(err, "writing cache usage log %s", l.path)
+	}
+
+	return errors.Wrapf(os.Rename(tmpFile.Name(), l.path), "writing cache usage log %s", l.path)
+}
@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestUsageLog(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "UsageLog", testUsageLog, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUsageLog(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir  string
+		logPath string
+		subject *cache.UsageLog
+		now     = time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "usage-log")
+		h.AssertNil(t, err)
+		logPath = filepath.Join(tmpDir, "some-dir", "cache-usage.json")
+		subject = cache.NewUsageLog(logPath)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Entries", func() {
+		it("is empty when nothing was recorded", func() {
+			entries, err := subject.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 0)
+		})
+
+		it("fails when the log is malformed", func() {
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(logPath), 0755))
+			h.AssertNil(t, ioutil.WriteFile(logPath, []byte("not-json"), 0600))
+
+			_, err := subject.Entries()
+			h.AssertError(t, err, "parsing cache usage log")
+		})
+	})
+
+	when("#Record", func() {
+		it("persists entries across instances", func() {
+			h.AssertNil(t, subject.Record("some-volume.build", cache.UsageEntry{Image: "some/app", Type: "build", LastUsed: now}))
+			h.AssertNil(t, subject.Record("some-volume.launch", cache.UsageEntry{Image: "some/app", Type: "launch", LastUsed: now}))
+			h.AssertNil(t, subject.Record("some-volume.build", cache.UsageEntry{Image: "some/app", Type: "build", LastUsed: now.Add(time.Hour)}))
+
+			entries, err := cache.NewUsageLog(logPath).Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, entries, map[string]cache.UsageEntry{
+				"some-volume.build":  {Image: "some/app", Type: "build", LastUsed: now.Add(time.Hour)},
+				"some-volume.launch": {Image: "some/app", Type: "launch", LastUsed: now},
+			})
+		})
+
+		it("keeps the entries recorded concurrently by other instances", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					h.AssertNil(t, cache.NewUsageLog(logPath).Record(fmt.Sprintf("volume-%d.build", i), cache.UsageEntry{Image: "some/app", Type: "build", LastUsed: now}))
+				}(i)
+			}
+			wg.Wait()
+
+			entries, err := subject.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 10)
+		})
+	})
+
+	when("#Remove", func() {
+		it("removes the given entries", func() {
+			h.AssertNil(t, subject.Record("some-volume.build", cache.UsageEntry{Image: "some/app", Type: "build", LastUsed: now}))
+			h.AssertNil(t, subject.Record("other-volume.build", cache.UsageEntry{Image: "other/app", Type: "build", LastUsed: now}))
+
+			h.AssertNil(t, subject.Remove("some-volume.build", "missing-volume"))
+
+			entries, err := subject.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 1)
+			h.AssertEq(t, entries["other-volume.build"].Image, "other/app")
+		})
+	})
+}
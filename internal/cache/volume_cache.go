@@ -10,20 +10,21 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/pkg/engine"
 )
 
 type VolumeCache struct {
-	docker client.CommonAPIClient
+	engine engine.ContainerEngine
 	volume string
 }
 
-func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient) *VolumeCache {
+func NewVolumeCache(imageRef name.Reference, suffix string, containerEngine engine.ContainerEngine) *VolumeCache {
 	sum := sha256.Sum256([]byte(imageRef.Name()))
 
 	vol := paths.FilterReservedNames(fmt.Sprintf("%s-%x", sanitizedRef(imageRef), sum[:6]))
 	return &VolumeCache{
 		volume: fmt.Sprintf("pack-cache-%s.%s", vol, suffix),
-		docker: dockerClient,
+		engine: containerEngine,
 	}
 }
 
@@ -32,7 +33,7 @@ func (c *VolumeCache) Name() string {
 }
 
 func (c *VolumeCache) Clear(ctx context.Context) error {
-	err := c.docker.VolumeRemove(ctx, c.Name(), true)
+	err := c.engine.VolumeRemove(ctx, c.Name(), true)
 	if err != nil && !client.IsErrNotFound(err) {
 		return err
 	}
@@ -17,6 +17,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/pkg/engine"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -30,12 +31,12 @@ func TestVolumeCache(t *testing.T) {
 }
 
 func testCache(t *testing.T, when spec.G, it spec.S) {
-	var dockerClient client.CommonAPIClient
+	var dockerClient *engine.Docker
 
 	it.Before(func() {
-		var err error
-		dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+		docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 		h.AssertNil(t, err)
+		dockerClient = engine.NewDocker(docker)
 	})
 	when("#NewVolumeCache", func() {
 		it("adds suffix to calculated name", func() {
@@ -124,15 +125,15 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 	when("#Clear", func() {
 		var (
 			volumeName   string
-			dockerClient client.CommonAPIClient
+			dockerClient *engine.Docker
 			subject      *cache.VolumeCache
 			ctx          context.Context
 		)
 
 		it.Before(func() {
-			var err error
-			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 			h.AssertNil(t, err)
+			dockerClient = engine.NewDocker(docker)
 			ctx = context.TODO()
 
 			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
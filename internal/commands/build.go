@@ -1,24 +1,39 @@
 package commands
 
 import (
+	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/docker/go-units"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
+	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/project"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 )
 
+const (
+	eventsFormatJSONL     = "jsonl"
+	outputOCILayoutPrefix = "oci-layout:"
+	detectFormatHuman     = "human"
+	detectFormatJSON      = "json"
+)
+
 type BuildFlags struct {
 	Publish            bool
 	ClearCache         bool
@@ -26,6 +41,7 @@ type BuildFlags struct {
 	Interactive        bool
 	DockerHost         string
 	CacheImage         string
+	Cache              cache.CacheOpts
 	AppPath            string
 	Builder            string
 	Registry           string
@@ -40,10 +56,53 @@ type BuildFlags struct {
 	Buildpacks         []string
 	Volumes            []string
 	AdditionalTags     []string
+	Platforms          []string
 	Workspace          string
 	GID                int
 	PreviousImage      string
 	SBOMDestinationDir string
+	OutputEvents       string
+	Output             string
+	ReportFile         string
+	ProvenanceFile     string
+	MetadataFile       string
+	SigningKey         string
+	Report             bool
+	Lock               bool
+	Locked             bool
+	DetectOnly         bool
+	DetectFormat       string
+	KeepOnFailure      bool
+	CheckGitDirty      bool
+	Secrets            []string
+	Memory             string
+	CPUs               float64
+	PidsLimit          int64
+	Ulimits            []string
+	Labels             []string
+	All                bool
+	FromFile           string
+	Parallel           int
+	Timeout            time.Duration
+	PhaseTimeouts      []string
+}
+
+// buildsFile is the file of builds read by pack build --from-file.
+type buildsFile struct {
+	Builds []buildsFileEntry `yaml:"builds"`
+}
+
+// buildsFileEntry is a build of a builds file. Paths are relative to the directory of the builds file, and the values
+// set replace those of the flags.
+type buildsFileEntry struct {
+	Image      string            `yaml:"image"`
+	Path       string            `yaml:"path"`
+	Descriptor string            `yaml:"descriptor"`
+	Builder    string            `yaml:"builder"`
+	RunImage   string            `yaml:"run-image"`
+	Env        map[string]string `yaml:"env"`
+	Buildpacks []string          `yaml:"buildpacks"`
+	Tags       []string          `yaml:"tags"`
 }
 
 // Build an image from source code
@@ -51,21 +110,31 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 	var flags BuildFlags
 
 	cmd := &cobra.Command{
-		Use:     "build <image-name>",
-		Args:    cobra.ExactArgs(1),
+		Use: "build <image-name>",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if flags.All || flags.FromFile != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Short:   "Generate app image from source code",
 		Example: "pack build test_img --path apps/test-app --builder cnbs/sample-builder:bionic",
 		Long: "Pack Build uses Cloud Native Buildpacks to create a runnable app image from source code.\n\nPack Build " +
 			"requires an image name, which will be generated from the source code. Build defaults to the current directory, " +
 			"but you can use `--path` to specify another source code directory. Build requires a `builder`, which can either " +
 			"be provided directly to build using `--builder`, or can be set using the `set-default-builder` command. For more " +
-			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.",
+			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.\n\n" +
+			"Use `--all` instead of an image name to build each app declared by the project descriptor to its own image, " +
+			"or `--from-file` to build each image listed in a YAML file, up to `--parallel` at the same time.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			if err := validateBuildFlags(&flags, cfg, packClient, logger); err != nil {
 				return err
 			}
 
-			imageName := args[0]
+			var imageName string
+			if !flags.All && flags.FromFile == "" {
+				imageName = args[0]
+			}
 
 			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath)
 			if err != nil {
@@ -83,7 +152,7 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				builder = descriptor.Build.Builder
 			}
 
-			if builder == "" {
+			if builder == "" && flags.FromFile == "" {
 				suggestSettingBuilder(logger, packClient)
 				return client.NewSoftError()
 			}
@@ -128,16 +197,57 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 			if cmd.Flags().Changed("gid") {
 				gid = flags.GID
 			}
-			if err := packClient.Build(cmd.Context(), client.BuildOptions{
+			ociLayoutDir, err := parseOutput(flags.Output)
+			if err != nil {
+				return err
+			}
+			secrets, err := parseSecrets(flags.Secrets)
+			if err != nil {
+				return err
+			}
+			labels, err := parseLabels(flags.Labels)
+			if err != nil {
+				return err
+			}
+			phaseTimeouts, err := parsePhaseTimeouts(flags.PhaseTimeouts)
+			if err != nil {
+				return err
+			}
+			var memory int64
+			if flags.Memory != "" {
+				if memory, err = units.RAMInBytes(flags.Memory); err != nil {
+					return errors.Wrapf(err, "parsing memory limit %s", style.Symbol(flags.Memory))
+				}
+			}
+			var eventHandlers []events.Handler
+			if flags.OutputEvents == eventsFormatJSONL {
+				eventHandlers = append(eventHandlers, events.NewJSONLHandler(logger.Writer()))
+			}
+			var lockFile string
+			if flags.Lock || flags.Locked {
+				lockFile = lockFilePath(flags.AppPath, actualDescriptorPath)
+			}
+			var buildReport *client.BuildReport
+			if flags.Report || flags.ReportFile != "" {
+				buildReport = &client.BuildReport{}
+				eventHandlers = append(eventHandlers, buildReport.Record)
+			}
+			buildOpts := client.BuildOptions{
 				AppPath:           flags.AppPath,
 				Builder:           builder,
 				Registry:          flags.Registry,
 				AdditionalMirrors: getMirrors(cfg),
 				AdditionalTags:    flags.AdditionalTags,
+				Platforms:         flags.Platforms,
 				RunImage:          flags.RunImage,
 				Env:               env,
 				Image:             imageName,
 				Publish:           flags.Publish,
+				SigningKey:        flags.SigningKey,
+				LockFile:          lockFile,
+				ProvenanceFile:    flags.ProvenanceFile,
+				MetadataFile:      flags.MetadataFile,
+				Locked:            flags.Locked,
 				DockerHost:        flags.DockerHost,
 				PullPolicy:        pullPolicy,
 				ClearCache:        flags.ClearCache,
@@ -146,20 +256,81 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				},
 				Buildpacks: buildpacks,
 				ContainerConfig: client.ContainerConfig{
-					Network: flags.Network,
-					Volumes: flags.Volumes,
+					Network:   flags.Network,
+					Volumes:   flags.Volumes,
+					Memory:    memory,
+					CPUs:      flags.CPUs,
+					PidsLimit: flags.PidsLimit,
+					Ulimits:   mergeUlimits(cfg, flags.Ulimits),
 				},
 				DefaultProcessType:       flags.DefaultProcessType,
 				ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
 				ProjectDescriptor:        descriptor,
 				CacheImage:               flags.CacheImage,
+				Cache:                    flags.Cache,
 				Workspace:                flags.Workspace,
 				LifecycleImage:           lifecycleImage,
 				GroupID:                  gid,
 				PreviousImage:            flags.PreviousImage,
 				Interactive:              flags.Interactive,
 				SBOMDestinationDir:       flags.SBOMDestinationDir,
-			}); err != nil {
+				OCILayoutDir:             ociLayoutDir,
+				EventHandler:             combineEventHandlers(eventHandlers),
+				MeasureLayers:            buildReport != nil,
+				KeepOnFailure:            flags.KeepOnFailure,
+				Secrets:                  secrets,
+				Labels:                   labels,
+				CheckGitDirty:            flags.CheckGitDirty,
+				Timeout:                  flags.Timeout,
+				PhaseTimeouts:            phaseTimeouts,
+			}
+			if flags.DetectOnly {
+				result, err := packClient.Detect(cmd.Context(), buildOpts)
+				if err != nil {
+					return errors.Wrap(err, "failed to detect")
+				}
+				return printDetectResult(logger, result, flags.DetectFormat)
+			}
+			if flags.FromFile != "" {
+				builds, err := readBuildsFile(cmd, flags, cfg, buildOpts)
+				if err != nil {
+					return err
+				}
+				for _, entryOpts := range builds {
+					if entryOpts.Builder == "" {
+						suggestSettingBuilder(logger, packClient)
+						return client.NewSoftError()
+					}
+				}
+				if err := packClient.BuildMany(cmd.Context(), client.BuildManyOptions{Builds: builds, Parallel: flags.Parallel}); err != nil {
+					return errors.Wrap(err, "failed to build")
+				}
+				for _, entryOpts := range builds {
+					logger.Infof("Successfully built image %s", style.Symbol(entryOpts.Image))
+				}
+				return nil
+			}
+			if flags.All {
+				if err := packClient.BuildAll(cmd.Context(), buildOpts); err != nil {
+					return errors.Wrap(err, "failed to build")
+				}
+				for _, app := range descriptor.Apps {
+					logger.Infof("Successfully built image %s", style.Symbol(app.Image))
+				}
+				return nil
+			}
+			err = packClient.Build(cmd.Context(), buildOpts)
+			if buildReport != nil {
+				if flags.Report {
+					printBuildReport(logger, *buildReport)
+				}
+				if flags.ReportFile != "" {
+					if err := writeBuildReport(flags.ReportFile, *buildReport); err != nil {
+						return err
+					}
+				}
+			}
+			if err != nil {
 				return errors.Wrap(err, "failed to build")
 			}
 			logger.Infof("Successfully built image %s", style.Symbol(imageName))
@@ -168,20 +339,41 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 	}
 	buildCommandFlags(cmd, &flags, cfg)
 	AddHelpFlag(cmd, "build")
+	cmd.AddCommand(BuildDebug(logger, packClient))
 	return cmd
 }
 
 func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Config) {
+	cmd.Flags().BoolVar(&buildFlags.All, "all", false, "Build each app declared by the project descriptor to its own image, instead of the image given as argument")
 	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
 	cmd.Flags().StringSliceVarP(&buildFlags.Buildpacks, "buildpack", "b", nil, "Buildpack to use. One of:\n  a buildpack by id and version in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  a packaged buildpack image name in the form of '<hostname>/<repo>[:<tag>]'"+stringSliceHelp("buildpack"))
 	cmd.Flags().StringVarP(&buildFlags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
 	cmd.Flags().StringVar(&buildFlags.CacheImage, "cache-image", "", `Cache build layers in remote registry. Requires --publish`)
+	cmd.Flags().Var(&buildFlags.Cache, "cache", `Cache options used to define cache techniques for build process, in the form 'type=<build|launch>,format=<volume|image|bind>,source=<image name or host path>'.
+- 'type' (default "build"): The cache to configure.
+- 'format' (default "volume"): Store the cache in a volume, in an image (build cache only, requires --publish) or in a host directory.
+- 'source': The image name of an image cache, or the host directory of a bind cache.`)
+	cmd.Flags().BoolVar(&buildFlags.CheckGitDirty, "check-git-dirty", false, "Record whether the git repository of the app has uncommitted changes in the project metadata of the app image.\nChecking it reads every file of the repository, which is slow for large repositories.")
 	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
+	cmd.Flags().StringVar(&buildFlags.FromFile, "from-file", "", "Path to a YAML file listing the images to build, instead of the image given as argument.\nEach build sets its image and may set its path, descriptor, builder, run-image, env, buildpacks and tags,\n  which replace those of the flags.")
 	cmd.Flags().StringVarP(&buildFlags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
 	cmd.Flags().StringVarP(&buildFlags.DefaultProcessType, "default-process", "D", "", `Set the default process type. (default "web")`)
+	cmd.Flags().BoolVar(&buildFlags.DetectOnly, "detect-only", false, "Only run detection, and print the buildpacks selected for the app and the build plan they resolved.\nNo image is built.")
+	cmd.Flags().StringVar(&buildFlags.DetectFormat, "detect-format", detectFormatHuman, "Format to print the result of --detect-only in. Accepted values are human and json.")
 	cmd.Flags().StringArrayVarP(&buildFlags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file."+stringArrayHelp("env")+"\nNOTE: These are NOT available at image runtime.")
 	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed\nNOTE: These are NOT available at image runtime.\"")
+	cmd.Flags().BoolVar(&buildFlags.KeepOnFailure, "keep-on-failure", false, "Keep the container of a failed lifecycle phase and the volumes of the build, to debug the failure with 'pack build debug'.\nCannot be used with secrets.")
+	limits := config.ContainerLimits{}
+	if cfg.ContainerLimits != nil {
+		limits = *cfg.ContainerLimits
+	}
+	cmd.Flags().StringVar(&buildFlags.Memory, "memory", limits.Memory, "Memory limit of each build container, such as '512m' or '4g'")
+	cmd.Flags().Float64Var(&buildFlags.CPUs, "cpus", limits.CPUs, "Number of CPUs each build container can use, such as '1.5'")
+	cmd.Flags().Int64Var(&buildFlags.PidsLimit, "pids-limit", limits.PidsLimit, "Maximum number of processes of each build container")
+	cmd.Flags().StringArrayVar(&buildFlags.Ulimits, "ulimit", nil, "Ulimit of each build container, in the form '<type>=<soft limit>[:<hard limit>]', such as 'nofile=1024:2048'.\nOverrides the ulimit of the same type in the container limits of the config."+stringArrayHelp("ulimit"))
 	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Connect detect and build containers to network")
+	cmd.Flags().StringVar(&buildFlags.MetadataFile, "metadata-file", "", "Path to write the metadata of the app image to, as JSON.\nThe metadata holds the image name, digest and additional tags, the run image, the buildpacks and process types\n  of the image, and the SBOM files written to --sbom-output-dir.")
+	cmd.Flags().IntVar(&buildFlags.Parallel, "parallel", 1, "Maximum number of images of --from-file to build at the same time")
 	cmd.Flags().BoolVar(&buildFlags.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&buildFlags.DockerHost, "docker-host", "",
 		`Address to docker daemon that will be exposed to the build container.
@@ -189,17 +381,30 @@ If not set (or set to empty string) the standard socket location will be used.
 Special value 'inherit' may be used in which case DOCKER_HOST environment variable will be used.
 This option may set DOCKER_HOST environment variable for the build container if needed.
 `)
+	cmd.Flags().BoolVar(&buildFlags.Lock, "lock", false, "Write the digests of the resolved builder, run image, lifecycle image and buildpacks to "+project.LockFileName+"\n  next to the project descriptor")
+	cmd.Flags().BoolVar(&buildFlags.Locked, "locked", false, "Fail the build if the resolved builder, run image, lifecycle image or buildpacks differ from\n  those recorded in "+project.LockFileName)
+	cmd.Flags().StringArrayVar(&buildFlags.Labels, "label", nil, "Label to set on the app image, in the form 'key=value'.\nOverrides the labels of the project descriptor, and the org.opencontainers.image.* labels derived from its project information.\nLabels are set once the image is exported, which changes the digest logged by the exporter."+stringArrayHelp("label"))
 	cmd.Flags().StringVar(&buildFlags.LifecycleImage, "lifecycle-image", cfg.LifecycleImage, `Custom lifecycle image to use for analysis, restore, and export when builder is untrusted.`)
+	cmd.Flags().StringSliceVar(&buildFlags.Platforms, "platform", nil, "Platform to build the app image for, in the form 'os/arch[/variant]', such as 'linux/arm64'.\nThe builder image for each platform is used. Building for multiple platforms publishes an image index\n  referencing the image of each platform, and requires --publish."+stringSliceHelp("platform"))
+	cmd.Flags().StringVar(&buildFlags.ProvenanceFile, "provenance-file", "", "Path to write the SLSA provenance of the app image to, as an in-toto statement.\nWith --publish, the provenance is also attached to the published image.")
+	cmd.Flags().StringSliceVar(&buildFlags.PhaseTimeouts, "phase-timeout", nil, "Longest time a lifecycle phase may run for, in the form '<phase>=<duration>', such as 'detect=2m,build=20m'.\nAccepted phases are "+strings.Join(build.PhaseNames, ", ")+". A phase that runs longer is stopped, and the build fails.\nTimeouts of phases other than create run each phase in its own container, even with a trusted builder."+stringSliceHelp("phase-timeout"))
 	cmd.Flags().StringVar(&buildFlags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
 	cmd.Flags().StringVarP(&buildFlags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	cmd.Flags().StringArrayVar(&buildFlags.Secrets, "secret", nil, "Secret file to copy into the detect and build containers, in the form 'id=<id>,src=<path>'.\nThe file is copied to /run/secrets/<id>, readable only by the build user, and is never written to the builder or app image."+stringArrayHelp("secret"))
 	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
 	cmd.Flags().StringSliceVarP(&buildFlags.AdditionalTags, "tag", "t", nil, "Additional tags to push the output image to.\nTags should be in the format 'image:tag' or 'repository/image:tag'."+stringSliceHelp("tag"))
+	cmd.Flags().DurationVar(&buildFlags.Timeout, "timeout", 0, "Longest time the build may run for, such as '30m'. A build that runs longer is stopped, and fails.\nDefaults to no timeout.")
 	cmd.Flags().BoolVar(&buildFlags.TrustBuilder, "trust-builder", false, "Trust the provided builder\nAll lifecycle phases will be run in a single container (if supported by the lifecycle).")
 	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount host volume into the build container, in the form '<host path>:<target path>[:<options>]'.\n- 'host path': Name of the volume or absolute directory path to mount.\n- 'target path': The path where the file or directory is available in the container.\n- 'options' (default \"ro\"): An optional comma separated list of mount options.\n    - \"ro\", volume contents are read-only.\n    - \"rw\", volume contents are readable and writeable.\n    - \"volume-opt=<key>=<value>\", can be specified more than once, takes a key-value pair consisting of the option name and its value."+stringArrayHelp("volume"))
 	cmd.Flags().StringVar(&buildFlags.Workspace, "workspace", "", "Location at which to mount the app dir in the build image")
 	cmd.Flags().IntVar(&buildFlags.GID, "gid", 0, `Override GID of user's group in the stack's build and run images. The provided value must be a positive number`)
 	cmd.Flags().StringVar(&buildFlags.PreviousImage, "previous-image", "", "Set previous image to a particular tag reference, digest reference, or (when performing a daemon build) image ID")
+	cmd.Flags().StringVar(&buildFlags.SigningKey, "signing-key", "", "Path to a cosign compatible private key to sign the published image with.\nEncrypted keys are decrypted with the password in the COSIGN_PASSWORD environment variable.")
 	cmd.Flags().StringVar(&buildFlags.SBOMDestinationDir, "sbom-output-dir", "", "Path to export SBoM contents.\nOmitting the flag will yield no SBoM content.")
+	cmd.Flags().StringVar(&buildFlags.OutputEvents, "output-events", "", "Write structured build events to stdout in the given format. Accepted values are jsonl.\nCombine with --quiet to suppress other output.")
+	cmd.Flags().StringVar(&buildFlags.Output, "output", "", "Additional output target for the app image, in the form 'oci-layout:<dir>'.\nThe image is written to the OCI image layout at <dir>, which is created if it doesn't exist.")
+	cmd.Flags().BoolVar(&buildFlags.Report, "report", false, "Print a summary of the time and resources spent in each lifecycle phase")
+	cmd.Flags().StringVar(&buildFlags.ReportFile, "report-file", "", "Path to write a JSON report of the time and resources spent in each lifecycle phase")
 	cmd.Flags().BoolVar(&buildFlags.Interactive, "interactive", false, "Launch a terminal UI to depict the build process")
 	if !cfg.Experimental {
 		cmd.Flags().MarkHidden("interactive")
@@ -215,17 +420,377 @@ func validateBuildFlags(flags *BuildFlags, cfg config.Config, packClient PackCli
 		return errors.New("cache-image flag requires the publish flag")
 	}
 
+	if flags.Cache.Build.Format == cache.CacheImage {
+		if flags.CacheImage != "" {
+			return errors.New("cache flag with an image format cannot be used with the cache-image flag")
+		}
+
+		if !flags.Publish {
+			return errors.New("image cache format requires the publish flag")
+		}
+	}
+
 	if flags.GID < 0 {
 		return errors.New("gid flag must be in the range of 0-2147483647")
 	}
 
+	if flags.OutputEvents != "" && flags.OutputEvents != eventsFormatJSONL {
+		return errors.Errorf("output-events format %s is not supported, accepted values are %s", style.Symbol(flags.OutputEvents), style.Symbol(eventsFormatJSONL))
+	}
+
+	if flags.Output != "" {
+		if _, err := parseOutput(flags.Output); err != nil {
+			return err
+		}
+
+		if flags.Publish {
+			return errors.New("output flag cannot be used with the publish flag")
+		}
+	}
+
+	if flags.SigningKey != "" && !flags.Publish {
+		return errors.New("signing-key flag requires the publish flag")
+	}
+
+	if flags.Lock && flags.Locked {
+		return errors.New("lock flag cannot be used with the locked flag")
+	}
+
+	if flags.KeepOnFailure && len(flags.Secrets) > 0 {
+		return errors.New("keep-on-failure flag cannot be used with the secret flag")
+	}
+
+	if flags.Timeout < 0 {
+		return errors.New("timeout flag must be positive")
+	}
+
+	if len(flags.Platforms) > 1 {
+		if flags.Lock || flags.Locked {
+			return errors.New("platform flag with multiple platforms cannot be used with the lock or locked flags")
+		}
+
+		if !flags.Publish {
+			return errors.New("platform flag with multiple platforms requires the publish flag")
+		}
+
+		if flags.Interactive {
+			return errors.New("platform flag with multiple platforms cannot be used with the interactive flag")
+		}
+
+		if flags.MetadataFile != "" {
+			return errors.New("platform flag with multiple platforms cannot be used with the metadata-file flag")
+		}
+	}
+
 	if flags.Interactive && !cfg.Experimental {
 		return client.NewExperimentError("Interactive mode is currently experimental.")
 	}
 
+	if err := validateAllFlags(flags); err != nil {
+		return err
+	}
+
+	if err := validateFromFileFlags(flags); err != nil {
+		return err
+	}
+
+	return validateDetectFlags(flags)
+}
+
+func validateAllFlags(flags *BuildFlags) error {
+	if !flags.All {
+		return nil
+	}
+
+	switch {
+	case len(flags.AdditionalTags) > 0:
+		return errors.New("all flag cannot be used with the tag flag")
+	case flags.Lock || flags.Locked:
+		return errors.New("all flag cannot be used with the lock or locked flags")
+	case flags.ProvenanceFile != "":
+		return errors.New("all flag cannot be used with the provenance-file flag")
+	case flags.MetadataFile != "":
+		return errors.New("all flag cannot be used with the metadata-file flag")
+	case flags.Report || flags.ReportFile != "":
+		return errors.New("all flag cannot be used with the report or report-file flags")
+	case flags.DetectOnly:
+		return errors.New("all flag cannot be used with the detect-only flag")
+	case flags.Interactive:
+		return errors.New("all flag cannot be used with the interactive flag")
+	case flags.Output != "":
+		return errors.New("all flag cannot be used with the output flag")
+	case len(flags.Platforms) > 1:
+		return errors.New("all flag cannot be used with multiple platforms")
+	}
+
+	return nil
+}
+
+func validateFromFileFlags(flags *BuildFlags) error {
+	if flags.FromFile == "" {
+		if flags.Parallel != 1 {
+			return errors.New("parallel flag requires the from-file flag")
+		}
+		return nil
+	}
+
+	switch {
+	case flags.Parallel < 1:
+		return errors.New("parallel flag must be at least 1")
+	case flags.All:
+		return errors.New("from-file flag cannot be used with the all flag")
+	case len(flags.AdditionalTags) > 0:
+		return errors.New("from-file flag cannot be used with the tag flag")
+	case flags.Lock || flags.Locked:
+		return errors.New("from-file flag cannot be used with the lock or locked flags")
+	case flags.ProvenanceFile != "":
+		return errors.New("from-file flag cannot be used with the provenance-file flag")
+	case flags.MetadataFile != "":
+		return errors.New("from-file flag cannot be used with the metadata-file flag")
+	case flags.DetectOnly:
+		return errors.New("from-file flag cannot be used with the detect-only flag")
+	case flags.Interactive:
+		return errors.New("from-file flag cannot be used with the interactive flag")
+	case flags.Output != "":
+		return errors.New("from-file flag cannot be used with the output flag")
+	case flags.Report || flags.ReportFile != "":
+		return errors.New("from-file flag cannot be used with the report or report-file flags")
+	}
+
 	return nil
 }
 
+// readBuildsFile returns the options of each build of the builds file of the from-file flag, from the options of the
+// flags.
+func readBuildsFile(cmd *cobra.Command, flags BuildFlags, cfg config.Config, opts client.BuildOptions) ([]client.BuildOptions, error) {
+	contents, err := ioutil.ReadFile(filepath.Clean(flags.FromFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading builds file %s", style.Symbol(flags.FromFile))
+	}
+
+	var file buildsFile
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, errors.Wrapf(err, "parsing builds file %s", style.Symbol(flags.FromFile))
+	}
+
+	if len(file.Builds) == 0 {
+		return nil, errors.Errorf("builds file %s has no builds", style.Symbol(flags.FromFile))
+	}
+
+	baseDir := filepath.Dir(flags.FromFile)
+	resolve := func(path, defaultPath string) string {
+		if path == "" {
+			return defaultPath
+		}
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	var builds []client.BuildOptions
+	for i, entry := range file.Builds {
+		if entry.Image == "" {
+			return nil, errors.Errorf("build %d of builds file %s must have an image", i+1, style.Symbol(flags.FromFile))
+		}
+
+		appPath := resolve(entry.Path, flags.AppPath)
+		descriptor, actualDescriptorPath, err := parseProjectToml(appPath, resolve(entry.Descriptor, flags.DescriptorPath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading project descriptor of image %s", style.Symbol(entry.Image))
+		}
+
+		builder := entry.Builder
+		if builder == "" {
+			builder = flags.Builder
+			if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
+				builder = descriptor.Build.Builder
+			}
+		}
+
+		env := map[string]string{}
+		for key, value := range opts.Env {
+			env[key] = value
+		}
+		for key, value := range entry.Env {
+			env[key] = value
+		}
+
+		entryOpts := opts
+		entryOpts.Image = entry.Image
+		entryOpts.AppPath = appPath
+		entryOpts.Builder = builder
+		entryOpts.Env = env
+		entryOpts.AdditionalTags = entry.Tags
+		entryOpts.ProjectDescriptor = descriptor
+		entryOpts.ProjectDescriptorBaseDir = filepath.Dir(actualDescriptorPath)
+		entryOpts.TrustBuilder = func(builder string) bool {
+			return isTrustedBuilder(cfg, builder) || flags.TrustBuilder
+		}
+		if entry.RunImage != "" {
+			entryOpts.RunImage = entry.RunImage
+		}
+		if len(entry.Buildpacks) > 0 {
+			entryOpts.Buildpacks = entry.Buildpacks
+		}
+		builds = append(builds, entryOpts)
+	}
+
+	return builds, nil
+}
+
+func validateDetectFlags(flags *BuildFlags) error {
+	if flags.DetectFormat != detectFormatHuman && flags.DetectFormat != detectFormatJSON {
+		return errors.Errorf("detect-format %s is not supported, accepted values are %s and %s", style.Symbol(flags.DetectFormat), style.Symbol(detectFormatHuman), style.Symbol(detectFormatJSON))
+	}
+
+	if !flags.DetectOnly {
+		if flags.DetectFormat != detectFormatHuman {
+			return errors.New("detect-format flag requires the detect-only flag")
+		}
+		return nil
+	}
+
+	switch {
+	case flags.Publish:
+		return errors.New("detect-only flag cannot be used with the publish flag")
+	case flags.Output != "":
+		return errors.New("detect-only flag cannot be used with the output flag")
+	case flags.Lock:
+		return errors.New("detect-only flag cannot be used with the lock flag")
+	case flags.SBOMDestinationDir != "":
+		return errors.New("detect-only flag cannot be used with the sbom-output-dir flag")
+	case flags.Report || flags.ReportFile != "":
+		return errors.New("detect-only flag cannot be used with the report or report-file flags")
+	case flags.ProvenanceFile != "":
+		return errors.New("detect-only flag cannot be used with the provenance-file flag")
+	case flags.MetadataFile != "":
+		return errors.New("detect-only flag cannot be used with the metadata-file flag")
+	case flags.Interactive:
+		return errors.New("detect-only flag cannot be used with the interactive flag")
+	case flags.ClearCache:
+		return errors.New("detect-only flag cannot be used with the clear-cache flag")
+	case len(flags.Platforms) > 1:
+		return errors.New("detect-only flag cannot be used with multiple platforms")
+	}
+
+	return nil
+}
+
+// parseOutput returns the OCI layout directory of an output target in the form 'oci-layout:<dir>'.
+func parseOutput(output string) (string, error) {
+	if output == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(output, outputOCILayoutPrefix) {
+		return "", errors.Errorf("output %s is not supported, expected the form %s", style.Symbol(output), style.Symbol(outputOCILayoutPrefix+"<dir>"))
+	}
+
+	dir := strings.TrimPrefix(output, outputOCILayoutPrefix)
+	if dir == "" {
+		return "", errors.Errorf("output %s must specify a directory", style.Symbol(output))
+	}
+
+	return dir, nil
+}
+
+// mergeUlimits returns the ulimits of the config, replaced by the ulimits of the same type in flagUlimits.
+func mergeUlimits(cfg config.Config, flagUlimits []string) []string {
+	if cfg.ContainerLimits == nil || len(cfg.ContainerLimits.Ulimits) == 0 {
+		return flagUlimits
+	}
+
+	ulimitType := func(ulimit string) string {
+		return strings.SplitN(ulimit, "=", 2)[0]
+	}
+
+	overridden := map[string]bool{}
+	for _, ulimit := range flagUlimits {
+		overridden[ulimitType(ulimit)] = true
+	}
+
+	var ulimits []string
+	for _, ulimit := range cfg.ContainerLimits.Ulimits {
+		if !overridden[ulimitType(ulimit)] {
+			ulimits = append(ulimits, ulimit)
+		}
+	}
+	return append(ulimits, flagUlimits...)
+}
+
+func parseLabels(labels []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, label := range labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("label %s has invalid format, expected the form %s", style.Symbol(label), style.Symbol("key=value"))
+		}
+		parsed[kv[0]] = kv[1]
+	}
+	return parsed, nil
+}
+
+// parsePhaseTimeouts returns the timeouts of the phases in the form '<phase>=<duration>', keyed by phase.
+func parsePhaseTimeouts(phaseTimeouts []string) (map[string]time.Duration, error) {
+	parsed := map[string]time.Duration{}
+	for _, phaseTimeout := range phaseTimeouts {
+		kv := strings.SplitN(phaseTimeout, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("phase-timeout %s has invalid format, expected the form %s", style.Symbol(phaseTimeout), style.Symbol("<phase>=<duration>"))
+		}
+		if !isPhaseName(kv[0]) {
+			return nil, errors.Errorf("phase-timeout %s has unknown phase %s, accepted phases are %s", style.Symbol(phaseTimeout), style.Symbol(kv[0]), strings.Join(build.PhaseNames, ", "))
+		}
+		timeout, err := time.ParseDuration(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing phase-timeout %s", style.Symbol(phaseTimeout))
+		}
+		if timeout <= 0 {
+			return nil, errors.Errorf("phase-timeout %s must be positive", style.Symbol(phaseTimeout))
+		}
+		parsed[kv[0]] = timeout
+	}
+	return parsed, nil
+}
+
+func isPhaseName(name string) bool {
+	for _, phase := range build.PhaseNames {
+		if name == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSecrets(secrets []string) ([]client.Secret, error) {
+	var parsed []client.Secret
+	for _, secret := range secrets {
+		var s client.Secret
+		for _, field := range strings.Split(secret, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("secret %s has invalid format, expected the form %s", style.Symbol(secret), style.Symbol("id=<id>,src=<path>"))
+			}
+			switch kv[0] {
+			case "id":
+				s.ID = kv[1]
+			case "src", "source":
+				s.Source = kv[1]
+			default:
+				return nil, errors.Errorf("secret %s has unknown key %s", style.Symbol(secret), style.Symbol(kv[0]))
+			}
+		}
+		if s.ID == "" || s.Source == "" {
+			return nil, errors.Errorf("secret %s must have an id and a src", style.Symbol(secret))
+		}
+		parsed = append(parsed, s)
+	}
+	return parsed, nil
+}
+
 func parseEnv(envFiles []string, envVars []string) (map[string]string, error) {
 	env := map[string]string{}
 
@@ -271,6 +836,20 @@ func addEnvVar(env map[string]string, item string) map[string]string {
 	return env
 }
 
+// lockFilePath returns the path of the lock file next to the project descriptor, or in the app directory when the
+// project has no descriptor.
+func lockFilePath(appPath, descriptorPath string) string {
+	if descriptorPath != "" {
+		return filepath.Join(filepath.Dir(descriptorPath), project.LockFileName)
+	}
+
+	if fi, err := os.Stat(appPath); err == nil && !fi.IsDir() {
+		appPath = filepath.Dir(appPath)
+	}
+
+	return filepath.Join(appPath, project.LockFileName)
+}
+
 func parseProjectToml(appPath, descriptorPath string) (projectTypes.Descriptor, string, error) {
 	actualPath := descriptorPath
 	computePath := descriptorPath == ""
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	xterm "golang.org/x/term"
+
+	"github.com/buildpacks/pack/internal/term"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type BuildDebugFlags struct {
+	Shell   string
+	Cleanup bool
+}
+
+// BuildDebug starts a shell in the environment of a lifecycle phase kept by pack build --keep-on-failure
+func BuildDebug(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags BuildDebugFlags
+
+	cmd := &cobra.Command{
+		Use:   "debug <container-id>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Start a shell in the environment of a failed build",
+		Example: "pack build my-app --keep-on-failure\n" +
+			"pack build debug 4f1c2d3e5a6b",
+		Long: "Start a shell in a container with the image, volumes, environment and user of a lifecycle phase that failed " +
+			"during `pack build --keep-on-failure`. The layers and app of the build are mounted at the same paths as in the failed phase, " +
+			"and the command it ran is printed so that it can be run again from the shell.\n\n" +
+			"Use `--cleanup` to remove the failed container and the volumes of its build once the shell exits.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			in := cmd.InOrStdin()
+			opts := client.DebugBuildOptions{
+				ContainerID: args[0],
+				Shell:       flags.Shell,
+				Cleanup:     flags.Cleanup,
+				In:          in,
+				Out:         logger.Writer(),
+				ErrOut:      logger.Writer(),
+			}
+
+			if f, ok := in.(*os.File); ok {
+				if fd, isTerm := term.IsTerminal(f); isTerm {
+					state, err := xterm.MakeRaw(int(fd))
+					if err != nil {
+						return errors.Wrap(err, "setting terminal to raw mode")
+					}
+					defer xterm.Restore(int(fd), state)
+
+					opts.TTY = true
+					if width, height, err := xterm.GetSize(int(fd)); err == nil {
+						opts.TerminalSize = [2]uint{uint(height), uint(width)}
+					}
+				}
+			}
+
+			if err := packClient.DebugBuild(cmd.Context(), opts); err != nil {
+				return errors.Wrap(err, "failed to debug build")
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.Shell, "shell", "/bin/sh", "Shell to start in the debug container")
+	cmd.Flags().BoolVar(&flags.Cleanup, "cleanup", false, "Remove the failed container and the volumes of its build once the shell exits")
+	AddHelpFlag(cmd, "debug")
+	return cmd
+}
@@ -0,0 +1,96 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildDebugCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BuildDebugCommand", testBuildDebugCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBuildDebugCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.Build(logger, config.Config{}, mockClient)
+		command.SetIn(strings.NewReader("ls\n"))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildDebug", func() {
+		it("starts a shell in the failed container", func() {
+			mockClient.EXPECT().
+				DebugBuild(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, opts client.DebugBuildOptions) error {
+					h.AssertEq(t, opts.ContainerID, "some-container")
+					h.AssertEq(t, opts.Shell, "/bin/sh")
+					h.AssertFalse(t, opts.Cleanup)
+					h.AssertFalse(t, opts.TTY)
+					return nil
+				})
+
+			command.SetArgs([]string{"debug", "some-container"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("passes the shell and cleanup flags", func() {
+			mockClient.EXPECT().
+				DebugBuild(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, opts client.DebugBuildOptions) error {
+					h.AssertEq(t, opts.Shell, "/bin/bash")
+					h.AssertTrue(t, opts.Cleanup)
+					return nil
+				})
+
+			command.SetArgs([]string{"debug", "some-container", "--shell", "/bin/bash", "--cleanup"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("errors when debugging fails", func() {
+			mockClient.EXPECT().
+				DebugBuild(gomock.Any(), gomock.Any()).
+				Return(errors.New("no such container"))
+
+			command.SetArgs([]string{"debug", "some-container"})
+			h.AssertError(t, command.Execute(), "failed to debug build: no such container")
+		})
+
+		it("requires a container", func() {
+			command.SetArgs([]string{"debug"})
+			h.AssertError(t, command.Execute(), "accepts 1 arg(s), received 0")
+		})
+	})
+}
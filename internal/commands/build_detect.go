@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/buildpacks/lifecycle/buildpack"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type detectOutput struct {
+	Group []buildpack.GroupBuildpack `json:"group"`
+	Plan  []detectPlanEntry          `json:"plan"`
+}
+
+type detectPlanEntry struct {
+	Providers []buildpack.GroupBuildpack `json:"providers"`
+	Requires  []buildpack.Require        `json:"requires"`
+}
+
+func printDetectResult(logger logging.Logger, result client.DetectResult, format string) error {
+	if format == detectFormatJSON {
+		output := detectOutput{Group: result.Group, Plan: []detectPlanEntry{}}
+		for _, entry := range result.Plan.Entries {
+			output.Plan = append(output.Plan, detectPlanEntry{Providers: entry.Providers, Requires: entry.Requires})
+		}
+
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "marshaling detect result")
+		}
+		_, err = fmt.Fprintln(logger.Writer(), string(data))
+		return err
+	}
+
+	logger.Info("Detected buildpacks:")
+	for _, bp := range result.Group {
+		if bp.Optional {
+			logger.Infof("  %s (optional)", style.Symbol(bp.String()))
+		} else {
+			logger.Infof("  %s", style.Symbol(bp.String()))
+		}
+	}
+
+	logger.Info("")
+	if len(result.Plan.Entries) == 0 {
+		logger.Info("Build plan: (none)")
+		return nil
+	}
+
+	logger.Info("Build plan:")
+	for _, entry := range result.Plan.Entries {
+		var requires, providers []string
+		for _, require := range entry.Requires {
+			if require.Version != "" {
+				requires = append(requires, fmt.Sprintf("%s@%s", require.Name, require.Version))
+			} else {
+				requires = append(requires, require.Name)
+			}
+		}
+		for _, provider := range entry.Providers {
+			providers = append(providers, provider.String())
+		}
+
+		logger.Infof("  %s provided by %s", style.Symbol(strings.Join(requires, ", ")), style.Symbol(strings.Join(providers, ", ")))
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/events"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func combineEventHandlers(handlers []events.Handler) events.Handler {
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	return func(e events.Event) {
+		for _, handler := range handlers {
+			handler(e)
+		}
+	}
+}
+
+func printBuildReport(logger logging.Logger, report client.BuildReport) {
+	tw := tabwriter.NewWriter(logging.GetWriterForLevel(logger, logging.InfoLevel), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tDURATION\tCREATE\tSTART\tWAIT\tCOPIED\tLAYERS")
+	for _, phase := range report.Phases {
+		layersSize := "-"
+		if phase.LayersSize > 0 {
+			layersSize = humanize.Bytes(uint64(phase.LayersSize))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			phase.Name,
+			formatReportDuration(phase.Duration),
+			formatReportDuration(phase.ContainerCreate),
+			formatReportDuration(phase.ContainerStart),
+			formatReportDuration(phase.ContainerWait),
+			humanize.Bytes(uint64(phase.BytesCopied)),
+			layersSize,
+		)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%s\t\t\t\t\t\n", formatReportDuration(report.TotalDuration()))
+	tw.Flush()
+}
+
+func writeBuildReport(path string, report client.BuildReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling build report")
+	}
+
+	return errors.Wrapf(ioutil.WriteFile(path, data, 0600), "writing build report to %s", path)
+}
+
+func formatReportDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -2,14 +2,19 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/buildpacks/lifecycle/api"
+	"github.com/buildpacks/lifecycle/buildpack"
+	"github.com/buildpacks/lifecycle/platform"
 	"github.com/golang/mock/gomock"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
@@ -20,7 +25,9 @@ import (
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/commands/testmocks"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
@@ -783,6 +790,829 @@ builder = "my-builder"
 				h.AssertNil(t, command.Execute())
 			})
 		})
+
+		when("--output-events is provided", func() {
+			when("the format is jsonl", func() {
+				it("sets an event handler", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEventHandler()).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output-events", "jsonl"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("the format is not supported", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output-events", "xml"})
+					err := command.Execute()
+					h.AssertError(t, err, "output-events format 'xml' is not supported")
+				})
+			})
+		})
+
+		when("--cache is provided", func() {
+			when("the format is bind", func() {
+				it("sets the bind cache", func() {
+					cacheDir, err := filepath.Abs("/some/cache-dir")
+					h.AssertNil(t, err)
+
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithCache(cache.CacheOpts{
+							Build: cache.CacheInfo{Format: cache.CacheBind, Source: cacheDir},
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--cache", "type=build,format=bind,source=/some/cache-dir"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("the format is image", func() {
+				it("requires --publish", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--cache", "type=build,format=image,source=some/cache-image"})
+					err := command.Execute()
+					h.AssertError(t, err, "image cache format requires the publish flag")
+				})
+
+				it("cannot be used with --cache-image", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--cache-image", "some/cache-image", "--cache", "type=build,format=image,source=some/cache-image"})
+					err := command.Execute()
+					h.AssertError(t, err, "cache flag with an image format cannot be used with the cache-image flag")
+				})
+			})
+
+			when("the value is invalid", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--cache", "type=build,format=bind"})
+					err := command.Execute()
+					h.AssertError(t, err, "cache source is required for bind caches")
+				})
+			})
+		})
+
+		when("--output is provided", func() {
+			when("the target is an OCI layout", func() {
+				it("sets the OCI layout directory", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithOCILayoutDir("some/layout-dir")).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output", "oci-layout:some/layout-dir"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("the target is not supported", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output", "tar:some/file.tar"})
+					err := command.Execute()
+					h.AssertError(t, err, "output 'tar:some/file.tar' is not supported")
+				})
+			})
+
+			when("the directory is missing", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output", "oci-layout:"})
+					err := command.Execute()
+					h.AssertError(t, err, "output 'oci-layout:' must specify a directory")
+				})
+			})
+
+			when("--publish is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output", "oci-layout:some/layout-dir", "--publish"})
+					err := command.Execute()
+					h.AssertError(t, err, "output flag cannot be used with the publish flag")
+				})
+			})
+		})
+
+		when("--platform is provided", func() {
+			it("sets the platforms", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatforms([]string{"linux/amd64", "linux/arm64"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--platform", "linux/amd64,linux/arm64", "--publish"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("allows a single platform without publishing", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatforms([]string{"linux/arm64"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--platform", "linux/arm64"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("multiple platforms are provided without --publish", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--platform", "linux/amd64", "--platform", "linux/arm64"})
+					err := command.Execute()
+					h.AssertError(t, err, "platform flag with multiple platforms requires the publish flag")
+				})
+			})
+		})
+
+		when("--all is provided", func() {
+			var descriptorPath string
+
+			it.Before(func() {
+				descriptorDir, err := ioutil.TempDir("", "build-all")
+				h.AssertNil(t, err)
+				descriptorPath = filepath.Join(descriptorDir, "project.toml")
+				h.AssertNil(t, ioutil.WriteFile(descriptorPath, []byte(`
+[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "some/api"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "some/web"
+`), 0600))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(filepath.Dir(descriptorPath)))
+			})
+
+			it("builds all apps of the project descriptor", func() {
+				mockClient.EXPECT().
+					BuildAll(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.Image, "")
+						h.AssertEq(t, opts.ProjectDescriptorBaseDir, filepath.Dir(descriptorPath))
+						h.AssertEq(t, len(opts.ProjectDescriptor.Apps), 2)
+						return nil
+					})
+
+				command.SetArgs([]string{"--all", "--builder", "my-builder", "--descriptor", descriptorPath})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully built image 'some/api'")
+				h.AssertContains(t, outBuf.String(), "Successfully built image 'some/web'")
+			})
+
+			it("errors when building fails", func() {
+				mockClient.EXPECT().
+					BuildAll(gomock.Any(), gomock.Any()).
+					Return(errors.New("building app 'services/web': some error"))
+
+				command.SetArgs([]string{"--all", "--builder", "my-builder", "--descriptor", descriptorPath})
+				h.AssertError(t, command.Execute(), "failed to build: building app 'services/web': some error")
+			})
+
+			it("doesn't accept an image name", func() {
+				command.SetArgs([]string{"image", "--all", "--builder", "my-builder", "--descriptor", descriptorPath})
+				h.AssertError(t, command.Execute(), `unknown command "image" for "build"`)
+			})
+
+			when("--tag is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--all", "--builder", "my-builder", "--tag", "some/tag"})
+					h.AssertError(t, command.Execute(), "all flag cannot be used with the tag flag")
+				})
+			})
+
+			when("--lock is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--all", "--builder", "my-builder", "--lock"})
+					h.AssertError(t, command.Execute(), "all flag cannot be used with the lock or locked flags")
+				})
+			})
+
+			when("--detect-only is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--all", "--builder", "my-builder", "--detect-only"})
+					h.AssertError(t, command.Execute(), "all flag cannot be used with the detect-only flag")
+				})
+			})
+
+			when("--report is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--all", "--builder", "my-builder", "--report"})
+					h.AssertError(t, command.Execute(), "all flag cannot be used with the report or report-file flags")
+				})
+			})
+
+			when("--report-file is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--all", "--builder", "my-builder", "--report-file", "report.json"})
+					h.AssertError(t, command.Execute(), "all flag cannot be used with the report or report-file flags")
+				})
+			})
+		})
+
+		when("--from-file is provided", func() {
+			var buildsDir, buildsPath string
+
+			it.Before(func() {
+				var err error
+				buildsDir, err = ioutil.TempDir("", "build-from-file")
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.MkdirAll(filepath.Join(buildsDir, "web"), 0755))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(buildsDir, "web", "project.toml"), []byte(`
+[build]
+builder = "web-builder"
+`), 0600))
+
+				buildsPath = filepath.Join(buildsDir, "builds.yaml")
+				h.AssertNil(t, ioutil.WriteFile(buildsPath, []byte(`
+builds:
+  - image: some/api
+    path: api
+    builder: api-builder
+    env:
+      QUEUE: jobs
+    tags:
+      - some/api:v1
+  - image: some/web
+    path: web
+    buildpacks:
+      - some/buildpack
+`), 0600))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(buildsDir))
+			})
+
+			it("builds each image of the file", func() {
+				mockClient.EXPECT().
+					BuildMany(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildManyOptions) error {
+						h.AssertEq(t, opts.Parallel, 3)
+						h.AssertEq(t, len(opts.Builds), 2)
+
+						api, web := opts.Builds[0], opts.Builds[1]
+						h.AssertEq(t, api.Image, "some/api")
+						h.AssertEq(t, api.AppPath, filepath.Join(buildsDir, "api"))
+						h.AssertEq(t, api.Builder, "api-builder")
+						h.AssertEq(t, api.Env, map[string]string{"SHARED": "1", "QUEUE": "jobs"})
+						h.AssertEq(t, api.AdditionalTags, []string{"some/api:v1"})
+						h.AssertEq(t, api.Buildpacks, []string{"flag/buildpack"})
+
+						h.AssertEq(t, web.Image, "some/web")
+						h.AssertEq(t, web.Builder, "web-builder")
+						h.AssertEq(t, web.ProjectDescriptorBaseDir, filepath.Join(buildsDir, "web"))
+						h.AssertEq(t, web.Buildpacks, []string{"some/buildpack"})
+						return nil
+					})
+
+				command.SetArgs([]string{"--from-file", buildsPath, "--parallel", "3", "--env", "SHARED=1", "--buildpack", "flag/buildpack"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully built image 'some/api'")
+				h.AssertContains(t, outBuf.String(), "Successfully built image 'some/web'")
+			})
+
+			it("errors when building fails", func() {
+				mockClient.EXPECT().
+					BuildMany(gomock.Any(), gomock.Any()).
+					Return(errors.New("1 of 2 builds failed: 'some/web': some error"))
+
+				command.SetArgs([]string{"--from-file", buildsPath})
+				h.AssertError(t, command.Execute(), "failed to build: 1 of 2 builds failed: 'some/web': some error")
+			})
+
+			it("errors when a build has no image", func() {
+				h.AssertNil(t, ioutil.WriteFile(buildsPath, []byte("builds:\n  - path: api\n"), 0600))
+
+				command.SetArgs([]string{"--from-file", buildsPath, "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "build 1 of builds file")
+			})
+
+			it("errors when the file has an unknown field", func() {
+				h.AssertNil(t, ioutil.WriteFile(buildsPath, []byte("builds:\n  - image: some/app\n    tag: v1\n"), 0600))
+
+				command.SetArgs([]string{"--from-file", buildsPath, "--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "parsing builds file")
+			})
+
+			it("doesn't accept an image name", func() {
+				command.SetArgs([]string{"image", "--from-file", buildsPath})
+				h.AssertError(t, command.Execute(), `unknown command "image" for "build"`)
+			})
+
+			when("--all is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--from-file", buildsPath, "--all"})
+					h.AssertError(t, command.Execute(), "from-file flag cannot be used with the all flag")
+				})
+			})
+
+			when("--parallel is less than 1", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--from-file", buildsPath, "--parallel", "0"})
+					h.AssertError(t, command.Execute(), "parallel flag must be at least 1")
+				})
+			})
+
+			when("--tag is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--from-file", buildsPath, "--tag", "some/tag"})
+					h.AssertError(t, command.Execute(), "from-file flag cannot be used with the tag flag")
+				})
+			})
+		})
+
+		when("--parallel is provided without --from-file", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--parallel", "2"})
+				h.AssertError(t, command.Execute(), "parallel flag requires the from-file flag")
+			})
+		})
+
+		when("--lock is provided", func() {
+			it("writes the lock file next to the project descriptor", func() {
+				descriptorDir, err := ioutil.TempDir("", "build-lock")
+				h.AssertNil(t, err)
+				defer os.RemoveAll(descriptorDir)
+				descriptorPath := filepath.Join(descriptorDir, "project.toml")
+				h.AssertNil(t, ioutil.WriteFile(descriptorPath, []byte("[project]\nname = \"some-app\"\n"), 0600))
+
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLockFile(filepath.Join(descriptorDir, "project.lock"), false)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--descriptor", descriptorPath, "--lock"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("writes the lock file in the app directory without a project descriptor", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLockFile(filepath.Join("some", "app", "project.lock"), false)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", filepath.Join("some", "app"), "--lock"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("--locked is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--lock", "--locked"})
+					h.AssertError(t, command.Execute(), "lock flag cannot be used with the locked flag")
+				})
+			})
+
+			when("multiple platforms are provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--lock", "--publish", "--platform", "linux/amd64,linux/arm64"})
+					h.AssertError(t, command.Execute(), "platform flag with multiple platforms cannot be used with the lock or locked flags")
+				})
+			})
+		})
+
+		when("--locked is provided", func() {
+			it("verifies the lock file", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLockFile("project.lock", true)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--locked"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--signing-key is provided", func() {
+			it("sets the signing key", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.SigningKey, "some/cosign.key")
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--signing-key", "some/cosign.key", "--publish"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("--publish is not provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--signing-key", "some/cosign.key"})
+					err := command.Execute()
+					h.AssertError(t, err, "signing-key flag requires the publish flag")
+				})
+			})
+		})
+
+		when("--provenance-file is provided", func() {
+			it("sets the provenance file", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.ProvenanceFile, "provenance.json")
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--provenance-file", "provenance.json"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--metadata-file is provided", func() {
+			it("sets the metadata file", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.MetadataFile, "metadata.json")
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--metadata-file", "metadata.json"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("multiple platforms are provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--platform", "linux/amd64,linux/arm64", "--metadata-file", "metadata.json"})
+					h.AssertError(t, command.Execute(), "platform flag with multiple platforms cannot be used with the metadata-file flag")
+				})
+			})
+		})
+
+		when("--label is provided", func() {
+			it("sets the labels", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.Labels, map[string]string{
+							"com.example.team": "payments",
+							"com.example.url":  "https://example.com/?a=b",
+						})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--label", "com.example.team=payments", "--label", "com.example.url=https://example.com/?a=b"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the label has an invalid format", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--label", "com.example.team"})
+					h.AssertError(t, command.Execute(), "label 'com.example.team' has invalid format, expected the form 'key=value'")
+				})
+			})
+		})
+
+		when("--timeout is provided", func() {
+			it("sets the timeout of the build", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.Timeout, 30*time.Minute)
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timeout", "30m"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the timeout is negative", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--timeout", "-1m"})
+					h.AssertError(t, command.Execute(), "timeout flag must be positive")
+				})
+			})
+		})
+
+		when("--phase-timeout is provided", func() {
+			it("sets the timeouts of the phases", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.PhaseTimeouts, map[string]time.Duration{"detect": 2 * time.Minute, "build": 20 * time.Minute})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "detect=2m,build=20m"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the phase timeout has an invalid format", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "20m"})
+					h.AssertError(t, command.Execute(), "phase-timeout '20m' has invalid format, expected the form '<phase>=<duration>'")
+				})
+			})
+
+			when("the phase is unknown", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "compile=20m"})
+					h.AssertError(t, command.Execute(), "phase-timeout 'compile=20m' has unknown phase 'compile'")
+				})
+			})
+
+			when("the duration is invalid", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "build=forever"})
+					h.AssertError(t, command.Execute(), "parsing phase-timeout 'build=forever'")
+				})
+			})
+		})
+
+		when("resource limits are provided", func() {
+			it("sets the limits of the build containers", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.ContainerConfig.Memory, int64(2*1024*1024*1024))
+						h.AssertEq(t, opts.ContainerConfig.CPUs, 1.5)
+						h.AssertEq(t, opts.ContainerConfig.PidsLimit, int64(512))
+						h.AssertEq(t, opts.ContainerConfig.Ulimits, []string{"nofile=1024:2048"})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "2g", "--cpus", "1.5", "--pids-limit", "512", "--ulimit", "nofile=1024:2048"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the memory limit is invalid", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "lots"})
+					h.AssertError(t, command.Execute(), "parsing memory limit 'lots'")
+				})
+			})
+
+			when("container limits are set in the config", func() {
+				it.Before(func() {
+					cfg.ContainerLimits = &config.ContainerLimits{
+						Memory:    "1g",
+						CPUs:      2,
+						PidsLimit: 128,
+						Ulimits:   []string{"nofile=1024", "nproc=64"},
+					}
+					command = commands.Build(logger, cfg, mockClient)
+				})
+
+				it("uses them by default", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+							h.AssertEq(t, opts.ContainerConfig.Memory, int64(1024*1024*1024))
+							h.AssertEq(t, opts.ContainerConfig.CPUs, 2.0)
+							h.AssertEq(t, opts.ContainerConfig.PidsLimit, int64(128))
+							h.AssertEq(t, opts.ContainerConfig.Ulimits, []string{"nofile=1024", "nproc=64"})
+							return nil
+						})
+
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("overrides them with the flags", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+							h.AssertEq(t, opts.ContainerConfig.Memory, int64(512*1024*1024))
+							h.AssertEq(t, opts.ContainerConfig.Ulimits, []string{"nproc=64", "nofile=4096"})
+							return nil
+						})
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "512m", "--ulimit", "nofile=4096"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+		})
+
+		when("--secret is provided", func() {
+			it("sets the secrets", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.Secrets, []client.Secret{
+							{ID: "npm", Source: "./npmrc"},
+							{ID: "pip", Source: "/some/pip.conf"},
+						})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "id=npm,src=./npmrc", "--secret", "source=/some/pip.conf,id=pip"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			when("the secret has an unknown key", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "id=npm,target=/npmrc"})
+					h.AssertError(t, command.Execute(), "secret 'id=npm,target=/npmrc' has unknown key 'target'")
+				})
+			})
+
+			when("the secret has no source", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "id=npm"})
+					h.AssertError(t, command.Execute(), "secret 'id=npm' must have an id and a src")
+				})
+			})
+
+			when("the secret has an invalid format", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "npm"})
+					h.AssertError(t, command.Execute(), "secret 'npm' has invalid format, expected the form 'id=<id>,src=<path>'")
+				})
+			})
+
+			when("--keep-on-failure is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "id=npm,src=./npmrc", "--keep-on-failure"})
+					h.AssertError(t, command.Execute(), "keep-on-failure flag cannot be used with the secret flag")
+				})
+			})
+		})
+
+		when("--keep-on-failure is provided", func() {
+			it("keeps the container of a failed phase", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithKeepOnFailure(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--keep-on-failure"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--check-git-dirty is provided", func() {
+			it("records the state of the git working tree", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCheckGitDirty(true)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--check-git-dirty"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--detect-only is provided", func() {
+			var result client.DetectResult
+
+			it.Before(func() {
+				result = client.DetectResult{
+					Group: []buildpack.GroupBuildpack{
+						{ID: "some/bp", Version: "1.2.3"},
+						{ID: "other/bp", Version: "4.5.6", Optional: true},
+					},
+					Plan: platform.BuildPlan{Entries: []platform.BuildPlanEntry{{
+						Providers: []buildpack.GroupBuildpack{{ID: "some/bp", Version: "1.2.3"}},
+						Requires:  []buildpack.Require{{Name: "some-dep", Version: "7.8.9"}},
+					}}},
+				}
+			})
+
+			it("prints the detected buildpacks and build plan", func() {
+				mockClient.EXPECT().
+					Detect(gomock.Any(), EqBuildOptionsWithImage("my-builder", "image")).
+					Return(result, nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Detected buildpacks:\n  'some/bp@1.2.3'\n  'other/bp@4.5.6' (optional)")
+				h.AssertContains(t, outBuf.String(), "Build plan:\n  'some-dep@7.8.9' provided by 'some/bp@1.2.3'")
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
+			})
+
+			it("prints the result as json", func() {
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					Return(result, nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--detect-format", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var output map[string][]map[string]interface{}
+				h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &output))
+				h.AssertEq(t, len(output["group"]), 2)
+				h.AssertEq(t, output["group"][1]["id"], "other/bp")
+				h.AssertEq(t, output["group"][1]["optional"], true)
+				h.AssertEq(t, len(output["plan"]), 1)
+				h.AssertEq(t, output["plan"][0]["requires"], []interface{}{map[string]interface{}{"name": "some-dep", "version": "7.8.9", "metadata": nil}})
+			})
+
+			it("fails when detection fails", func() {
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					Return(client.DetectResult{}, errors.New("no buildpacks participating"))
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only"})
+				h.AssertError(t, command.Execute(), "failed to detect: no buildpacks participating")
+			})
+
+			when("--publish is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--publish"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the publish flag")
+				})
+			})
+
+			when("--lock is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--lock"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the lock flag")
+				})
+			})
+
+			when("--provenance-file is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--provenance-file", "provenance.json"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the provenance-file flag")
+				})
+			})
+
+			when("--clear-cache is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--clear-cache"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the clear-cache flag")
+				})
+			})
+
+			when("--metadata-file is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--metadata-file", "metadata.json"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the metadata-file flag")
+				})
+			})
+
+			when("--report is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--report"})
+					h.AssertError(t, command.Execute(), "detect-only flag cannot be used with the report or report-file flags")
+				})
+			})
+		})
+
+		when("--detect-format is provided", func() {
+			when("the format is not supported", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only", "--detect-format", "yaml"})
+					h.AssertError(t, command.Execute(), "detect-format 'yaml' is not supported, accepted values are 'human' and 'json'")
+				})
+			})
+
+			when("--detect-only is not provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-format", "json"})
+					h.AssertError(t, command.Execute(), "detect-format flag requires the detect-only flag")
+				})
+			})
+		})
+
+		when("--report-file is provided", func() {
+			var tmpDir string
+
+			it.Before(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "build-report")
+				h.AssertNil(t, err)
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(tmpDir))
+			})
+
+			it("writes the phases reported by the build", func() {
+				reportPath := filepath.Join(tmpDir, "report.json")
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithEventHandler()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.MeasureLayers, true)
+						opts.EventHandler(events.Event{
+							Type:     events.PhaseFinished,
+							Phase:    "detector",
+							Duration: time.Second,
+							Stats:    &events.PhaseStats{BytesCopied: 1024},
+						})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--report-file", reportPath})
+				h.AssertNil(t, command.Execute())
+
+				contents, err := ioutil.ReadFile(reportPath)
+				h.AssertNil(t, err)
+				h.AssertContains(t, string(contents), `"name": "detector"`)
+				h.AssertContains(t, string(contents), `"bytesCopied": 1024`)
+			})
+		})
+
+		when("--report is provided", func() {
+			it("prints a summary of the phases", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithEventHandler()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "detector", Duration: time.Second})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--report"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "PHASE")
+				h.AssertContains(t, outBuf.String(), "detector")
+			})
+		})
 	})
 }
 
@@ -931,6 +1761,69 @@ func EqBuildOptionsWithSBOMOutputDir(s string) interface{} {
 	}
 }
 
+func EqBuildOptionsWithCache(cacheOpts cache.CacheOpts) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Cache=%s", cacheOpts.String()),
+		equals: func(o client.BuildOptions) bool {
+			return o.Cache == cacheOpts
+		},
+	}
+}
+
+func EqBuildOptionsWithPlatforms(platforms []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Platforms=%s", platforms),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.Platforms, platforms)
+		},
+	}
+}
+
+func EqBuildOptionsWithLockFile(lockFile string, locked bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("LockFile=%s Locked=%t", lockFile, locked),
+		equals: func(o client.BuildOptions) bool {
+			return o.LockFile == lockFile && o.Locked == locked
+		},
+	}
+}
+
+func EqBuildOptionsWithKeepOnFailure(keepOnFailure bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("KeepOnFailure=%t", keepOnFailure),
+		equals: func(o client.BuildOptions) bool {
+			return o.KeepOnFailure == keepOnFailure
+		},
+	}
+}
+
+func EqBuildOptionsWithCheckGitDirty(checkGitDirty bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CheckGitDirty=%t", checkGitDirty),
+		equals: func(o client.BuildOptions) bool {
+			return o.CheckGitDirty == checkGitDirty
+		},
+	}
+}
+
+func EqBuildOptionsWithOCILayoutDir(dir string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("OCILayoutDir=%s", dir),
+		equals: func(o client.BuildOptions) bool {
+			return o.OCILayoutDir == dir
+		},
+	}
+}
+
+func EqBuildOptionsWithEventHandler() gomock.Matcher {
+	return buildOptionsMatcher{
+		description: "EventHandler is set",
+		equals: func(o client.BuildOptions) bool {
+			return o.EventHandler != nil
+		},
+	}
+}
+
 type buildOptionsMatcher struct {
 	equals      func(client.BuildOptions) bool
 	description string
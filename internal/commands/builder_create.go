@@ -21,6 +21,7 @@ type BuilderCreateFlags struct {
 	Publish         bool
 	Registry        string
 	Policy          string
+	SigningKey      string
 }
 
 // CreateBuilder creates a builder image, based on a builder config
@@ -37,6 +38,8 @@ func BuilderCreate(logger logging.Logger, cfg config.Config, pack PackClient) *c
 	pack builders suggest
 
 Creating a custom builder allows you to control what buildpacks are used and what image apps are based on. For more on how to create a builder, see: https://buildpacks.io/docs/operator-guide/create-a-builder/.
+
+When the builder config lists [[targets]], a builder is created for each target, using the build image and lifecycle of the target when provided, and published together as an image index.
 `,
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			if err := validateCreateFlags(&flags, cfg); err != nil {
@@ -73,6 +76,7 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				Publish:         flags.Publish,
 				Registry:        flags.Registry,
 				PullPolicy:      pullPolicy,
+				SigningKey:      flags.SigningKey,
 			}); err != nil {
 				return err
 			}
@@ -89,6 +93,7 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 	cmd.Flags().StringVarP(&flags.BuilderTomlPath, "config", "c", "", "Path to builder TOML file (required)")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().StringVar(&flags.SigningKey, "signing-key", "", "Path to a cosign compatible private key to sign the published builder with (requires --publish)")
 
 	AddHelpFlag(cmd, "create")
 	return cmd
@@ -107,5 +112,9 @@ func validateCreateFlags(flags *BuilderCreateFlags, cfg config.Config) error {
 		return errors.Errorf("Please provide a builder config path, using --config.")
 	}
 
+	if flags.SigningKey != "" && !flags.Publish {
+		return errors.Errorf("--signing-key requires --publish. Only images published to a registry can be signed.")
+	}
+
 	return nil
 }
@@ -80,6 +80,17 @@ func testCreateCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--signing-key is specified without --publish", func() {
+			it("errors with a descriptive message", func() {
+				command.SetArgs([]string{
+					"some/builder",
+					"--config", builderConfigPath,
+					"--signing-key", "some/cosign.key",
+				})
+				h.AssertError(t, command.Execute(), "--signing-key requires --publish. Only images published to a registry can be signed.")
+			})
+		})
+
 		when("--pull-policy", func() {
 			it("returns error for unknown policy", func() {
 				command.SetArgs([]string{
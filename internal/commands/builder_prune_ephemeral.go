@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type BuilderPruneEphemeralFlags struct {
+	DryRun bool
+}
+
+func BuilderPruneEphemeral(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags BuilderPruneEphemeralFlags
+
+	cmd := &cobra.Command{
+		Use:     "prune-ephemeral",
+		Args:    cobra.NoArgs,
+		Short:   "Remove ephemeral builders",
+		Example: "pack builder prune-ephemeral",
+		Long: "Remove the ephemeral builders kept for later builds.\n\n" +
+			"Builds run on an ephemeral builder, created from their builder with the buildpacks and environment variables of the build. " +
+			"It is reused by later builds with the same builder and buildpacks. " +
+			"Ephemeral builders with environment variables, or with buildpacks that weren't packaged, are removed after the build instead.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			pruned, err := pack.PruneEphemeralBuilders(cmd.Context(), client.PruneEphemeralBuildersOptions{DryRun: flags.DryRun})
+			if err != nil {
+				return err
+			}
+
+			verb := "Removed"
+			if flags.DryRun {
+				verb = "Would remove"
+			}
+			for _, name := range pruned {
+				logger.Infof("%s ephemeral builder %s", verb, style.Symbol(name))
+			}
+			logger.Infof("%s %d ephemeral builder(s)", verb, len(pruned))
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show the ephemeral builders that would be removed without removing them")
+	AddHelpFlag(cmd, "prune-ephemeral")
+	return cmd
+}
@@ -0,0 +1,80 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuilderPruneEphemeralCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuilderPruneEphemeralCommand", testBuilderPruneEphemeralCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuilderPruneEphemeralCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		pruned         = []string{"pack.local/builder/aaaa:latest"}
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.BuilderPruneEphemeral(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuilderPruneEphemeral", func() {
+		it("removes the ephemeral builders", func() {
+			mockClient.EXPECT().
+				PruneEphemeralBuilders(gomock.Any(), client.PruneEphemeralBuildersOptions{}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+			h.AssertContains(t, outBuf.String(), "Removed ephemeral builder 'pack.local/builder/aaaa:latest'")
+			h.AssertContains(t, outBuf.String(), "Removed 1 ephemeral builder(s)")
+		})
+
+		it("reports the ephemeral builders that would be removed in a dry run", func() {
+			mockClient.EXPECT().
+				PruneEphemeralBuilders(gomock.Any(), client.PruneEphemeralBuildersOptions{DryRun: true}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{"--dry-run"})
+			h.AssertNil(t, cmd.Execute())
+			h.AssertContains(t, outBuf.String(), "Would remove ephemeral builder 'pack.local/builder/aaaa:latest'")
+		})
+
+		it("fails when the ephemeral builders can't be pruned", func() {
+			mockClient.EXPECT().
+				PruneEphemeralBuilders(gomock.Any(), gomock.Any()).
+				Return(nil, errors.New("some-error"))
+
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "some-error")
+		})
+	})
+}
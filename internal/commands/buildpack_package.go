@@ -23,6 +23,7 @@ type BuildpackPackageFlags struct {
 	Policy            string
 	BuildpackRegistry string
 	Path              string
+	SigningKey        string
 }
 
 // BuildpackPackager packages buildpacks
@@ -47,7 +48,9 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 			"image repositories or persisted on disk as a '.cnb' file. You can also package a number of buildpacks " +
 			"together, to enable easier distribution of a set of buildpacks. " +
 			"Packaged buildpacks can be used as inputs to `pack build` (using the `--buildpack` flag), " +
-			"and they can be included in the configs used in `pack builder create` and `pack buildpack package`. For more " +
+			"and they can be included in the configs used in `pack builder create` and `pack buildpack package`. " +
+			"When the package config lists `[[targets]]`, a package is created for each target and combined into an " +
+			"image index, or a single '.cnb' file with a manifest per target. For more " +
 			"on how to package a buildpack, see: https://buildpacks.io/docs/buildpack-author-guide/package-a-buildpack/.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			if err := validateBuildpackPackageFlags(&flags); err != nil {
@@ -100,6 +103,7 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 				Publish:         flags.Publish,
 				PullPolicy:      pullPolicy,
 				Registry:        flags.BuildpackRegistry,
+				SigningKey:      flags.SigningKey,
 			}); err != nil {
 				return err
 			}
@@ -120,6 +124,7 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
 	cmd.Flags().StringVarP(&flags.Path, "path", "p", "", "Path to the Buildpack that needs to be packaged")
 	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	cmd.Flags().StringVar(&flags.SigningKey, "signing-key", "", `Path to a cosign compatible private key to sign the published package with (requires --publish)`)
 
 	AddHelpFlag(cmd, "package")
 	return cmd
@@ -129,6 +134,9 @@ func validateBuildpackPackageFlags(p *BuildpackPackageFlags) error {
 	if p.Publish && p.Policy == image.PullNever.String() {
 		return errors.Errorf("--publish and --pull-policy never cannot be used together. The --publish flag requires the use of remote images.")
 	}
+	if p.SigningKey != "" && !p.Publish {
+		return errors.Errorf("--signing-key requires --publish. Only packages published to a registry can be signed.")
+	}
 	if p.PackageTomlPath != "" && p.Path != "" {
 		return errors.Errorf("--config and --path cannot be used together. Please specify the relative path to the Buildpack directory in the package config file.")
 	}
@@ -237,6 +237,19 @@ func testPackageCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--signing-key is specified without --publish", func() {
+			it("errors with a descriptive message", func() {
+				cmd := packageCommand()
+				cmd.SetArgs([]string{
+					"some-image-name", "--config", "/path/to/some/file",
+					"--signing-key", "some/cosign.key",
+				})
+
+				err := cmd.Execute()
+				h.AssertError(t, err, "--signing-key requires --publish. Only packages published to a registry can be signed.")
+			})
+		})
+
 		it("logs an error and exits when package toml is invalid", func() {
 			expectedErr := errors.New("it went wrong")
 
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewCacheCommand(logger logging.Logger, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Interact with build caches",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(CacheList(logger, client))
+	cmd.AddCommand(CacheInspect(logger, client))
+	cmd.AddCommand(CachePrune(logger, client))
+	AddHelpFlag(cmd, "cache")
+	return cmd
+}
+
+func printCacheVolumes(logger logging.Logger, volumes []client.CacheVolume) {
+	tw := tabwriter.NewWriter(logging.GetWriterForLevel(logger, logging.InfoLevel), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tTYPE\tSIZE\tLAST USED\tVOLUME")
+	for _, volume := range volumes {
+		image := "-"
+		if volume.Image != "" {
+			image = volume.Image
+		}
+
+		size := "-"
+		if volume.Size >= 0 {
+			size = humanize.Bytes(uint64(volume.Size))
+		}
+
+		lastUsed := "-"
+		if !volume.LastUsed.IsZero() {
+			lastUsed = humanize.Time(volume.LastUsed)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", image, volume.Type, size, lastUsed, volume.Name)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func CacheInspect(logger logging.Logger, pack PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "inspect <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Show the cache volumes of an app image",
+		Example: "pack cache inspect my-app",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			volumes, err := pack.InspectCache(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			printCacheVolumes(logger, volumes)
+			return nil
+		}),
+	}
+
+	AddHelpFlag(cmd, "inspect")
+	return cmd
+}
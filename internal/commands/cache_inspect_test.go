@@ -0,0 +1,79 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheInspectCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheInspectCommand", testCacheInspectCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheInspectCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.CacheInspect(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheInspect", func() {
+		it("requires an image name", func() {
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "accepts 1 arg")
+		})
+
+		it("prints the cache volumes of the image", func() {
+			mockClient.EXPECT().
+				InspectCache(gomock.Any(), "some/app").
+				Return([]client.CacheVolume{
+					{Name: "pack-cache-some-app.build", Image: "some/app", Type: "build", Size: 1000},
+					{Name: "pack-cache-some-app.launch", Image: "some/app", Type: "launch", Size: -1},
+				}, nil)
+
+			cmd.SetArgs([]string{"some/app"})
+			h.AssertNil(t, cmd.Execute())
+
+			output := outBuf.String()
+			h.AssertContains(t, output, "some/app  build   1.0 kB  -          pack-cache-some-app.build")
+			h.AssertContains(t, output, "some/app  launch  -       -          pack-cache-some-app.launch")
+		})
+
+		it("fails when the image has no caches", func() {
+			mockClient.EXPECT().
+				InspectCache(gomock.Any(), "some/app").
+				Return(nil, errors.New("no cache volumes found for image 'some/app'"))
+
+			cmd.SetArgs([]string{"some/app"})
+			h.AssertError(t, cmd.Execute(), "no cache volumes found for image 'some/app'")
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func CacheList(logger logging.Logger, pack PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Args:    cobra.NoArgs,
+		Short:   "List the cache volumes created by builds",
+		Example: "pack cache ls",
+		Long: "List the cache volumes created by builds.\n\n" +
+			"The app image and last use of a volume are only known once it has been used by a build of this version of pack.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			volumes, err := pack.ListCaches(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if len(volumes) == 0 {
+				logger.Info("No cache volumes found")
+				return nil
+			}
+
+			printCacheVolumes(logger, volumes)
+			return nil
+		}),
+	}
+
+	AddHelpFlag(cmd, "ls")
+	return cmd
+}
@@ -0,0 +1,96 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheListCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheListCommand", testCacheListCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheListCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.CacheList(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheList", func() {
+		it("prints the cache volumes", func() {
+			mockClient.EXPECT().
+				ListCaches(gomock.Any()).
+				Return([]client.CacheVolume{
+					{
+						Name:     "pack-cache-some-app.build",
+						Image:    "index.docker.io/some/app:latest",
+						Type:     "build",
+						Size:     2 * 1000 * 1000,
+						LastUsed: time.Now().Add(-49 * time.Hour),
+					},
+					{
+						Name: "pack-cache-other-app.launch",
+						Type: "launch",
+						Size: -1,
+					},
+				}, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+
+			output := outBuf.String()
+			h.AssertContains(t, output, "IMAGE                            TYPE    SIZE    LAST USED   VOLUME")
+			h.AssertContains(t, output, "index.docker.io/some/app:latest  build   2.0 MB  2 days ago  pack-cache-some-app.build")
+			h.AssertContains(t, output, "-                                launch  -       -           pack-cache-other-app.launch")
+		})
+
+		it("reports when there are no cache volumes", func() {
+			mockClient.EXPECT().
+				ListCaches(gomock.Any()).
+				Return(nil, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+			h.AssertContains(t, outBuf.String(), "No cache volumes found")
+		})
+
+		it("fails when the caches can't be listed", func() {
+			mockClient.EXPECT().
+				ListCaches(gomock.Any()).
+				Return(nil, errors.New("some-error"))
+
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "some-error")
+		})
+	})
+}
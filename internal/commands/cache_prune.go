@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CachePruneFlags struct {
+	OlderThan string
+	Image     string
+	All       bool
+	DryRun    bool
+}
+
+func CachePrune(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags CachePruneFlags
+
+	cmd := &cobra.Command{
+		Use:     "prune",
+		Args:    cobra.NoArgs,
+		Short:   "Remove cache volumes",
+		Example: "pack cache prune --older-than 168h",
+		Long: "Remove cache volumes that were last used before a given duration ago, that belong to an app image, or both.\n\n" +
+			"Volumes in use by a container are skipped.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			opts, err := pruneCachesOptions(flags)
+			if err != nil {
+				return err
+			}
+
+			pruned, err := pack.PruneCaches(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			verb := "Removed"
+			if flags.DryRun {
+				verb = "Would remove"
+			}
+			for _, volume := range pruned {
+				logger.Infof("%s cache volume %s", verb, style.Symbol(volume.Name))
+			}
+			logger.Infof("%s %d cache volume(s)", verb, len(pruned))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.OlderThan, "older-than", "", "Remove volumes last used before this duration ago (e.g. 72h)")
+	cmd.Flags().StringVar(&flags.Image, "image", "", "Remove the volumes of this app image")
+	cmd.Flags().BoolVar(&flags.All, "all", false, "Remove all cache volumes")
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show the volumes that would be removed without removing them")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
+
+func pruneCachesOptions(flags CachePruneFlags) (client.PruneCachesOptions, error) {
+	if flags.All && (flags.OlderThan != "" || flags.Image != "") {
+		return client.PruneCachesOptions{}, errors.New("all flag cannot be used with the older-than or image flags")
+	}
+
+	if !flags.All && flags.OlderThan == "" && flags.Image == "" {
+		return client.PruneCachesOptions{}, errors.New("one of the older-than, image or all flags is required")
+	}
+
+	opts := client.PruneCachesOptions{
+		Image:  flags.Image,
+		DryRun: flags.DryRun,
+	}
+
+	if flags.OlderThan != "" {
+		olderThan, err := time.ParseDuration(flags.OlderThan)
+		if err != nil {
+			return client.PruneCachesOptions{}, errors.Wrapf(err, "parsing older-than %s", style.Symbol(flags.OlderThan))
+		}
+		if olderThan <= 0 {
+			return client.PruneCachesOptions{}, errors.Errorf("older-than %s must be a positive duration", style.Symbol(flags.OlderThan))
+		}
+		opts.OlderThan = olderThan
+	}
+
+	return opts, nil
+}
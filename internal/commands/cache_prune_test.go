@@ -0,0 +1,120 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCachePruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CachePruneCommand", testCachePruneCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCachePruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		pruned         []client.CacheVolume
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		pruned = []client.CacheVolume{{Name: "pack-cache-some-app.build"}}
+
+		cmd = commands.CachePrune(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CachePrune", func() {
+		it("removes the volumes older than the duration", func() {
+			mockClient.EXPECT().
+				PruneCaches(gomock.Any(), client.PruneCachesOptions{OlderThan: 72 * time.Hour}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{"--older-than", "72h"})
+			h.AssertNil(t, cmd.Execute())
+			h.AssertContains(t, outBuf.String(), "Removed cache volume 'pack-cache-some-app.build'")
+			h.AssertContains(t, outBuf.String(), "Removed 1 cache volume(s)")
+		})
+
+		it("removes the volumes of the image", func() {
+			mockClient.EXPECT().
+				PruneCaches(gomock.Any(), client.PruneCachesOptions{Image: "some/app"}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{"--image", "some/app"})
+			h.AssertNil(t, cmd.Execute())
+		})
+
+		it("removes all volumes", func() {
+			mockClient.EXPECT().
+				PruneCaches(gomock.Any(), client.PruneCachesOptions{}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{"--all"})
+			h.AssertNil(t, cmd.Execute())
+		})
+
+		it("reports the volumes that would be removed in a dry run", func() {
+			mockClient.EXPECT().
+				PruneCaches(gomock.Any(), client.PruneCachesOptions{Image: "some/app", DryRun: true}).
+				Return(pruned, nil)
+
+			cmd.SetArgs([]string{"--image", "some/app", "--dry-run"})
+			h.AssertNil(t, cmd.Execute())
+			h.AssertContains(t, outBuf.String(), "Would remove cache volume 'pack-cache-some-app.build'")
+		})
+
+		it("requires a selection flag", func() {
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "one of the older-than, image or all flags is required")
+		})
+
+		it("doesn't allow all with other flags", func() {
+			cmd.SetArgs([]string{"--all", "--image", "some/app"})
+			h.AssertError(t, cmd.Execute(), "all flag cannot be used with the older-than or image flags")
+		})
+
+		it("fails for an invalid duration", func() {
+			cmd.SetArgs([]string{"--older-than", "a-week"})
+			h.AssertError(t, cmd.Execute(), "parsing older-than 'a-week'")
+		})
+
+		it("fails for a negative duration", func() {
+			cmd.SetArgs([]string{"--older-than", "-1h"})
+			h.AssertError(t, cmd.Execute(), "older-than '-1h' must be a positive duration")
+		})
+
+		it("fails when the caches can't be pruned", func() {
+			mockClient.EXPECT().
+				PruneCaches(gomock.Any(), gomock.Any()).
+				Return(nil, errors.New("some-error"))
+
+			cmd.SetArgs([]string{"--all"})
+			h.AssertError(t, cmd.Execute(), "some-error")
+		})
+	})
+}
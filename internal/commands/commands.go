@@ -25,11 +25,19 @@ type PackClient interface {
 	NewBuildpack(context.Context, client.NewBuildpackOptions) error
 	PackageBuildpack(ctx context.Context, opts client.PackageBuildpackOptions) error
 	Build(context.Context, client.BuildOptions) error
+	BuildAll(context.Context, client.BuildOptions) error
+	BuildMany(context.Context, client.BuildManyOptions) error
+	Detect(context.Context, client.BuildOptions) (client.DetectResult, error)
+	DebugBuild(context.Context, client.DebugBuildOptions) error
 	RegisterBuildpack(context.Context, client.RegisterBuildpackOptions) error
 	YankBuildpack(client.YankBuildpackOptions) error
 	InspectBuildpack(client.InspectBuildpackOptions) (*client.BuildpackInfo, error)
 	PullBuildpack(context.Context, client.PullBuildpackOptions) error
 	DownloadSBOM(name string, options client.DownloadSBOMOptions) error
+	ListCaches(context.Context) ([]client.CacheVolume, error)
+	InspectCache(context.Context, string) ([]client.CacheVolume, error)
+	PruneCaches(context.Context, client.PruneCachesOptions) ([]client.CacheVolume, error)
+	PruneEphemeralBuilders(context.Context, client.PruneEphemeralBuildersOptions) ([]string, error)
 }
 
 func AddHelpFlag(cmd *cobra.Command, commandName string) {
@@ -24,6 +24,7 @@ func NewConfigCommand(logger logging.Logger, cfg config.Config, cfgPath string,
 	cmd.AddCommand(ConfigTrustedBuilder(logger, cfg, cfgPath))
 	cmd.AddCommand(ConfigLifecycleImage(logger, cfg, cfgPath))
 	cmd.AddCommand(ConfigRegistryMirrors(logger, cfg, cfgPath))
+	cmd.AddCommand(ConfigContainerEngine(logger, cfg, cfgPath))
 
 	AddHelpFlag(cmd, "config")
 	return cmd
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func ConfigContainerEngine(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "container-engine <docker | podman>",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "List, set and unset the container engine used to run builds",
+		Long: "You can use this command to list, set, and unset the container engine pack runs builds with:\n" +
+			"* To list your container engine, run `pack config container-engine`.\n" +
+			"* To set your container engine, run `pack config container-engine <docker | podman>`.\n" +
+			"* To unset your container engine, run `pack config container-engine --unset`.\n" +
+			fmt.Sprintf("The Podman API socket is read from %s, or found at its default locations. ", style.Symbol(engine.PodmanHostEnvVar)) +
+			fmt.Sprintf("Unsetting the container engine will reset it to the default, which is %s", style.Symbol(config.DockerContainerEngine)),
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset:
+				if len(args) > 0 {
+					return errors.Errorf("container engine and --unset cannot be specified simultaneously")
+				}
+				oldContainerEngine := containerEngineOrDefault(cfg.ContainerEngine)
+				cfg.ContainerEngine = ""
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully unset container engine %s", style.Symbol(oldContainerEngine))
+				logger.Infof("Container engine has been set to %s", style.Symbol(config.DockerContainerEngine))
+			case len(args) == 0: // list
+				logger.Infof("The current container engine is %s", style.Symbol(containerEngineOrDefault(cfg.ContainerEngine)))
+			default: // set
+				newContainerEngine := args[0]
+
+				if newContainerEngine != config.DockerContainerEngine && newContainerEngine != config.PodmanContainerEngine {
+					return errors.Errorf("invalid container engine %s, must be %s or %s", style.Symbol(newContainerEngine), style.Symbol(config.DockerContainerEngine), style.Symbol(config.PodmanContainerEngine))
+				}
+
+				if newContainerEngine == containerEngineOrDefault(cfg.ContainerEngine) {
+					logger.Infof("Container engine is already set to %s", style.Symbol(newContainerEngine))
+					return nil
+				}
+
+				cfg.ContainerEngine = newContainerEngine
+				if err := config.Write(cfg, cfgPath); err != nil {
+					return errors.Wrapf(err, "writing config to %s", cfgPath)
+				}
+
+				logger.Infof("Successfully set %s as the container engine", style.Symbol(newContainerEngine))
+			}
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset container engine, and set it back to the default container engine, which is "+style.Symbol(config.DockerContainerEngine))
+	AddHelpFlag(cmd, "container-engine")
+	return cmd
+}
+
+func containerEngineOrDefault(containerEngine string) string {
+	if containerEngine == "" {
+		return config.DockerContainerEngine
+	}
+	return containerEngine
+}
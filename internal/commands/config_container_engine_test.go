@@ -0,0 +1,143 @@
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigContainerEngine(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ConfigContainerEngineCommand", testConfigContainerEngineCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testConfigContainerEngineCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command      *cobra.Command
+		logger       logging.Logger
+		outBuf       bytes.Buffer
+		tempPackHome string
+		configFile   string
+		assert       = h.NewAssertionManager(t)
+		cfg          = config.Config{}
+	)
+
+	it.Before(func() {
+		var err error
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		tempPackHome, err = ioutil.TempDir("", "pack-home")
+		h.AssertNil(t, err)
+		configFile = filepath.Join(tempPackHome, "config.toml")
+
+		command = commands.ConfigContainerEngine(logger, cfg, configFile)
+		command.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tempPackHome))
+	})
+
+	when("#ConfigContainerEngine", func() {
+		when("list", func() {
+			when("no container engine is specified", func() {
+				it("lists the default container engine", func() {
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "The current container engine is 'docker'")
+				})
+			})
+
+			when("container engine set to podman in config", func() {
+				it("lists podman as container engine", func() {
+					cfg.ContainerEngine = "podman"
+					command = commands.ConfigContainerEngine(logger, cfg, configFile)
+					command.SetArgs([]string{})
+
+					h.AssertNil(t, command.Execute())
+
+					assert.Contains(outBuf.String(), "The current container engine is 'podman'")
+				})
+			})
+		})
+
+		when("set", func() {
+			when("container engine provided is the same as configured container engine", func() {
+				it("provides a helpful message", func() {
+					cfg.ContainerEngine = "podman"
+					command = commands.ConfigContainerEngine(logger, cfg, configFile)
+					command.SetArgs([]string{"podman"})
+
+					h.AssertNil(t, command.Execute())
+
+					h.AssertEq(t, strings.TrimSpace(outBuf.String()), `Container engine is already set to 'podman'`)
+				})
+			})
+
+			when("invalid container engine is specified", func() {
+				it("does not write invalid container engine to config", func() {
+					command.SetArgs([]string{"podman"})
+					assert.Succeeds(command.Execute())
+
+					command.SetArgs([]string{"containerd"})
+					err := command.Execute()
+					h.AssertError(t, err, `invalid container engine 'containerd', must be 'docker' or 'podman'`)
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.ContainerEngine, "podman")
+				})
+			})
+
+			when("valid container engine is specified", func() {
+				it("sets the container engine in config", func() {
+					command.SetArgs([]string{"podman"})
+					assert.Succeeds(command.Execute())
+
+					readCfg, err := config.Read(configFile)
+					assert.Nil(err)
+					assert.Equal(readCfg.ContainerEngine, "podman")
+				})
+			})
+		})
+
+		when("unset", func() {
+			it("removes set container engine and resets to default container engine", func() {
+				command = commands.ConfigContainerEngine(logger, config.Config{ContainerEngine: "podman"}, configFile)
+				command.SetArgs([]string{"--unset"})
+				assert.Succeeds(command.Execute())
+
+				readCfg, err := config.Read(configFile)
+				assert.Nil(err)
+				assert.Equal(readCfg.ContainerEngine, "")
+				assert.Contains(outBuf.String(), "Successfully unset container engine 'podman'")
+			})
+		})
+
+		when("--unset and container engine to set is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{
+					"podman",
+					"--unset",
+				})
+				err := command.Execute()
+				h.AssertError(t, err, `container engine and --unset cannot be specified simultaneously`)
+			})
+		})
+	})
+}
@@ -48,6 +48,7 @@ func Rebase(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Co
 
 	cmd.Flags().BoolVar(&opts.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing")
+	cmd.Flags().StringVar(&opts.MetadataFile, "metadata-file", "", "Path to write the metadata of the rebased image to, as JSON.\nThe metadata holds the image name and digest, the run image, and the buildpacks and process types of the image.")
 	cmd.Flags().StringVar(&policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
 
 	AddHelpFlag(cmd, "rebase")
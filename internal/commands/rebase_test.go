@@ -116,6 +116,18 @@ func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("--metadata-file is provided", func() {
+				it("sets the metadata file", func() {
+					opts.MetadataFile = "metadata.json"
+					mockClient.EXPECT().
+						Rebase(gomock.Any(), opts).
+						Return(nil)
+
+					command.SetArgs([]string{repoName, "--metadata-file", "metadata.json"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
 			when("--pull-policy unknown-policy", func() {
 				it("fails to run", func() {
 					command.SetArgs([]string{repoName, "--pull-policy", "unknown-policy"})
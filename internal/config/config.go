@@ -22,6 +22,17 @@ type Config struct {
 	Registries          []Registry        `toml:"registries,omitempty"`
 	LifecycleImage      string            `toml:"lifecycle-image,omitempty"`
 	RegistryMirrors     map[string]string `toml:"registry-mirrors,omitempty"`
+	VerifyPolicies      []VerifyPolicy    `toml:"verify,omitempty"`
+	ContainerEngine     string            `toml:"container-engine,omitempty"`
+	ContainerLimits     *ContainerLimits  `toml:"container-limits,omitempty"`
+}
+
+// ContainerLimits are the default resource limits of the containers pack build runs lifecycle phases in.
+type ContainerLimits struct {
+	Memory    string   `toml:"memory,omitempty"`
+	CPUs      float64  `toml:"cpus,omitempty"`
+	PidsLimit int64    `toml:"pids-limit,omitempty"`
+	Ulimits   []string `toml:"ulimits,omitempty"`
 }
 
 type Registry struct {
@@ -39,8 +50,21 @@ type TrustedBuilder struct {
 	Name string `toml:"name"`
 }
 
+// VerifyPolicy requires images matching Image to be signed by the owner of the public key at Key.
+// Image is a repository, or a repository prefix ending with '*'.
+type VerifyPolicy struct {
+	Image string `toml:"image"`
+	Key   string `toml:"key"`
+}
+
 const OfficialRegistryName = "official"
 
+// Container engines pack can run the lifecycle with.
+const (
+	DockerContainerEngine = "docker"
+	PodmanContainerEngine = "podman"
+)
+
 func DefaultRegistry() Registry {
 	return Registry{
 		OfficialRegistryName,
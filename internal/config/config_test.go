@@ -91,6 +91,33 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 				h.AssertContains(t, string(b), `[registry-mirrors]
   "index.docker.io" = "10.0.0.1"`)
 			})
+
+			it("writes the container limits", func() {
+				h.AssertNil(t, config.Write(config.Config{
+					ContainerLimits: &config.ContainerLimits{
+						Memory:    "4g",
+						CPUs:      1.5,
+						PidsLimit: 512,
+						Ulimits:   []string{"nofile=1024:2048"},
+					},
+				}, configPath))
+
+				b, err := ioutil.ReadFile(configPath)
+				h.AssertNil(t, err)
+				h.AssertContains(t, string(b), `[container-limits]
+  memory = "4g"
+  cpus = 1.5
+  pids-limit = 512
+  ulimits = ["nofile=1024:2048"]`)
+			})
+
+			it("doesn't write unset container limits", func() {
+				h.AssertNil(t, config.Write(config.Config{DefaultBuilder: "some/builder"}, configPath))
+
+				b, err := ioutil.ReadFile(configPath)
+				h.AssertNil(t, err)
+				h.AssertNotContains(t, string(b), "container-limits")
+			})
 		})
 
 		when("config on disk", func() {
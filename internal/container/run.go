@@ -7,14 +7,29 @@ import (
 
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/pkg/errors"
 )
 
 type Handler func(bodyChan <-chan dcontainer.ContainerWaitOKBody, errChan <-chan error, reader io.Reader) error
 
-func RunWithHandler(ctx context.Context, docker client.CommonAPIClient, ctrID string, handler Handler) error {
+// ExitError is returned when a container exits with a non-zero status code.
+type ExitError struct {
+	StatusCode int64
+}
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("failed with status code: %d", e.StatusCode)
+}
+
+// Client is the subset of the container engine API needed to run a container.
+type Client interface {
+	ContainerWait(ctx context.Context, container string, condition dcontainer.WaitCondition) (<-chan dcontainer.ContainerWaitOKBody, <-chan error)
+	ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error)
+	ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error
+}
+
+func RunWithHandler(ctx context.Context, docker Client, ctrID string, handler Handler) error {
 	bodyChan, errChan := docker.ContainerWait(ctx, ctrID, dcontainer.WaitConditionNextExit)
 
 	resp, err := docker.ContainerAttach(ctx, ctrID, types.ContainerAttachOptions{
@@ -34,6 +49,57 @@ func RunWithHandler(ctx context.Context, docker client.CommonAPIClient, ctrID st
 	return handler(bodyChan, errChan, resp.Reader)
 }
 
+// RunInteractive runs the container with ctrID with its stdin attached to in, such as to run a shell in it.
+// The output of containers created with a TTY is not multiplexed, and is written to out as is.
+func RunInteractive(ctx context.Context, docker Client, ctrID string, tty bool, in io.Reader, out, errOut io.Writer) error {
+	bodyChan, errChan := docker.ContainerWait(ctx, ctrID, dcontainer.WaitConditionNextExit)
+
+	resp, err := docker.ContainerAttach(ctx, ctrID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	go func() {
+		io.Copy(resp.Conn, in)
+		resp.CloseWrite()
+	}()
+
+	copyErr := make(chan error, 1)
+	go func() {
+		var err error
+		if tty {
+			_, err = io.Copy(out, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(out, errOut, resp.Reader)
+		}
+		copyErr <- err
+	}()
+
+	if err := docker.ContainerStart(ctx, ctrID, types.ContainerStartOptions{}); err != nil {
+		return errors.Wrap(err, "container start")
+	}
+
+	select {
+	case body := <-bodyChan:
+		if err := <-copyErr; err != nil {
+			return err
+		}
+		if body.StatusCode != 0 {
+			return ExitError{StatusCode: body.StatusCode}
+		}
+	case err := <-errChan:
+		return err
+	}
+
+	return nil
+}
+
 func DefaultHandler(out, errOut io.Writer) Handler {
 	return func(bodyChan <-chan dcontainer.ContainerWaitOKBody, errChan <-chan error, reader io.Reader) error {
 		copyErr := make(chan error)
@@ -48,7 +114,7 @@ func DefaultHandler(out, errOut io.Writer) Handler {
 		select {
 		case body := <-bodyChan:
 			if body.StatusCode != 0 {
-				return fmt.Errorf("failed with status code: %d", body.StatusCode)
+				return ExitError{StatusCode: body.StatusCode}
 			}
 		case err := <-errChan:
 			return err
@@ -2,6 +2,7 @@ package fakes
 
 import (
 	"context"
+	"sync"
 
 	"github.com/buildpacks/imgutil"
 	"github.com/pkg/errors"
@@ -10,15 +11,18 @@ import (
 )
 
 type FetchArgs struct {
-	Daemon     bool
-	PullPolicy image.PullPolicy
-	Platform   string
+	Daemon           bool
+	PullPolicy       image.PullPolicy
+	Platform         string
+	SkipVerification bool
 }
 
 type FakeImageFetcher struct {
 	LocalImages  map[string]imgutil.Image
 	RemoteImages map[string]imgutil.Image
 	FetchCalls   map[string]*FetchArgs
+
+	mu sync.Mutex
 }
 
 func NewFakeImageFetcher() *FakeImageFetcher {
@@ -30,7 +34,10 @@ func NewFakeImageFetcher() *FakeImageFetcher {
 }
 
 func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
-	f.FetchCalls[name] = &FetchArgs{Daemon: options.Daemon, PullPolicy: options.PullPolicy, Platform: options.Platform}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.FetchCalls[name] = &FetchArgs{Daemon: options.Daemon, PullPolicy: options.PullPolicy, Platform: options.Platform, SkipVerification: options.SkipVerification}
 
 	ri, remoteFound := f.RemoteImages[name]
 
@@ -2,15 +2,35 @@ package fakes
 
 import (
 	"context"
+	"sync"
 
 	"github.com/buildpacks/pack/internal/build"
 )
 
 type FakeLifecycle struct {
-	Opts build.LifecycleOptions
+	Opts         build.LifecycleOptions
+	DetectResult build.DetectResult
+
+	// Executions records the options of every execution, in order. Opts holds the last one.
+	Executions []build.LifecycleOptions
+
+	// OnExecute, when set, is called with the options of every execution, such as to export the app image.
+	OnExecute func(opts build.LifecycleOptions)
+
+	mu sync.Mutex
 }
 
 func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.Opts = opts
+	f.Executions = append(f.Executions, opts)
+	if opts.DetectHandler != nil {
+		opts.DetectHandler(f.DetectResult)
+	}
+	if f.OnExecute != nil {
+		f.OnExecute(opts)
+	}
 	return nil
 }
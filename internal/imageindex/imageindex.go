@@ -0,0 +1,106 @@
+package imageindex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Manifest is a platform specific image published to a registry.
+type Manifest struct {
+	Ref      name.Reference
+	Platform v1.Platform
+}
+
+// ParsePlatform parses a platform in the form 'os/arch' or 'os/arch/variant', such as 'linux/arm64/v8'.
+func ParsePlatform(platform string) (v1.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v1.Platform{}, errors.Errorf("invalid platform %s, expected the form os/arch[/variant]", style.Symbol(platform))
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return v1.Platform{}, errors.Errorf("invalid platform %s, expected the form os/arch[/variant]", style.Symbol(platform))
+		}
+	}
+
+	p := v1.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// PlatformString formats a platform in the form accepted by ParsePlatform.
+func PlatformString(platform v1.Platform) string {
+	s := fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+	if platform.Variant != "" {
+		s += "/" + platform.Variant
+	}
+	return s
+}
+
+// PlatformSuffix formats a platform for use in tags and paths, such as 'linux-arm64'.
+func PlatformSuffix(platform v1.Platform) string {
+	return strings.ReplaceAll(PlatformString(platform), "/", "-")
+}
+
+// PlatformTag returns the tag of ref suffixed with the platform, such as 'latest-linux-arm64'.
+// It is used to publish the platform specific images that make up an image index.
+func PlatformTag(ref name.Tag, platform v1.Platform) name.Tag {
+	return ref.Context().Tag(fmt.Sprintf("%s-%s", ref.TagStr(), PlatformSuffix(platform)))
+}
+
+// Publish creates an image index referencing the manifests and pushes it to each of the tags.
+// The manifests must already be published. They are copied to the repository of a tag when it differs from theirs.
+// The digest of the index is returned.
+func Publish(keychain authn.Keychain, manifests []Manifest, tags ...name.Reference) (v1.Hash, error) {
+	if len(manifests) == 0 {
+		return v1.Hash{}, errors.New("an image index requires at least one manifest")
+	}
+
+	var (
+		adds      []mutate.IndexAddendum
+		mediaType = types.DockerManifestList
+	)
+	for _, manifest := range manifests {
+		img, err := remote.Image(manifest.Ref, remote.WithAuthFromKeychain(keychain))
+		if err != nil {
+			return v1.Hash{}, errors.Wrapf(err, "fetching image %s", style.Symbol(manifest.Ref.Name()))
+		}
+
+		imgMediaType, err := img.MediaType()
+		if err != nil {
+			return v1.Hash{}, errors.Wrapf(err, "reading media type of image %s", style.Symbol(manifest.Ref.Name()))
+		}
+		if imgMediaType == types.OCIManifestSchema1 {
+			mediaType = types.OCIImageIndex
+		}
+
+		platform := manifest.Platform
+		adds = append(adds, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: &platform},
+		})
+	}
+
+	index := mutate.IndexMediaType(mutate.AppendManifests(empty.Index, adds...), mediaType)
+	for _, tag := range tags {
+		if err := remote.WriteIndex(tag, index, remote.WithAuthFromKeychain(keychain)); err != nil {
+			return v1.Hash{}, errors.Wrapf(err, "publishing image index %s", style.Symbol(tag.Name()))
+		}
+	}
+
+	return index.Digest()
+}
@@ -0,0 +1,140 @@
+package imageindex_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/imageindex"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageIndex(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ImageIndex", testImageIndex, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageIndex(t *testing.T, when spec.G, it spec.S) {
+	when("#ParsePlatform", func() {
+		it("parses os and architecture", func() {
+			platform, err := imageindex.ParsePlatform("linux/amd64")
+			h.AssertNil(t, err)
+			h.AssertEq(t, platform, v1.Platform{OS: "linux", Architecture: "amd64"})
+		})
+
+		it("parses a variant", func() {
+			platform, err := imageindex.ParsePlatform("linux/arm64/v8")
+			h.AssertNil(t, err)
+			h.AssertEq(t, platform, v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+			h.AssertEq(t, imageindex.PlatformString(platform), "linux/arm64/v8")
+		})
+
+		for _, invalid := range []string{"linux", "linux/", "/amd64", "linux/arm64/v8/extra"} {
+			invalid := invalid
+			it(fmt.Sprintf("fails for %s", invalid), func() {
+				_, err := imageindex.ParsePlatform(invalid)
+				h.AssertError(t, err, "expected the form os/arch[/variant]")
+			})
+		}
+	})
+
+	when("#PlatformTag", func() {
+		it("suffixes the tag with the platform", func() {
+			tag, err := name.NewTag("some-registry.io/some/app:1.0")
+			h.AssertNil(t, err)
+
+			platformTag := imageindex.PlatformTag(tag, v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+			h.AssertEq(t, platformTag.Name(), "some-registry.io/some/app:1.0-linux-arm-v7")
+		})
+	})
+
+	when("#Publish", func() {
+		var (
+			server       *httptest.Server
+			registryHost string
+		)
+
+		it.Before(func() {
+			server = httptest.NewServer(registry.New())
+
+			u, err := url.Parse(server.URL)
+			h.AssertNil(t, err)
+			registryHost = u.Host
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		publishImage := func(tag string) (name.Reference, v1.Hash) {
+			ref, err := name.ParseReference(tag)
+			h.AssertNil(t, err)
+			img, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(ref, img))
+			digest, err := img.Digest()
+			h.AssertNil(t, err)
+			return ref, digest
+		}
+
+		it("publishes an index of the platform images to each tag", func() {
+			amd64Ref, amd64Digest := publishImage(registryHost + "/some/app:latest-linux-amd64")
+			arm64Ref, arm64Digest := publishImage(registryHost + "/some/app:latest-linux-arm64")
+
+			tag, err := name.ParseReference(registryHost + "/some/app:latest")
+			h.AssertNil(t, err)
+			otherTag, err := name.ParseReference(registryHost + "/other/app:1.0")
+			h.AssertNil(t, err)
+
+			digest, err := imageindex.Publish(authn.DefaultKeychain, []imageindex.Manifest{
+				{Ref: amd64Ref, Platform: v1.Platform{OS: "linux", Architecture: "amd64"}},
+				{Ref: arm64Ref, Platform: v1.Platform{OS: "linux", Architecture: "arm64"}},
+			}, tag, otherTag)
+			h.AssertNil(t, err)
+
+			for _, ref := range []name.Reference{tag, otherTag} {
+				index, err := remote.Index(ref)
+				h.AssertNil(t, err)
+
+				indexDigest, err := index.Digest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, indexDigest, digest)
+
+				manifest, err := index.IndexManifest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, manifest.MediaType, types.DockerManifestList)
+				h.AssertEq(t, len(manifest.Manifests), 2)
+				h.AssertEq(t, manifest.Manifests[0].Digest, amd64Digest)
+				h.AssertEq(t, manifest.Manifests[0].Platform.Architecture, "amd64")
+				h.AssertEq(t, manifest.Manifests[1].Digest, arm64Digest)
+				h.AssertEq(t, manifest.Manifests[1].Platform.Architecture, "arm64")
+			}
+		})
+
+		it("fails when a manifest was not published", func() {
+			ref, err := name.ParseReference(registryHost + "/some/app:missing")
+			h.AssertNil(t, err)
+
+			_, err = imageindex.Publish(authn.DefaultKeychain, []imageindex.Manifest{{Ref: ref}}, ref)
+			h.AssertError(t, err, "fetching image")
+		})
+
+		it("requires a manifest", func() {
+			_, err := imageindex.Publish(authn.DefaultKeychain, nil)
+			h.AssertError(t, err, "an image index requires at least one manifest")
+		})
+	})
+}
@@ -0,0 +1,76 @@
+// Package layout writes images into OCI image layout directories.
+package layout
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// DockerClient is the subset of the docker client needed to read images from the daemon.
+type DockerClient interface {
+	daemon.Client
+}
+
+// WriteDaemonImage saves the image ref from the docker daemon into the OCI image layout at dir.
+// The layout is created if it doesn't exist. The image is annotated with the tag or digest of ref
+// so that it can be addressed as `<dir>:<tag>`, replacing any image previously written with the
+// same annotation. The digest of the written image manifest is returned.
+//
+// The image is read from the daemon as the lifecycle exporter can only export to a daemon or a registry.
+// It is streamed from the daemon once, rather than buffered in memory.
+func WriteDaemonImage(ctx context.Context, docker DockerClient, ref name.Reference, dir string) (v1.Hash, error) {
+	img, err := daemon.Image(ref, daemon.WithContext(ctx), daemon.WithClient(docker), daemon.WithUnbufferedOpener())
+	if err != nil {
+		return v1.Hash{}, errors.Wrapf(err, "reading image %s from daemon", ref.Name())
+	}
+
+	return Write(img, ref.Identifier(), dir)
+}
+
+// Write adds img to the OCI image layout at dir, annotated with refName.
+// The layout is created if it doesn't exist.
+func Write(img v1.Image, refName string, dir string) (v1.Hash, error) {
+	path, err := OpenOrCreate(dir)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	annotations := map[string]string{ocispec.AnnotationRefName: refName}
+	if err := path.ReplaceImage(img, match.Annotation(ocispec.AnnotationRefName, refName), layout.WithAnnotations(annotations)); err != nil {
+		return v1.Hash{}, errors.Wrapf(err, "writing image to layout %s", dir)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return v1.Hash{}, errors.Wrap(err, "calculating image digest")
+	}
+
+	return digest, nil
+}
+
+// OpenOrCreate returns the OCI image layout at dir, creating an empty layout when there is none.
+func OpenOrCreate(dir string) (layout.Path, error) {
+	path, err := layout.FromPath(dir)
+	if err == nil {
+		return path, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.Wrapf(err, "reading layout %s", dir)
+	}
+
+	path, err = layout.Write(dir, empty.Index)
+	if err != nil {
+		return "", errors.Wrapf(err, "creating layout %s", dir)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,173 @@
+package layout_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	ggcrlayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/heroku/color"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/layout"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLayout(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Layout", testLayout, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLayout(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir    string
+		layoutDir string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "layout-test")
+		h.AssertNil(t, err)
+		layoutDir = filepath.Join(tmpDir, "some-layout")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Write", func() {
+		it("creates the layout and annotates the image", func() {
+			img, err := random.Image(1024, 2)
+			h.AssertNil(t, err)
+
+			digest, err := layout.Write(img, "latest", layoutDir)
+			h.AssertNil(t, err)
+
+			expectedDigest, err := img.Digest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, digest, expectedDigest)
+
+			_, err = os.Stat(filepath.Join(layoutDir, "oci-layout"))
+			h.AssertNil(t, err)
+			manifests := readManifests(t, layoutDir)
+			h.AssertEq(t, len(manifests), 1)
+			h.AssertEq(t, manifests[0].Digest, expectedDigest)
+			h.AssertEq(t, manifests[0].Annotations[ocispec.AnnotationRefName], "latest")
+		})
+
+		it("replaces an image written with the same ref name", func() {
+			first, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			second, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			other, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+
+			_, err = layout.Write(first, "latest", layoutDir)
+			h.AssertNil(t, err)
+			_, err = layout.Write(other, "other", layoutDir)
+			h.AssertNil(t, err)
+			secondDigest, err := layout.Write(second, "latest", layoutDir)
+			h.AssertNil(t, err)
+
+			manifests := readManifests(t, layoutDir)
+			h.AssertEq(t, len(manifests), 2)
+			h.AssertEq(t, manifests[0].Annotations[ocispec.AnnotationRefName], "other")
+			h.AssertEq(t, manifests[1].Digest, secondDigest)
+			h.AssertEq(t, manifests[1].Annotations[ocispec.AnnotationRefName], "latest")
+		})
+	})
+
+	when("#WriteDaemonImage", func() {
+		it("writes the image saved by the daemon", func() {
+			ref, err := name.ParseReference("some/app:some-tag")
+			h.AssertNil(t, err)
+			img, err := random.Image(1024, 2)
+			h.AssertNil(t, err)
+
+			var saved bytes.Buffer
+			h.AssertNil(t, tarball.Write(ref, img, &saved))
+
+			configName, err := img.ConfigName()
+			h.AssertNil(t, err)
+
+			docker := &fakeDockerClient{saved: saved.Bytes(), imageID: configName.String()}
+			_, err = layout.WriteDaemonImage(context.TODO(), docker, ref, layoutDir)
+			h.AssertNil(t, err)
+			h.AssertEq(t, docker.savedImages, []string{"index.docker.io/some/app:some-tag"})
+
+			manifests := readManifests(t, layoutDir)
+			h.AssertEq(t, len(manifests), 1)
+			h.AssertEq(t, manifests[0].Annotations[ocispec.AnnotationRefName], "some-tag")
+
+			path, err := ggcrlayout.FromPath(layoutDir)
+			h.AssertNil(t, err)
+			written, err := path.Image(manifests[0].Digest)
+			h.AssertNil(t, err)
+			writtenLayers, err := written.Layers()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(writtenLayers), 2)
+		})
+
+		it("fails when the image can't be saved", func() {
+			ref, err := name.ParseReference("some/app")
+			h.AssertNil(t, err)
+
+			_, err = layout.WriteDaemonImage(context.TODO(), &fakeDockerClient{}, ref, layoutDir)
+			h.AssertError(t, err, "writing image to layout")
+		})
+	})
+}
+
+func readManifests(t *testing.T, dir string) []v1.Descriptor {
+	t.Helper()
+
+	path, err := ggcrlayout.FromPath(dir)
+	h.AssertNil(t, err)
+	index, err := path.ImageIndex()
+	h.AssertNil(t, err)
+	manifest, err := index.IndexManifest()
+	h.AssertNil(t, err)
+
+	return manifest.Manifests
+}
+
+type fakeDockerClient struct {
+	saved       []byte
+	savedImages []string
+	imageID     string
+}
+
+func (f *fakeDockerClient) NegotiateAPIVersion(context.Context) {}
+
+func (f *fakeDockerClient) ImageSave(_ context.Context, images []string) (io.ReadCloser, error) {
+	f.savedImages = images
+	if f.saved == nil {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.NopCloser(bytes.NewReader(f.saved)), nil
+}
+
+func (f *fakeDockerClient) ImageLoad(context.Context, io.Reader, bool) (types.ImageLoadResponse, error) {
+	return types.ImageLoadResponse{}, nil
+}
+
+func (f *fakeDockerClient) ImageTag(context.Context, string, string) error {
+	return nil
+}
+
+func (f *fakeDockerClient) ImageInspectWithRaw(context.Context, string) (types.ImageInspect, []byte, error) {
+	return types.ImageInspect{ID: f.imageID}, nil, nil
+}
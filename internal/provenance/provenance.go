@@ -0,0 +1,197 @@
+// Package provenance describes how images are built with in-toto statements holding a SLSA provenance predicate, and
+// publishes them next to the images in the format used by cosign for attestations.
+package provenance
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// StatementType is the type of in-toto statements.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+
+	// PredicateType is the type of SLSA provenance predicates.
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+
+	// PayloadType is the type of the payload of the envelopes holding statements.
+	PayloadType = "application/vnd.in-toto+json"
+
+	// EnvelopeMediaType is the media type of the layers holding the envelopes of the attestations of an image.
+	EnvelopeMediaType types.MediaType = "application/vnd.dsse.envelope.v1+json"
+
+	// PredicateTypeAnnotation is the layer annotation holding the predicate type of an attestation.
+	PredicateTypeAnnotation = "predicateType"
+)
+
+// Statement is an in-toto statement about the subjects, holding a SLSA provenance predicate.
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact identified by its digests, such as {"sha256": "<hex>"}.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate describes how the subjects of a statement were built.
+type Predicate struct {
+	Builder     Builder                `json:"builder"`
+	BuildType   string                 `json:"buildType"`
+	Invocation  Invocation             `json:"invocation"`
+	BuildConfig map[string]interface{} `json:"buildConfig,omitempty"`
+	Metadata    Metadata               `json:"metadata"`
+	Materials   []Material             `json:"materials,omitempty"`
+}
+
+// Builder identifies the platform that ran the build.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation describes the event that started the build.
+type Invocation struct {
+	ConfigSource ConfigSource           `json:"configSource,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	Environment  map[string]interface{} `json:"environment,omitempty"`
+}
+
+// ConfigSource identifies the source the build was configured by.
+type ConfigSource struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint,omitempty"`
+}
+
+// Metadata describes the time the build ran and the completeness of the statement.
+type Metadata struct {
+	BuildStartedOn  *time.Time   `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn *time.Time   `json:"buildFinishedOn,omitempty"`
+	Completeness    Completeness `json:"completeness"`
+	Reproducible    bool         `json:"reproducible"`
+}
+
+// Completeness states whether the parts of the predicate list everything that influenced the build.
+type Completeness struct {
+	Parameters  bool `json:"parameters"`
+	Environment bool `json:"environment"`
+	Materials   bool `json:"materials"`
+}
+
+// Material is an artifact the build was made from, such as an image or the source of the app.
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// envelope is a DSSE envelope holding a statement. Statements published by pack are not signed.
+type envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []signature `json:"signatures"`
+}
+
+type signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// DigestSet returns the digest set of digest, in the form 'algorithm:hex', such as {"sha256": "<hex>"}.
+func DigestSet(digest string) (map[string]string, error) {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing digest %s", style.Symbol(digest))
+	}
+
+	return map[string]string{hash.Algorithm: hash.Hex}, nil
+}
+
+// Tag returns the tag the attestations of the image with digest are published to, such as 'repo:sha256-<hex>.att'.
+func Tag(digest name.Digest) (name.Tag, error) {
+	hash, err := v1.NewHash(digest.DigestStr())
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	return digest.Context().Tag(fmt.Sprintf("%s-%s.att", hash.Algorithm, hash.Hex)), nil
+}
+
+// Attach publishes statement as an attestation of the image with digest, next to the attestations it already has.
+func Attach(digest name.Digest, statement Statement, keychain authn.Keychain) (name.Tag, error) {
+	attTag, err := Tag(digest)
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return name.Tag{}, errors.Wrap(err, "encoding provenance statement")
+	}
+
+	contents, err := json.Marshal(envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []signature{},
+	})
+	if err != nil {
+		return name.Tag{}, errors.Wrap(err, "encoding provenance envelope")
+	}
+
+	attImage, err := attestationImage(attTag, keychain)
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	attImage, err = mutate.Append(attImage, mutate.Addendum{
+		Layer:       static.NewLayer(contents, EnvelopeMediaType),
+		Annotations: map[string]string{PredicateTypeAnnotation: statement.PredicateType},
+	})
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	if err := remote.Write(attTag, attImage, remote.WithAuthFromKeychain(keychain)); err != nil {
+		return name.Tag{}, errors.Wrapf(err, "publishing attestation %s", style.Symbol(attTag.Name()))
+	}
+
+	return attTag, nil
+}
+
+// attestationImage returns the image holding the existing attestations at attTag, or an empty one when there are none.
+func attestationImage(attTag name.Tag, keychain authn.Keychain) (v1.Image, error) {
+	img, err := remote.Image(attTag, remote.WithAuthFromKeychain(keychain))
+	if err == nil {
+		return img, nil
+	}
+
+	if !isNotFound(err) {
+		return nil, errors.Wrapf(err, "fetching attestations %s", style.Symbol(attTag.Name()))
+	}
+
+	return mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON), nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,144 @@
+package provenance_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/provenance"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProvenance(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Provenance", testProvenance, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProvenance(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server    *httptest.Server
+		digest    name.Digest
+		statement provenance.Statement
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New())
+		u, err := url.Parse(server.URL)
+		h.AssertNil(t, err)
+
+		ref, err := name.NewTag(u.Host + "/some/image:latest")
+		h.AssertNil(t, err)
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(ref, img))
+
+		hash, err := img.Digest()
+		h.AssertNil(t, err)
+		digest = ref.Context().Digest(hash.String())
+
+		statement = provenance.Statement{
+			Type:          provenance.StatementType,
+			Subject:       []provenance.Subject{{Name: ref.Context().Name(), Digest: map[string]string{"sha256": hash.Hex}}},
+			PredicateType: provenance.PredicateType,
+			Predicate: provenance.Predicate{
+				Builder:   provenance.Builder{ID: "some-builder-id"},
+				BuildType: "some-build-type",
+			},
+		}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	readStatements := func(attTag name.Tag) []provenance.Statement {
+		t.Helper()
+
+		attImage, err := remote.Image(attTag)
+		h.AssertNil(t, err)
+		manifest, err := attImage.Manifest()
+		h.AssertNil(t, err)
+
+		var statements []provenance.Statement
+		for _, desc := range manifest.Layers {
+			h.AssertEq(t, desc.MediaType, provenance.EnvelopeMediaType)
+			h.AssertEq(t, desc.Annotations[provenance.PredicateTypeAnnotation], provenance.PredicateType)
+
+			layer, err := attImage.LayerByDigest(desc.Digest)
+			h.AssertNil(t, err)
+			rc, err := layer.Compressed()
+			h.AssertNil(t, err)
+			contents, err := ioutil.ReadAll(rc)
+			h.AssertNil(t, err)
+			h.AssertNil(t, rc.Close())
+
+			var env struct {
+				PayloadType string `json:"payloadType"`
+				Payload     string `json:"payload"`
+			}
+			h.AssertNil(t, json.Unmarshal(contents, &env))
+			h.AssertEq(t, env.PayloadType, provenance.PayloadType)
+
+			payload, err := base64.StdEncoding.DecodeString(env.Payload)
+			h.AssertNil(t, err)
+
+			var s provenance.Statement
+			h.AssertNil(t, json.Unmarshal(payload, &s))
+			statements = append(statements, s)
+		}
+		return statements
+	}
+
+	when("#Attach", func() {
+		it("publishes the statement as an attestation of the image", func() {
+			attTag, err := provenance.Attach(digest, statement, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			h.AssertEq(t, attTag.TagStr(), "sha256-"+digest.DigestStr()[len("sha256:"):]+".att")
+
+			h.AssertEq(t, readStatements(attTag), []provenance.Statement{statement})
+		})
+
+		it("keeps the existing attestations", func() {
+			_, err := provenance.Attach(digest, statement, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+
+			other := statement
+			other.Predicate.Builder.ID = "other-builder-id"
+			attTag, err := provenance.Attach(digest, other, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, readStatements(attTag), []provenance.Statement{statement, other})
+		})
+	})
+
+	when("#DigestSet", func() {
+		it("returns the digest by algorithm", func() {
+			hash, err := v1.NewHash(digest.DigestStr())
+			h.AssertNil(t, err)
+
+			set, err := provenance.DigestSet(digest.DigestStr())
+			h.AssertNil(t, err)
+			h.AssertEq(t, set, map[string]string{"sha256": hash.Hex})
+		})
+
+		it("errors for invalid digests", func() {
+			_, err := provenance.DigestSet("some-image-id")
+			h.AssertError(t, err, "parsing digest 'some-image-id'")
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PasswordEnvVar is the environment variable holding the password of encrypted private keys, as used by cosign.
+const PasswordEnvVar = "COSIGN_PASSWORD"
+
+const (
+	pemTypePrivateKey           = "PRIVATE KEY"
+	pemTypeECPrivateKey         = "EC PRIVATE KEY"
+	pemTypeEncryptedCosignKey   = "ENCRYPTED COSIGN PRIVATE KEY"
+	pemTypeEncryptedSigstore    = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	pemTypePublicKey            = "PUBLIC KEY"
+	encryptedKeyKDFScrypt       = "scrypt"
+	encryptedKeyCipherSecretbox = "nacl/secretbox"
+)
+
+// encryptedKey is the format of private keys generated by 'cosign generate-key-pair'.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadPrivateKey reads an ECDSA or RSA private key from a PEM file. Keys encrypted by cosign are decrypted with the
+// password in the COSIGN_PASSWORD environment variable.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case pemTypePrivateKey:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case pemTypeECPrivateKey:
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case pemTypeEncryptedCosignKey, pemTypeEncryptedSigstore:
+		var der []byte
+		der, err = decryptKey(block.Bytes, []byte(os.Getenv(PasswordEnvVar)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decrypting private key %s", style.Symbol(path))
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	default:
+		return nil, errors.Errorf("unsupported private key type %s in %s", style.Symbol(block.Type), style.Symbol(path))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing private key %s", style.Symbol(path))
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, errors.Errorf("unsupported private key algorithm in %s, only ECDSA and RSA keys are supported", style.Symbol(path))
+	}
+}
+
+// LoadPublicKey reads an ECDSA or RSA public key from a PEM file.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type != pemTypePublicKey {
+		return nil, errors.Errorf("unsupported public key type %s in %s", style.Symbol(block.Type), style.Symbol(path))
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing public key %s", style.Symbol(path))
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return k, nil
+	case *rsa.PublicKey:
+		return k, nil
+	default:
+		return nil, errors.Errorf("unsupported public key algorithm in %s, only ECDSA and RSA keys are supported", style.Symbol(path))
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading key %s", style.Symbol(path))
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, errors.Errorf("key %s is not PEM encoded", style.Symbol(path))
+	}
+
+	return block, nil
+}
+
+func decryptKey(data, password []byte) ([]byte, error) {
+	var key encryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, errors.Wrap(err, "parsing encrypted key")
+	}
+
+	if key.KDF.Name != encryptedKeyKDFScrypt {
+		return nil, errors.Errorf("unsupported key derivation function %s", style.Symbol(key.KDF.Name))
+	}
+
+	if key.Cipher.Name != encryptedKeyCipherSecretbox {
+		return nil, errors.Errorf("unsupported cipher %s", style.Symbol(key.Cipher.Name))
+	}
+
+	if len(key.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce")
+	}
+
+	secret, err := scrypt.Key(password, key.KDF.Salt, key.KDF.Params.N, key.KDF.Params.R, key.KDF.Params.P, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key")
+	}
+
+	var (
+		nonce     [24]byte
+		secretKey [32]byte
+	)
+	copy(nonce[:], key.Cipher.Nonce)
+	copy(secretKey[:], secret)
+
+	der, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.Errorf("invalid password, set it with %s", style.Symbol(PasswordEnvVar))
+	}
+
+	return der, nil
+}
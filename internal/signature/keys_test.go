@@ -0,0 +1,198 @@
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/buildpacks/pack/internal/signature"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestKeys(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Keys", testKeys, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testKeys(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir string
+		key    *ecdsa.PrivateKey
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "signature-keys")
+		h.AssertNil(t, err)
+
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#LoadPrivateKey", func() {
+		it("loads PKCS8 keys", func() {
+			path := writePrivateKey(t, tmpDir, key)
+
+			loaded, err := signature.LoadPrivateKey(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, loaded.Public().(*ecdsa.PublicKey).Equal(&key.PublicKey), true)
+		})
+
+		it("loads EC keys", func() {
+			der, err := x509.MarshalECPrivateKey(key)
+			h.AssertNil(t, err)
+			path := writePEM(t, tmpDir, "EC PRIVATE KEY", der)
+
+			loaded, err := signature.LoadPrivateKey(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, loaded.Public().(*ecdsa.PublicKey).Equal(&key.PublicKey), true)
+		})
+
+		when("the key is encrypted by cosign", func() {
+			var path string
+
+			it.Before(func() {
+				path = writeEncryptedPrivateKey(t, tmpDir, key, "some-password")
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Unsetenv(signature.PasswordEnvVar))
+			})
+
+			it("decrypts the key with the password", func() {
+				h.AssertNil(t, os.Setenv(signature.PasswordEnvVar, "some-password"))
+
+				loaded, err := signature.LoadPrivateKey(path)
+				h.AssertNil(t, err)
+				h.AssertEq(t, loaded.Public().(*ecdsa.PublicKey).Equal(&key.PublicKey), true)
+			})
+
+			it("fails for an invalid password", func() {
+				h.AssertNil(t, os.Setenv(signature.PasswordEnvVar, "other-password"))
+
+				_, err := signature.LoadPrivateKey(path)
+				h.AssertError(t, err, "invalid password, set it with 'COSIGN_PASSWORD'")
+			})
+		})
+
+		it("fails for unsupported algorithms", func() {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			h.AssertNil(t, err)
+			der, err := x509.MarshalPKCS8PrivateKey(edKey)
+			h.AssertNil(t, err)
+			path := writePEM(t, tmpDir, "PRIVATE KEY", der)
+
+			_, err = signature.LoadPrivateKey(path)
+			h.AssertError(t, err, "only ECDSA and RSA keys are supported")
+		})
+
+		it("fails for files that are not PEM encoded", func() {
+			path := filepath.Join(tmpDir, "key")
+			h.AssertNil(t, ioutil.WriteFile(path, []byte("some-key"), 0600))
+
+			_, err := signature.LoadPrivateKey(path)
+			h.AssertError(t, err, "is not PEM encoded")
+		})
+	})
+
+	when("#LoadPublicKey", func() {
+		it("loads PKIX keys", func() {
+			path := writePublicKey(t, tmpDir, key)
+
+			loaded, err := signature.LoadPublicKey(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, loaded.(*ecdsa.PublicKey).Equal(&key.PublicKey), true)
+		})
+
+		it("fails for private keys", func() {
+			path := writePrivateKey(t, tmpDir, key)
+
+			_, err := signature.LoadPublicKey(path)
+			h.AssertError(t, err, "unsupported public key type 'PRIVATE KEY'")
+		})
+	})
+}
+
+func writePEM(t *testing.T, dir, pemType string, der []byte) string {
+	t.Helper()
+
+	f, err := ioutil.TempFile(dir, "key")
+	h.AssertNil(t, err)
+	defer f.Close()
+
+	h.AssertNil(t, pem.Encode(f, &pem.Block{Type: pemType, Bytes: der}))
+	return f.Name()
+}
+
+func writePrivateKey(t *testing.T, dir string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	h.AssertNil(t, err)
+	return writePEM(t, dir, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, dir string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	h.AssertNil(t, err)
+	return writePEM(t, dir, "PUBLIC KEY", der)
+}
+
+// writeEncryptedPrivateKey writes key encrypted as by 'cosign generate-key-pair', with cheaper scrypt parameters.
+func writeEncryptedPrivateKey(t *testing.T, dir string, key *ecdsa.PrivateKey, password string) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	h.AssertNil(t, err)
+
+	var (
+		salt      = make([]byte, 32)
+		nonce     [24]byte
+		secretKey [32]byte
+	)
+	_, err = rand.Read(salt)
+	h.AssertNil(t, err)
+	_, err = rand.Read(nonce[:])
+	h.AssertNil(t, err)
+
+	secret, err := scrypt.Key([]byte(password), salt, 1024, 8, 1, 32)
+	h.AssertNil(t, err)
+	copy(secretKey[:], secret)
+
+	contents, err := json.Marshal(map[string]interface{}{
+		"kdf": map[string]interface{}{
+			"name":   "scrypt",
+			"params": map[string]int{"N": 1024, "r": 8, "p": 1},
+			"salt":   salt,
+		},
+		"cipher": map[string]interface{}{
+			"name":  "nacl/secretbox",
+			"nonce": nonce[:],
+		},
+		"ciphertext": secretbox.Seal(nil, der, &nonce, &secretKey),
+	})
+	h.AssertNil(t, err)
+
+	return writePEM(t, dir, "ENCRYPTED COSIGN PRIVATE KEY", contents)
+}
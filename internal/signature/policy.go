@@ -0,0 +1,122 @@
+package signature
+
+import (
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PolicyVerifier verifies the signatures of images matching the verify policies of the pack config.
+type PolicyVerifier struct {
+	policies []config.VerifyPolicy
+	keychain authn.Keychain
+}
+
+// NewPolicyVerifier returns a verifier for the policies, fetching signatures with the credentials of keychain.
+func NewPolicyVerifier(policies []config.VerifyPolicy, keychain authn.Keychain) *PolicyVerifier {
+	return &PolicyVerifier{
+		policies: policies,
+		keychain: keychain,
+	}
+}
+
+// Verify ensures the image named imageName, resolved to digest, is signed by the key of one of the policies matching
+// it. Images no policy matches are not verified. When imageName refers to an image index, a signature of the index
+// is accepted for the images it contains.
+func (v *PolicyVerifier) Verify(imageName string, digest name.Digest) error {
+	var keys []string
+	for _, policy := range v.policies {
+		if matchesImage(policy.Image, imageName) {
+			keys = append(keys, policy.Key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	digests := []name.Digest{digest}
+	if indexDigest, ok := v.indexDigest(imageName, digest); ok {
+		digests = append(digests, indexDigest)
+	}
+
+	for _, keyPath := range keys {
+		key, err := LoadPublicKey(keyPath)
+		if err != nil {
+			return errors.Wrapf(err, "loading key of verify policy for %s", style.Symbol(imageName))
+		}
+
+		for _, d := range digests {
+			if err := Verify(d, key, v.keychain); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return errors.Errorf("image %s is not signed by the key of a matching verify policy", style.Symbol(imageName))
+}
+
+// indexDigest returns the digest of the image index imageName refers to, when it contains the image with digest.
+func (v *PolicyVerifier) indexDigest(imageName string, digest name.Digest) (name.Digest, bool) {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return name.Digest{}, false
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(v.keychain))
+	if err != nil || !desc.MediaType.IsIndex() {
+		return name.Digest{}, false
+	}
+
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return name.Digest{}, false
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return name.Digest{}, false
+	}
+
+	for _, m := range manifest.Manifests {
+		if m.Digest.String() == digest.DigestStr() {
+			return ref.Context().Digest(desc.Digest.String()), true
+		}
+	}
+
+	return name.Digest{}, false
+}
+
+// matchesImage returns whether the repository of imageName matches pattern. A pattern ending with '*' matches
+// repositories starting with the rest of the pattern, such as 'gcr.io/my-org/*'.
+func matchesImage(pattern, imageName string) bool {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return false
+	}
+	repo := ref.Context().Name()
+
+	if strings.HasSuffix(pattern, "*") {
+		// normalize the prefix as a repository, so 'my-org/*' matches images on docker hub
+		const placeholder = "placeholder"
+		prefixRepo, err := name.NewRepository(strings.TrimSuffix(pattern, "*")+placeholder, name.WeakValidation)
+		if err != nil {
+			return false
+		}
+
+		return strings.HasPrefix(repo, strings.TrimSuffix(prefixRepo.Name(), placeholder))
+	}
+
+	patternRef, err := name.ParseReference(pattern, name.WeakValidation)
+	if err != nil {
+		return false
+	}
+
+	return patternRef.Context().Name() == repo
+}
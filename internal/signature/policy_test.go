@@ -0,0 +1,155 @@
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/signature"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPolicyVerifier(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PolicyVerifier", testPolicyVerifier, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPolicyVerifier(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server       *httptest.Server
+		registryHost string
+		tmpDir       string
+		key          *ecdsa.PrivateKey
+		keyPath      string
+		imageName    string
+		digest       name.Digest
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New())
+		u, err := url.Parse(server.URL)
+		h.AssertNil(t, err)
+		registryHost = u.Host
+
+		tmpDir, err = ioutil.TempDir("", "policy-verifier")
+		h.AssertNil(t, err)
+
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		h.AssertNil(t, err)
+		keyPath = writePublicKey(t, tmpDir, key)
+
+		imageName = registryHost + "/some-org/image:latest"
+		ref, err := name.NewTag(imageName)
+		h.AssertNil(t, err)
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(ref, img))
+
+		hash, err := img.Digest()
+		h.AssertNil(t, err)
+		digest = ref.Context().Digest(hash.String())
+	})
+
+	it.After(func() {
+		server.Close()
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	sign := func(digest name.Digest) {
+		h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+	}
+
+	when("#Verify", func() {
+		it("ignores images no policy matches", func() {
+			verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+				{Image: registryHost + "/other-org/image", Key: keyPath},
+			}, authn.DefaultKeychain)
+
+			h.AssertNil(t, verifier.Verify(imageName, digest))
+		})
+
+		it("succeeds for signed images matching a policy", func() {
+			sign(digest)
+			verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+				{Image: registryHost + "/some-org/image", Key: keyPath},
+			}, authn.DefaultKeychain)
+
+			h.AssertNil(t, verifier.Verify(imageName, digest))
+		})
+
+		it("fails for unsigned images matching a policy", func() {
+			verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+				{Image: registryHost + "/some-org/image", Key: keyPath},
+			}, authn.DefaultKeychain)
+
+			err := verifier.Verify(imageName, digest)
+			h.AssertError(t, err, "image '"+imageName+"' is not signed by the key of a matching verify policy")
+		})
+
+		it("matches policies with a wildcard", func() {
+			verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+				{Image: registryHost + "/some-org/*", Key: keyPath},
+			}, authn.DefaultKeychain)
+
+			err := verifier.Verify(imageName, digest)
+			h.AssertError(t, err, "is not signed by the key of a matching verify policy")
+
+			sign(digest)
+			h.AssertNil(t, verifier.Verify(imageName, digest))
+		})
+
+		it("fails when the key can't be loaded", func() {
+			verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+				{Image: registryHost + "/some-org/image", Key: "some-missing-key"},
+			}, authn.DefaultKeychain)
+
+			err := verifier.Verify(imageName, digest)
+			h.AssertError(t, err, "loading key of verify policy")
+		})
+
+		when("the image is part of a signed image index", func() {
+			it("accepts the signature of the index", func() {
+				indexName := registryHost + "/some-org/image:index"
+				img, err := remote.Image(digest)
+				h.AssertNil(t, err)
+
+				index := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{
+					Add:        img,
+					Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+				})
+				indexRef, err := name.NewTag(indexName)
+				h.AssertNil(t, err)
+				h.AssertNil(t, remote.WriteIndex(indexRef, index))
+				indexHash, err := index.Digest()
+				h.AssertNil(t, err)
+				sign(indexRef.Context().Digest(indexHash.String()))
+
+				verifier := signature.NewPolicyVerifier([]config.VerifyPolicy{
+					{Image: registryHost + "/some-org/image", Key: keyPath},
+				}, authn.DefaultKeychain)
+
+				h.AssertNil(t, verifier.Verify(indexName, digest))
+			})
+		})
+	})
+}
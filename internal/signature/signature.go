@@ -0,0 +1,232 @@
+// Package signature signs and verifies images in the format used by cosign, so that images signed by pack can be
+// verified by cosign and the other way around.
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// SimpleSigningMediaType is the media type of the signed payloads.
+	SimpleSigningMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// SignatureAnnotation is the layer annotation holding the base64 encoded signature of the payload.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	payloadType = "cosign container image signature"
+)
+
+// payload is the simple signing payload that is signed, identifying the image by digest.
+type payload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// Tag returns the tag the signatures of the image with digest are published to, such as 'repo:sha256-<hex>.sig'.
+func Tag(digest name.Digest) (name.Tag, error) {
+	hash, err := v1.NewHash(digest.DigestStr())
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	return digest.Context().Tag(fmt.Sprintf("%s-%s.sig", hash.Algorithm, hash.Hex)), nil
+}
+
+// Sign signs the image with digest with key and publishes the signature next to it.
+// The image is identified by its digest rather than a tag, as a tag may be moved to another image once published.
+func Sign(digest name.Digest, key crypto.Signer, keychain authn.Keychain) error {
+	contents, err := newPayload(digest)
+	if err != nil {
+		return err
+	}
+
+	sig, err := signPayload(key, contents)
+	if err != nil {
+		return errors.Wrapf(err, "signing %s", style.Symbol(digest.Name()))
+	}
+
+	sigTag, err := Tag(digest)
+	if err != nil {
+		return err
+	}
+
+	sigImage, err := signatureImage(sigTag, keychain)
+	if err != nil {
+		return err
+	}
+
+	layer := static.NewLayer(contents, SimpleSigningMediaType)
+	layerDigest, err := layer.Digest()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return err
+	}
+	for _, l := range manifest.Layers {
+		if l.Digest == layerDigest {
+			// payloads only depend on the digest, the image is already signed
+			return nil
+		}
+	}
+
+	sigImage, err = mutate.Append(sigImage, mutate.Addendum{
+		Layer:       layer,
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := remote.Write(sigTag, sigImage, remote.WithAuthFromKeychain(keychain)); err != nil {
+		return errors.Wrapf(err, "publishing signature %s", style.Symbol(sigTag.Name()))
+	}
+
+	return nil
+}
+
+// Verify ensures the image with digest has a signature from the owner of key.
+func Verify(digest name.Digest, key crypto.PublicKey, keychain authn.Keychain) error {
+	sigTag, err := Tag(digest)
+	if err != nil {
+		return err
+	}
+
+	sigImage, err := remote.Image(sigTag, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		if isNotFound(err) {
+			return errors.Errorf("no signatures found for %s", style.Symbol(digest.Name()))
+		}
+		return errors.Wrapf(err, "fetching signatures of %s", style.Symbol(digest.Name()))
+	}
+
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range manifest.Layers {
+		encoded, ok := desc.Annotations[SignatureAnnotation]
+		if !ok || desc.MediaType != SimpleSigningMediaType {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+
+		layer, err := sigImage.LayerByDigest(desc.Digest)
+		if err != nil {
+			return err
+		}
+
+		contents, err := readLayer(layer)
+		if err != nil {
+			return err
+		}
+
+		if verifyPayload(key, contents, sig) && payloadMatches(contents, digest) {
+			return nil
+		}
+	}
+
+	return errors.Errorf("no valid signatures found for %s", style.Symbol(digest.Name()))
+}
+
+func newPayload(digest name.Digest) ([]byte, error) {
+	var p payload
+	p.Critical.Identity.DockerReference = digest.Context().Name()
+	p.Critical.Image.DockerManifestDigest = digest.DigestStr()
+	p.Critical.Type = payloadType
+
+	return json.Marshal(p)
+}
+
+func payloadMatches(contents []byte, digest name.Digest) bool {
+	var p payload
+	if err := json.Unmarshal(contents, &p); err != nil {
+		return false
+	}
+
+	return p.Critical.Type == payloadType && p.Critical.Image.DockerManifestDigest == digest.DigestStr()
+}
+
+func signPayload(key crypto.Signer, contents []byte) ([]byte, error) {
+	hash := sha256.Sum256(contents)
+	return key.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+func verifyPayload(key crypto.PublicKey, contents, sig []byte) bool {
+	hash := sha256.Sum256(contents)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil
+	default:
+		return false
+	}
+}
+
+// signatureImage returns the image holding the existing signatures at sigTag, or an empty one when there are none.
+func signatureImage(sigTag name.Tag, keychain authn.Keychain) (v1.Image, error) {
+	img, err := remote.Image(sigTag, remote.WithAuthFromKeychain(keychain))
+	if err == nil {
+		return img, nil
+	}
+
+	if !isNotFound(err) {
+		return nil, errors.Wrapf(err, "fetching signatures %s", style.Symbol(sigTag.Name()))
+	}
+
+	return mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON), nil
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(rc)
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,143 @@
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/signature"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSignature(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Signature", testSignature, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSignature(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server *httptest.Server
+		key    *ecdsa.PrivateKey
+		ref    name.Tag
+		digest name.Digest
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New())
+		u, err := url.Parse(server.URL)
+		h.AssertNil(t, err)
+
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		h.AssertNil(t, err)
+
+		ref, err = name.NewTag(u.Host + "/some/image:latest")
+		h.AssertNil(t, err)
+
+		img, err := random.Image(1024, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(ref, img))
+
+		hash, err := img.Digest()
+		h.AssertNil(t, err)
+		digest = ref.Context().Digest(hash.String())
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#Sign", func() {
+		it("publishes a signature for the digest of the image", func() {
+			h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+
+			sigTag, err := signature.Tag(digest)
+			h.AssertNil(t, err)
+			h.AssertEq(t, sigTag.TagStr(), "sha256-"+digest.DigestStr()[len("sha256:"):]+".sig")
+
+			sigImage, err := remote.Image(sigTag)
+			h.AssertNil(t, err)
+			manifest, err := sigImage.Manifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Layers), 1)
+			h.AssertEq(t, manifest.Layers[0].MediaType, signature.SimpleSigningMediaType)
+			h.AssertNotEq(t, manifest.Layers[0].Annotations[signature.SignatureAnnotation], "")
+
+			layer, err := sigImage.LayerByDigest(manifest.Layers[0].Digest)
+			h.AssertNil(t, err)
+			rc, err := layer.Compressed()
+			h.AssertNil(t, err)
+			defer rc.Close()
+			contents, err := ioutil.ReadAll(rc)
+			h.AssertNil(t, err)
+
+			var payload map[string]map[string]interface{}
+			h.AssertNil(t, json.Unmarshal(contents, &payload))
+			h.AssertEq(t, payload["critical"]["type"], "cosign container image signature")
+			h.AssertEq(t, payload["critical"]["identity"], map[string]interface{}{"docker-reference": ref.Context().Name()})
+			h.AssertEq(t, payload["critical"]["image"], map[string]interface{}{"docker-manifest-digest": digest.DigestStr()})
+		})
+
+		it("signs the image with the digest once its tag is moved", func() {
+			other, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(ref, other))
+
+			h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+
+			h.AssertNil(t, signature.Verify(digest, &key.PublicKey, authn.DefaultKeychain))
+			otherHash, err := other.Digest()
+			h.AssertNil(t, err)
+			h.AssertNotNil(t, signature.Verify(ref.Context().Digest(otherHash.String()), &key.PublicKey, authn.DefaultKeychain))
+		})
+
+		it("doesn't sign the same image twice", func() {
+			h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+			h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+
+			sigTag, err := signature.Tag(digest)
+			h.AssertNil(t, err)
+			sigImage, err := remote.Image(sigTag)
+			h.AssertNil(t, err)
+			manifest, err := sigImage.Manifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Layers), 1)
+		})
+	})
+
+	when("#Verify", func() {
+		it("succeeds for signed images", func() {
+			h.AssertNil(t, signature.Sign(digest, key, authn.DefaultKeychain))
+
+			h.AssertNil(t, signature.Verify(digest, &key.PublicKey, authn.DefaultKeychain))
+		})
+
+		it("fails for images signed with another key", func() {
+			otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			h.AssertNil(t, err)
+			h.AssertNil(t, signature.Sign(digest, otherKey, authn.DefaultKeychain))
+
+			err = signature.Verify(digest, &key.PublicKey, authn.DefaultKeychain)
+			h.AssertError(t, err, "no valid signatures found for '"+digest.Name()+"'")
+		})
+
+		it("fails for unsigned images", func() {
+			err := signature.Verify(digest, &key.PublicKey, authn.DefaultKeychain)
+			h.AssertError(t, err, "no signatures found for '"+digest.Name()+"'")
+		})
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 
+	ilayout "github.com/buildpacks/pack/internal/layout"
 	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/archive"
@@ -151,13 +152,22 @@ func (b *PackageBuilder) resolvedStacks() []dist.Stack {
 }
 
 func (b *PackageBuilder) SaveAsFile(path, imageOS string) error {
-	if err := b.validate(); err != nil {
-		return err
-	}
+	return SavePlatformsAsFile(path, []PlatformPackage{{Builder: b, Platform: v1.Platform{OS: imageOS}}})
+}
 
-	layoutImage, err := newLayoutImage(imageOS)
-	if err != nil {
-		return errors.Wrap(err, "creating layout image")
+// PlatformPackage is a buildpackage built for a single platform.
+type PlatformPackage struct {
+	Builder  *PackageBuilder
+	Platform v1.Platform
+}
+
+// SavePlatformsAsFile writes the packages to a single OCI layout archive at path.
+// Each package is a manifest of the layout index, described by its platform when it has an architecture.
+func SavePlatformsAsFile(path string, packages []PlatformPackage) error {
+	for _, pkg := range packages {
+		if err := pkg.Builder.validate(); err != nil {
+			return err
+		}
 	}
 
 	tmpDir, err := ioutil.TempDir("", "package-buildpack")
@@ -166,22 +176,40 @@ func (b *PackageBuilder) SaveAsFile(path, imageOS string) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	if err := b.finalizeImage(layoutImage, tmpDir); err != nil {
-		return err
-	}
-
 	layoutDir, err := ioutil.TempDir(tmpDir, "oci-layout")
 	if err != nil {
 		return errors.Wrap(err, "creating oci-layout temp dir")
 	}
 
-	p, err := layout.Write(layoutDir, empty.Index)
+	p, err := ilayout.OpenOrCreate(layoutDir)
 	if err != nil {
-		return errors.Wrap(err, "writing index")
+		return err
 	}
 
-	if err := p.AppendImage(layoutImage); err != nil {
-		return errors.Wrap(err, "writing layout")
+	for _, pkg := range packages {
+		layoutImage, err := newLayoutImage(pkg.Platform)
+		if err != nil {
+			return errors.Wrap(err, "creating layout image")
+		}
+
+		// buildpack layer tars are named after the buildpack, use a directory per package as platforms share buildpack ids
+		pkgDir, err := ioutil.TempDir(tmpDir, "package")
+		if err != nil {
+			return err
+		}
+
+		if err := pkg.Builder.finalizeImage(layoutImage, pkgDir); err != nil {
+			return err
+		}
+
+		var opts []layout.Option
+		if pkg.Platform.Architecture != "" {
+			opts = append(opts, layout.WithPlatform(pkg.Platform))
+		}
+
+		if err := p.AppendImage(layoutImage, opts...); err != nil {
+			return errors.Wrap(err, "writing layout")
+		}
 	}
 
 	outputFile, err := os.Create(path)
@@ -196,7 +224,7 @@ func (b *PackageBuilder) SaveAsFile(path, imageOS string) error {
 	return archive.WriteDirToTar(tw, layoutDir, "/", 0, 0, 0755, true, false, nil)
 }
 
-func newLayoutImage(imageOS string) (*layoutImage, error) {
+func newLayoutImage(platform v1.Platform) (*layoutImage, error) {
 	i := empty.Image
 
 	configFile, err := i.ConfigFile()
@@ -204,7 +232,9 @@ func newLayoutImage(imageOS string) (*layoutImage, error) {
 		return nil, err
 	}
 
+	imageOS := platform.OS
 	configFile.OS = imageOS
+	configFile.Architecture = platform.Architecture
 	i, err = mutate.ConfigFile(i, configFile)
 	if err != nil {
 		return nil, err
@@ -231,15 +261,27 @@ func newLayoutImage(imageOS string) (*layoutImage, error) {
 }
 
 func (b *PackageBuilder) SaveAsImage(repoName string, publish bool, imageOS string) (imgutil.Image, error) {
+	return b.SaveAsPlatformImage(repoName, publish, v1.Platform{OS: imageOS})
+}
+
+// SaveAsPlatformImage saves the package as an image for the platform.
+// The architecture of the image is only set when the platform has one.
+func (b *PackageBuilder) SaveAsPlatformImage(repoName string, publish bool, platform v1.Platform) (imgutil.Image, error) {
 	if err := b.validate(); err != nil {
 		return nil, err
 	}
 
-	image, err := b.imageFactory.NewImage(repoName, !publish, imageOS)
+	image, err := b.imageFactory.NewImage(repoName, !publish, platform.OS)
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating image")
 	}
 
+	if platform.Architecture != "" {
+		if err := image.SetArchitecture(platform.Architecture); err != nil {
+			return nil, errors.Wrapf(err, "setting image architecture")
+		}
+	}
+
 	tmpDir, err := ioutil.TempDir("", "package-buildpack")
 	if err != nil {
 		return nil, err
@@ -16,6 +16,7 @@ import (
 	"github.com/buildpacks/imgutil/layer"
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/golang/mock/gomock"
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/stream"
 	"github.com/heroku/color"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -576,6 +577,73 @@ func testPackageBuilder(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#SaveAsPlatformImage", func() {
+		it("sets the architecture", func() {
+			buildpack1, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+				API:    api.MustParse("0.2"),
+				Info:   dist.BuildpackInfo{ID: "bp.1.id", Version: "bp.1.version"},
+				Stacks: []dist.Stack{{ID: "stack.id.1"}},
+			}, 0644)
+			h.AssertNil(t, err)
+
+			builder := buildpack.NewBuilder(mockImageFactory("linux"))
+			builder.SetBuildpack(buildpack1)
+
+			packageImage, err := builder.SaveAsPlatformImage("some/package", false, ggcrv1.Platform{OS: "linux", Architecture: "arm64"})
+			h.AssertNil(t, err)
+
+			arch, err := packageImage.Architecture()
+			h.AssertNil(t, err)
+			h.AssertEq(t, arch, "arm64")
+		})
+	})
+
+	when("#SavePlatformsAsFile", func() {
+		it("writes a manifest per platform", func() {
+			var packages []buildpack.PlatformPackage
+			for _, platform := range []ggcrv1.Platform{
+				{OS: "linux", Architecture: "amd64"},
+				{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			} {
+				bp, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+					API:    api.MustParse("0.2"),
+					Info:   dist.BuildpackInfo{ID: "bp.1.id", Version: "bp.1.version"},
+					Stacks: []dist.Stack{{ID: "stack.id.1"}},
+				}, 0644, ifakes.WithExtraBuildpackContents("bin/"+platform.Architecture, platform.Architecture))
+				h.AssertNil(t, err)
+
+				builder := buildpack.NewBuilder(mockImageFactory(""))
+				builder.SetBuildpack(bp)
+				packages = append(packages, buildpack.PlatformPackage{Builder: builder, Platform: platform})
+			}
+
+			outputFile := filepath.Join(tmpDir, fmt.Sprintf("package-%s.cnb", h.RandString(10)))
+			h.AssertNil(t, buildpack.SavePlatformsAsFile(outputFile, packages))
+
+			h.AssertOnTarEntry(t, outputFile, "/index.json",
+				func(t *testing.T, header *tar.Header, data []byte) {
+					index := v1.Index{}
+					h.AssertNil(t, json.Unmarshal(data, &index))
+					h.AssertEq(t, len(index.Manifests), 2)
+					h.AssertEq(t, *index.Manifests[0].Platform, v1.Platform{OS: "linux", Architecture: "amd64"})
+					h.AssertEq(t, *index.Manifests[1].Platform, v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+					h.AssertNotEq(t, index.Manifests[0].Digest, index.Manifests[1].Digest)
+
+					h.AssertOnTarEntry(t, outputFile,
+						"/blobs/sha256/"+index.Manifests[1].Digest.Hex(),
+						func(t *testing.T, header *tar.Header, data []byte) {
+							manifest := v1.Manifest{}
+							h.AssertNil(t, json.Unmarshal(data, &manifest))
+
+							h.AssertOnTarEntry(t, outputFile,
+								"/blobs/sha256/"+manifest.Config.Digest.Hex(),
+								h.ContentContains(`"architecture":"arm64"`),
+							)
+						})
+				})
+		})
+	})
+
 	when("#SaveAsFile", func() {
 		it("sets metadata", func() {
 			buildpack1, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
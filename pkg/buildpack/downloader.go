@@ -73,6 +73,10 @@ type DownloadOptions struct {
 	Daemon bool
 
 	PullPolicy image.PullPolicy
+
+	// Platform of the buildpackage image to fetch, in the form 'os/arch[/variant]'.
+	// Defaults to the platform of the daemon or registry.
+	Platform string
 }
 
 func (c *buildpackDownloader) Download(ctx context.Context, buildpackURI string, opts DownloadOptions) (Buildpack, []Buildpack, error) {
@@ -95,7 +99,7 @@ func (c *buildpackDownloader) Download(ctx context.Context, buildpackURI string,
 	case PackageLocator:
 		imageName := ParsePackageLocator(buildpackURI)
 		c.logger.Debugf("Downloading buildpack from image: %s", style.Symbol(imageName))
-		mainBP, depBPs, err = extractPackagedBuildpacks(ctx, imageName, c.imageFetcher, image.FetchOptions{Daemon: opts.Daemon, PullPolicy: opts.PullPolicy})
+		mainBP, depBPs, err = extractPackagedBuildpacks(ctx, imageName, c.imageFetcher, image.FetchOptions{Daemon: opts.Daemon, PullPolicy: opts.PullPolicy, Platform: opts.Platform})
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "extracting from registry %s", style.Symbol(buildpackURI))
 		}
@@ -106,7 +110,7 @@ func (c *buildpackDownloader) Download(ctx context.Context, buildpackURI string,
 			return nil, nil, errors.Wrapf(err, "locating in registry: %s", style.Symbol(buildpackURI))
 		}
 
-		mainBP, depBPs, err = extractPackagedBuildpacks(ctx, address, c.imageFetcher, image.FetchOptions{Daemon: opts.Daemon, PullPolicy: opts.PullPolicy})
+		mainBP, depBPs, err = extractPackagedBuildpacks(ctx, address, c.imageFetcher, image.FetchOptions{Daemon: opts.Daemon, PullPolicy: opts.PullPolicy, Platform: opts.Platform})
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "extracting from registry %s", style.Symbol(buildpackURI))
 		}
@@ -123,7 +127,7 @@ func (c *buildpackDownloader) Download(ctx context.Context, buildpackURI string,
 			return nil, nil, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(buildpackURI))
 		}
 
-		mainBP, depBPs, err = decomposeBuildpack(blob, opts.ImageOS)
+		mainBP, depBPs, err = decomposeBuildpack(blob, opts.ImageOS, opts.Platform)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "extracting from %s", style.Symbol(buildpackURI))
 		}
@@ -134,14 +138,14 @@ func (c *buildpackDownloader) Download(ctx context.Context, buildpackURI string,
 }
 
 // decomposeBuildpack decomposes a buildpack blob into the main builder (order buildpack) and it's dependencies buildpacks.
-func decomposeBuildpack(blob blob.Blob, imageOS string) (mainBP Buildpack, depBPs []Buildpack, err error) {
+func decomposeBuildpack(blob blob.Blob, imageOS, platform string) (mainBP Buildpack, depBPs []Buildpack, err error) {
 	isOCILayout, err := IsOCILayoutBlob(blob)
 	if err != nil {
 		return mainBP, depBPs, errors.Wrap(err, "inspecting buildpack blob")
 	}
 
 	if isOCILayout {
-		mainBP, depBPs, err = BuildpacksFromOCILayoutBlob(blob)
+		mainBP, depBPs, err = BuildpacksFromOCILayoutBlobForPlatform(blob, platform)
 		if err != nil {
 			return mainBP, depBPs, errors.Wrap(err, "extracting buildpacks")
 		}
@@ -40,7 +40,13 @@ func IsOCILayoutBlob(blob blob2.Blob) (bool, error) {
 
 // BuildpackFromOCILayoutBlob constructs buildpacks from a blob in OCI layout format.
 func BuildpacksFromOCILayoutBlob(blob Blob) (mainBP Buildpack, dependencies []Buildpack, err error) {
-	layoutPackage, err := newOCILayoutPackage(blob)
+	return BuildpacksFromOCILayoutBlobForPlatform(blob, "")
+}
+
+// BuildpacksFromOCILayoutBlobForPlatform constructs buildpacks from a blob in OCI layout format, using the manifest
+// for the platform, in the form 'os/arch[/variant]', when the layout contains a manifest per platform.
+func BuildpacksFromOCILayoutBlobForPlatform(blob Blob, platform string) (mainBP Buildpack, dependencies []Buildpack, err error) {
+	layoutPackage, err := newOCILayoutPackage(blob, platform)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -49,7 +55,7 @@ func BuildpacksFromOCILayoutBlob(blob Blob) (mainBP Buildpack, dependencies []Bu
 }
 
 func ConfigFromOCILayoutBlob(blob Blob) (config v1.ImageConfig, err error) {
-	layoutPackage, err := newOCILayoutPackage(blob)
+	layoutPackage, err := newOCILayoutPackage(blob, "")
 	if err != nil {
 		return v1.ImageConfig{}, err
 	}
@@ -62,7 +68,7 @@ type ociLayoutPackage struct {
 	blob      Blob
 }
 
-func newOCILayoutPackage(blob Blob) (*ociLayoutPackage, error) {
+func newOCILayoutPackage(blob Blob, platform string) (*ociLayoutPackage, error) {
 	index := &v1.Index{}
 
 	if err := unmarshalJSONFromBlob(blob, "/index.json", index); err != nil {
@@ -71,13 +77,25 @@ func newOCILayoutPackage(blob Blob) (*ociLayoutPackage, error) {
 
 	var manifestDescriptor *v1.Descriptor
 	for _, m := range index.Manifests {
-		if m.MediaType == "application/vnd.docker.distribution.manifest.v2+json" {
-			manifestDescriptor = &m // nolint:scopelint
-			break
+		if m.MediaType != "application/vnd.docker.distribution.manifest.v2+json" {
+			continue
+		}
+
+		if platform != "" && m.Platform != nil {
+			target := dist.Target{OS: m.Platform.OS, Arch: m.Platform.Architecture, Variant: m.Platform.Variant}
+			if target.String() != platform {
+				continue
+			}
 		}
+
+		manifestDescriptor = &m // nolint:scopelint
+		break
 	}
 
 	if manifestDescriptor == nil {
+		if platform != "" {
+			return nil, errors.Errorf("unable to find manifest for platform %s", style.Symbol(platform))
+		}
 		return nil, errors.New("unable to find manifest")
 	}
 
@@ -2,10 +2,13 @@ package buildpack_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/buildpacks/lifecycle/api"
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -57,6 +60,56 @@ func testOCILayoutPackage(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#BuildpacksFromOCILayoutBlobForPlatform", func() {
+		var packagePath string
+
+		it.Before(func() {
+			tmpDir, err := ioutil.TempDir("", "oci-layout-package")
+			h.AssertNil(t, err)
+			packagePath = filepath.Join(tmpDir, "package.cnb")
+
+			var packages []buildpack.PlatformPackage
+			for _, arch := range []string{"amd64", "arm64"} {
+				bp, err := fakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+					API:    api.MustParse("0.3"),
+					Info:   dist.BuildpackInfo{ID: "bp.one", Version: "1.2.3-" + arch},
+					Stacks: []dist.Stack{{ID: "some.stack.id"}},
+				}, 0644)
+				h.AssertNil(t, err)
+
+				builder := buildpack.NewBuilder(nil)
+				builder.SetBuildpack(bp)
+				packages = append(packages, buildpack.PlatformPackage{
+					Builder:  builder,
+					Platform: ggcrv1.Platform{OS: "linux", Architecture: arch},
+				})
+			}
+
+			h.AssertNil(t, buildpack.SavePlatformsAsFile(packagePath, packages))
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(filepath.Dir(packagePath)))
+		})
+
+		it("extracts the buildpacks of the platform", func() {
+			mainBP, _, err := buildpack.BuildpacksFromOCILayoutBlobForPlatform(blob.NewBlob(packagePath), "linux/arm64")
+			h.AssertNil(t, err)
+			h.AssertEq(t, mainBP.Descriptor().Info.Version, "1.2.3-arm64")
+		})
+
+		it("extracts the buildpacks of the first manifest without a platform", func() {
+			mainBP, _, err := buildpack.BuildpacksFromOCILayoutBlobForPlatform(blob.NewBlob(packagePath), "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, mainBP.Descriptor().Info.Version, "1.2.3-amd64")
+		})
+
+		it("fails when there is no manifest for the platform", func() {
+			_, _, err := buildpack.BuildpacksFromOCILayoutBlobForPlatform(blob.NewBlob(packagePath), "linux/s390x")
+			h.AssertError(t, err, "unable to find manifest for platform 'linux/s390x'")
+		})
+	})
+
 	when("#IsOCILayoutBlob", func() {
 		when("is an OCI layout blob", func() {
 			it("returns true", func() {
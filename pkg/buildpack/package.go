@@ -54,6 +54,7 @@ func ExtractBuildpacks(pkg Package) (mainBP Buildpack, depBPs []Buildpack, err e
 
 			diffID := bpInfo.LayerDiffID // Allow use in closure
 			b := &openerBlob{
+				diffID: diffID,
 				opener: func() (io.ReadCloser, error) {
 					rc, err := pkg.GetLayer(diffID)
 					if err != nil {
@@ -79,9 +80,24 @@ func ExtractBuildpacks(pkg Package) (mainBP Buildpack, depBPs []Buildpack, err e
 }
 
 type openerBlob struct {
+	diffID string
 	opener func() (io.ReadCloser, error)
 }
 
 func (b *openerBlob) Open() (io.ReadCloser, error) {
 	return b.opener()
 }
+
+// LayerDiffID returns the diffID of the layer holding a buildpack extracted from a package, which identifies its
+// contents without reading them. It returns false for buildpacks that weren't extracted from a package.
+func LayerDiffID(bp Buildpack) (string, bool) {
+	b, ok := bp.(*buildpack)
+	if !ok {
+		return "", false
+	}
+	blob, ok := b.Blob.(*openerBlob)
+	if !ok || blob.diffID == "" {
+		return "", false
+	}
+	return blob.diffID, true
+}
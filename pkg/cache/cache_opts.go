@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Format is the storage backing a cache.
+type Format int
+
+const (
+	CacheVolume Format = iota
+	CacheImage
+	CacheBind
+)
+
+func (f Format) String() string {
+	switch f {
+	case CacheImage:
+		return "image"
+	case CacheBind:
+		return "bind"
+	default:
+		return "volume"
+	}
+}
+
+// CacheInfo describes where a single cache is stored.
+type CacheInfo struct {
+	Format Format
+	// Source is the image name of an image cache, or the host directory of a bind cache.
+	// Volume caches are always named after the app image.
+	Source string
+}
+
+// CacheOpts configures the build and launch caches of a build.
+// It can be set from a flag value in the form 'type=build,format=bind,source=/path'.
+type CacheOpts struct {
+	Build  CacheInfo
+	Launch CacheInfo
+}
+
+func (c *CacheOpts) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return errors.Wrapf(err, "parsing cache %s", style.Symbol(value))
+	}
+
+	info := CacheInfo{}
+	cacheType := "build"
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("invalid field %s must be a key=value pair", style.Symbol(field))
+		}
+
+		key, val := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		switch key {
+		case "type":
+			cacheType = strings.ToLower(val)
+		case "format":
+			switch strings.ToLower(val) {
+			case "volume":
+				info.Format = CacheVolume
+			case "image":
+				info.Format = CacheImage
+			case "bind":
+				info.Format = CacheBind
+			default:
+				return errors.Errorf("invalid cache format %s, accepted values are volume, image and bind", style.Symbol(val))
+			}
+		case "source", "src", "name":
+			info.Source = val
+		default:
+			return errors.Errorf("unknown cache field %s", style.Symbol(key))
+		}
+	}
+
+	if err := info.sanitize(); err != nil {
+		return err
+	}
+
+	switch cacheType {
+	case "build":
+		c.Build = info
+	case "launch":
+		if info.Format == CacheImage {
+			return errors.New("image cache format is not supported for the launch cache")
+		}
+		c.Launch = info
+	default:
+		return errors.Errorf("invalid cache type %s, accepted values are build and launch", style.Symbol(cacheType))
+	}
+
+	return nil
+}
+
+func (c *CacheOpts) String() string {
+	var entries []string
+	for _, cache := range []struct {
+		cacheType string
+		info      CacheInfo
+	}{{"build", c.Build}, {"launch", c.Launch}} {
+		if cache.info == (CacheInfo{}) {
+			continue
+		}
+
+		entry := fmt.Sprintf("type=%s,format=%s", cache.cacheType, cache.info.Format)
+		if cache.info.Source != "" {
+			entry += fmt.Sprintf(",source=%s", cache.info.Source)
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ";")
+}
+
+func (c *CacheOpts) Type() string {
+	return "cache"
+}
+
+func (i *CacheInfo) sanitize() error {
+	switch i.Format {
+	case CacheVolume:
+		if i.Source != "" {
+			return errors.New("cache source is not supported for volume caches, volumes are named after the app image")
+		}
+	case CacheImage, CacheBind:
+		if i.Source == "" {
+			return errors.Errorf("cache source is required for %s caches", i.Format)
+		}
+	}
+
+	if i.Format == CacheBind {
+		abs, err := filepath.Abs(i.Source)
+		if err != nil {
+			return errors.Wrapf(err, "resolving cache source %s", style.Symbol(i.Source))
+		}
+		i.Source = abs
+	}
+
+	return nil
+}
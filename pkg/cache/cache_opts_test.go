@@ -0,0 +1,88 @@
+package cache_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheOpts(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "CacheOpts", testCacheOpts, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheOpts(t *testing.T, when spec.G, it spec.S) {
+	when("#Set", func() {
+		it("defaults to volume caches", func() {
+			var opts cache.CacheOpts
+			h.AssertEq(t, opts.Build.Format, cache.CacheVolume)
+			h.AssertEq(t, opts.Launch.Format, cache.CacheVolume)
+			h.AssertEq(t, opts.String(), "")
+		})
+
+		it("configures a bind build cache", func() {
+			var opts cache.CacheOpts
+			h.AssertNil(t, opts.Set("type=build,format=bind,source=/some/cache-dir"))
+
+			expected, err := filepath.Abs("/some/cache-dir")
+			h.AssertNil(t, err)
+			h.AssertEq(t, opts.Build, cache.CacheInfo{Format: cache.CacheBind, Source: expected})
+			h.AssertEq(t, opts.Launch, cache.CacheInfo{})
+		})
+
+		it("resolves relative bind sources", func() {
+			var opts cache.CacheOpts
+			h.AssertNil(t, opts.Set("format=bind,source=some-dir"))
+
+			expected, err := filepath.Abs("some-dir")
+			h.AssertNil(t, err)
+			h.AssertEq(t, opts.Build.Source, expected)
+		})
+
+		it("configures the launch cache", func() {
+			var opts cache.CacheOpts
+			h.AssertNil(t, opts.Set("type=launch,format=bind,src=/some/launch-dir"))
+
+			h.AssertEq(t, opts.Launch.Format, cache.CacheBind)
+			h.AssertEq(t, opts.Build, cache.CacheInfo{})
+		})
+
+		it("configures an image build cache", func() {
+			var opts cache.CacheOpts
+			h.AssertNil(t, opts.Set("type=build,format=image,name=some-registry.io/some/cache"))
+
+			h.AssertEq(t, opts.Build, cache.CacheInfo{Format: cache.CacheImage, Source: "some-registry.io/some/cache"})
+			h.AssertEq(t, opts.String(), "type=build,format=image,source=some-registry.io/some/cache")
+		})
+
+		when("the value is invalid", func() {
+			for _, tc := range []struct {
+				value string
+				err   string
+			}{
+				{"type=build,bind", "invalid field 'bind' must be a key=value pair"},
+				{"type=other", "invalid cache type 'other'"},
+				{"format=tarball", "invalid cache format 'tarball'"},
+				{"some-key=some-value", "unknown cache field 'some-key'"},
+				{"format=bind", "cache source is required for bind caches"},
+				{"format=image", "cache source is required for image caches"},
+				{"format=volume,source=some-volume", "cache source is not supported for volume caches"},
+				{"type=launch,format=image,source=some/image", "image cache format is not supported for the launch cache"},
+			} {
+				tc := tc
+				it("errors for "+tc.value, func() {
+					var opts cache.CacheOpts
+					h.AssertError(t, opts.Set(tc.value), tc.err)
+				})
+			}
+		})
+	})
+}
@@ -9,14 +9,16 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/imgutil/remote"
 	"github.com/buildpacks/lifecycle/platform"
-	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/volume/mounts"
+	"github.com/docker/go-units"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	ignore "github.com/sabhiram/go-gitignore"
@@ -24,6 +26,8 @@ import (
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	internalConfig "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/imageindex"
+	"github.com/buildpacks/pack/internal/layout"
 	pname "github.com/buildpacks/pack/internal/name"
 	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/stringset"
@@ -31,7 +35,10 @@ import (
 	"github.com/buildpacks/pack/internal/termui"
 	"github.com/buildpacks/pack/pkg/archive"
 	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
@@ -90,6 +97,7 @@ type BuildOptions struct {
 
 	// Address of docker daemon exposed to build container
 	// e.g. tcp://example.com:1234, unix:///run/user/1000/podman/podman.sock
+	// Defaults to the socket of the Podman service when the container engine is Podman.
 	DockerHost string
 
 	// Used to determine a run-image mirror if Run Image is empty.
@@ -109,12 +117,50 @@ type BuildOptions struct {
 	// Create an additional image that contains cache=true layers and push it to the registry.
 	CacheImage string
 
+	// Configure the storage of the build and launch caches, such as a host directory bind-mounted into the build containers.
+	// Defaults to volume caches named after Image.
+	Cache cache.CacheOpts
+
 	// Option passed directly to the lifecycle.
 	// If true, publishes Image directly to a registry.
 	// Assumes Image contains a valid registry with credentials
 	// provided by the docker client.
 	Publish bool
 
+	// Path to a private key, in the format used by cosign, to sign the published image with.
+	// Encrypted keys are decrypted with the password in the COSIGN_PASSWORD environment variable.
+	SigningKey string
+
+	// Path of a lock file recording the digests of the builder, run image, lifecycle image and buildpacks
+	// resolved by the build, such as 'project.lock' next to the project descriptor.
+	// The lock file is written after a successful build, unless Locked is true.
+	// Option not valid when building for several Platforms.
+	LockFile string
+
+	// Path of a file to write an in-toto statement to, holding the SLSA provenance of the app image: the digests of
+	// the builder and run image, the lifecycle version, the buildpacks that took part in the build, the source of the
+	// app and the options of the build. When Publish is true, the statement is also attached to the published image,
+	// at the tag 'sha256-<hex>.att' used by cosign for attestations.
+	// Option not valid when building for several Platforms.
+	ProvenanceFile string
+
+	// Locked, when true, fails the build before running the lifecycle if the images and buildpacks it resolves
+	// differ from those recorded in LockFile.
+	Locked bool
+
+	// Platforms to build the app image for, in the form 'os/arch[/variant]', such as 'linux/arm64'.
+	// Each platform is built by its own lifecycle execution, using the builder image for the platform.
+	// When several platforms are given, the platform specific images are published to tags of Image
+	// suffixed with the platform, such as 'latest-linux-arm64', and an image index referencing them
+	// is published to Image and AdditionalTags. Building for several platforms requires Publish.
+	// Defaults to the platform of the builder image available on the daemon.
+	Platforms []string
+
+	// Directory of an OCI image layout to write the app image to.
+	// The image is exported to the daemon and then saved to the layout, annotated with the tag of Image.
+	// Option not valid if Publish is true.
+	OCILayoutDir string
+
 	// Clear the build cache from previous builds.
 	ClearCache bool
 
@@ -170,6 +216,57 @@ type BuildOptions struct {
 
 	// Directory to output any SBOM artifacts
 	SBOMDestinationDir string
+
+	// EventHandler, when set, receives typed events describing the progress of the build,
+	// such as lifecycle phases starting and finishing, detected buildpacks and exported layers.
+	EventHandler events.Handler
+
+	// MeasureLayers, when true, records the size of the layers volume after each lifecycle phase
+	// in the stats of PhaseFinished events. Measuring requires querying the disk usage of the daemon,
+	// which can be slow on hosts with many volumes.
+	MeasureLayers bool
+
+	// KeepOnFailure, when true, keeps the container of a failed lifecycle phase and the volumes of the build,
+	// rather than removing them, to debug the failure with DebugBuild. It can't be used with secrets, which are copied
+	// into the containers of the detect and build phases.
+	KeepOnFailure bool
+
+	// Labels are set on the app image once it is exported. They replace the labels of the ProjectDescriptor, and
+	// the org.opencontainers.image.* labels derived from its project information.
+	// Setting labels saves the app image again, after the lifecycle exported it, so the digest of the built image isn't
+	// the one logged by the exporter. The image metadata, provenance and events report the digest of the labelled image.
+	Labels map[string]string
+
+	// CheckGitDirty records whether the working tree of the git repository of the app has uncommitted changes, in the
+	// git source of the project metadata. Checking it reads every file of the working tree.
+	CheckGitDirty bool
+
+	// Secrets are files made available to the detect and build phases, in addition to the secrets of the
+	// ProjectDescriptor. A secret with the ID of a ProjectDescriptor secret replaces it.
+	Secrets []Secret
+
+	// MetadataFile is the path to write the metadata of the app image to, as JSON. See ImageMetadata.
+	MetadataFile string
+
+	// Timeout is the longest time the build may run for. Zero means no timeout.
+	// A build that times out returns a TimeoutError.
+	Timeout time.Duration
+
+	// PhaseTimeouts are the longest times lifecycle phases may run for, keyed by phase: analyze, detect, restore,
+	// build, export or create. A phase that times out returns a TimeoutError.
+	// The create phase only runs with a trusted builder. Timeouts of other phases run them in separate containers,
+	// even with a trusted builder.
+	PhaseTimeouts map[string]time.Duration
+}
+
+// Secret is a file copied to /run/secrets/<ID> in the detect and build phases, readable only by the build user of the
+// builder. Secrets are never written to the builder image or the app image, and are not available to the exporter.
+type Secret struct {
+	// ID names the secret, and the file it is copied to.
+	ID string
+
+	// Source is the path to the file holding the secret.
+	Source string
 }
 
 // ProxyConfig specifies proxy setting to be set as environment variables in a container.
@@ -199,6 +296,18 @@ type ContainerConfig struct {
 	// - /layers
 	// - anything below /cnb/**
 	Volumes []string
+
+	// Memory limits the memory of each build container, in bytes. Zero means no limit.
+	Memory int64
+
+	// CPUs limits the number of CPUs each build container can use, such as 1.5. Zero means no limit.
+	CPUs float64
+
+	// PidsLimit limits the number of processes of each build container. Zero means no limit.
+	PidsLimit int64
+
+	// Ulimits of each build container, in the form <type>=<soft limit>[:<hard limit>], such as nofile=1024:2048.
+	Ulimits []string
 }
 
 var IsSuggestedBuilderFunc = func(b string) bool {
@@ -215,11 +324,91 @@ var IsSuggestedBuilderFunc = func(b string) bool {
 // If any configuration is deemed invalid, or if any lifecycle phases fail,
 // an error will be returned and no image produced.
 func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
+	return c.buildImage(ctx, opts, nil)
+}
+
+// buildImage builds the app image of opts. When metadataHandler is set, the metadata of the app image is passed to it
+// once exported.
+func (c *Client) buildImage(ctx context.Context, opts BuildOptions, metadataHandler func(ImageMetadata)) error {
+	signingKey, err := loadSigningKey(opts.SigningKey, opts.Publish)
+	if err != nil {
+		return err
+	}
+
+	if opts.Locked && opts.LockFile == "" {
+		return errors.New("a locked build requires a lock file")
+	}
+
+	if err := validatePhaseTimeouts(opts.PhaseTimeouts); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	// the digest of the published image, which is signed
+	var digest string
+	if signingKey != nil {
+		handler := metadataHandler
+		metadataHandler = func(metadata ImageMetadata) {
+			digest = metadata.Digest
+			if handler != nil {
+				handler(metadata)
+			}
+		}
+	}
+
+	if len(opts.Platforms) > 1 {
+		if opts.LockFile != "" {
+			return errors.New("a lock file cannot be used when building for multiple platforms")
+		}
+		if opts.ProvenanceFile != "" {
+			return errors.New("a provenance file cannot be used when building for multiple platforms")
+		}
+		if opts.MetadataFile != "" {
+			return errors.New("a metadata file cannot be used when building for multiple platforms")
+		}
+
+		digest, err = c.buildPlatforms(ctx, opts)
+	} else {
+		err = c.build(ctx, opts, nil, metadataHandler)
+	}
+
+	if err != nil {
+		return asTimeoutError(ctx, opts.Timeout, err)
+	}
+
+	if signingKey == nil {
+		return nil
+	}
+
+	return c.signImages(signingKey, digest, append([]string{opts.Image}, opts.AdditionalTags...)...)
+}
+
+// build builds the app image for a single platform. When detectHandler is set, only the phases required to detect the
+// buildpacks of the app are run, and their result is passed to detectHandler. When metadataHandler is set, the
+// metadata of the exported app image is passed to it.
+func (c *Client) build(ctx context.Context, opts BuildOptions, detectHandler func(build.DetectResult), metadataHandler func(ImageMetadata)) error {
+	startedOn := time.Now()
+
 	imageRef, err := c.parseTagReference(opts.Image)
 	if err != nil {
 		return errors.Wrapf(err, "invalid image name '%s'", opts.Image)
 	}
 
+	var targetPlatform string
+	if len(opts.Platforms) == 1 {
+		platform, err := imageindex.ParsePlatform(opts.Platforms[0])
+		if err != nil {
+			return err
+		}
+		targetPlatform = imageindex.PlatformString(platform)
+	}
+
+	if opts.Publish && opts.OCILayoutDir != "" {
+		return errors.New("an OCI layout output cannot be used when publishing")
+	}
+
 	appPath, err := c.processAppPath(opts.AppPath)
 	if err != nil {
 		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
@@ -232,18 +421,24 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), image.FetchOptions{Daemon: true, PullPolicy: opts.PullPolicy})
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), image.FetchOptions{Daemon: true, PullPolicy: opts.PullPolicy, Platform: targetPlatform})
 	if err != nil {
 		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
 	}
 
+	if targetPlatform != "" {
+		if err := validateBuilderPlatform(rawBuilderImage, targetPlatform); err != nil {
+			return errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
+		}
+	}
+
 	bldr, err := c.getBuilder(rawBuilderImage)
 	if err != nil {
 		return errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
 	}
 
 	runImageName := c.resolveRunImage(opts.RunImage, imageRef.Context().RegistryStr(), builderRef.Context().RegistryStr(), bldr.Stack(), opts.AdditionalMirrors, opts.Publish)
-	runImage, err := c.validateRunImage(ctx, runImageName, opts.PullPolicy, opts.Publish, bldr.StackID)
+	runImage, err := c.validateRunImage(ctx, runImageName, opts.PullPolicy, opts.Publish, bldr.StackID, targetPlatform)
 	if err != nil {
 		return errors.Wrapf(err, "invalid run-image '%s'", runImageName)
 	}
@@ -253,11 +448,22 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 
-	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Image(), bldr.Buildpacks(), bldr.Order(), bldr.StackID, opts)
+	fetchedBPs, order, lockedBPs, err := c.processBuildpacks(ctx, bldr.Image(), bldr.Buildpacks(), bldr.Order(), bldr.StackID, opts)
 	if err != nil {
 		return err
 	}
 
+	var lock projectTypes.Lock
+	if opts.LockFile != "" {
+		if lock.Builder, err = lockedImage(builderRef.Name(), rawBuilderImage); err != nil {
+			return err
+		}
+		if lock.RunImage, err = lockedImage(runImageName, runImage); err != nil {
+			return err
+		}
+		lock.Buildpacks = lockedBPs
+	}
+
 	if err := c.validateMixins(fetchedBPs, bldr, runImageName, runMixins); err != nil {
 		return errors.Wrap(err, "validating stack mixins")
 	}
@@ -271,11 +477,11 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		buildEnvs[k] = v
 	}
 
-	ephemeralBuilder, err := c.createEphemeralBuilder(rawBuilderImage, buildEnvs, order, fetchedBPs)
+	ephemeralBuilder, removeEphemeralBuilder, err := c.ephemeralBuilder(ctx, rawBuilderImage, targetPlatform, buildEnvs, order, fetchedBPs)
 	if err != nil {
 		return err
 	}
-	defer c.docker.ImageRemove(context.Background(), ephemeralBuilder.Name(), types.ImageRemoveOptions{Force: true})
+	defer removeEphemeralBuilder()
 
 	var builderPlatformAPIs builder.APISet
 	builderPlatformAPIs = append(builderPlatformAPIs, ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Deprecated...)
@@ -301,12 +507,15 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		c.logger.Warn(warning)
 	}
 
-	fileFilter, err := getFileFilter(opts.ProjectDescriptor)
+	secrets, err := processSecrets(imgOS, opts)
 	if err != nil {
 		return err
 	}
+	if len(secrets) > 0 && opts.KeepOnFailure {
+		return errors.New("failed containers can't be kept when building with secrets, as the secrets are copied into them")
+	}
 
-	runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
+	resources, err := processResources(opts.ContainerConfig)
 	if err != nil {
 		return err
 	}
@@ -323,12 +532,53 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 			}
 		}
 	}
+	if source, err := gitMetadata(appPath, opts.CheckGitDirty); err == nil {
+		projectMetadata.Source = withGitSource(projectMetadata.Source, source)
+	} else {
+		c.logger.Debugf("Not recording git provenance: %s", err)
+	}
+
+	labels, err := imageLabels(opts, projectMetadata.Source)
+	if err != nil {
+		return err
+	}
+
+	fileFilter, err := getFileFilter(opts.ProjectDescriptor)
+	if err != nil {
+		return err
+	}
+
+	runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
+	if err != nil {
+		return err
+	}
+
+	var prov *buildProvenance
+	if opts.ProvenanceFile != "" && detectHandler == nil {
+		prov = &buildProvenance{
+			startedOn:        startedOn,
+			lifecycleVersion: ephemeralBuilder.LifecycleDescriptor().Info.Version.String(),
+			source:           projectMetadata.Source,
+		}
+		if prov.builder, err = lockedImage(builderRef.Name(), rawBuilderImage); err != nil {
+			return err
+		}
+		if prov.runImage, err = lockedImage(runImageName, runImage); err != nil {
+			return err
+		}
+	}
 
 	// Default mode: if the TrustBuilder option is not set, trust the suggested builders.
 	if opts.TrustBuilder == nil {
 		opts.TrustBuilder = IsSuggestedBuilderFunc
 	}
 
+	dockerHost := opts.DockerHost
+	// the lifecycle reaches the daemon at the default docker socket, give it the socket pack talks to podman through
+	if podman, ok := c.engine.(*engine.Podman); ok && dockerHost == "" {
+		dockerHost = podman.DaemonHost()
+	}
+
 	lifecycleOpts := build.LifecycleOptions{
 		AppPath:            appPath,
 		Image:              imageRef,
@@ -340,8 +590,9 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		Publish:            opts.Publish,
 		TrustBuilder:       opts.TrustBuilder(opts.Builder),
 		UseCreator:         false,
-		DockerHost:         opts.DockerHost,
+		DockerHost:         dockerHost,
 		CacheImage:         opts.CacheImage,
+		Cache:              opts.Cache,
 		HTTPProxy:          proxyConfig.HTTPProxy,
 		HTTPSProxy:         proxyConfig.HTTPSProxy,
 		NoProxy:            proxyConfig.NoProxy,
@@ -356,21 +607,37 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		Interactive:        opts.Interactive,
 		Termui:             termui.NewTermui(imageRef.Name(), ephemeralBuilder, runImageName),
 		SBOMDestinationDir: opts.SBOMDestinationDir,
+		EventHandler:       opts.EventHandler,
+		MeasureLayers:      opts.MeasureLayers,
+		DetectOnly:         detectHandler != nil,
+		DetectHandler:      detectHandler,
+		KeepOnFailure:      opts.KeepOnFailure,
+		Secrets:            secrets,
+		Resources:          resources,
+		PhaseTimeouts:      opts.PhaseTimeouts,
 	}
 
+	c.recordCacheUsage(imageRef, opts)
+
 	lifecycleVersion := ephemeralBuilder.LifecycleDescriptor().Info.Version
 	// Technically the creator is supported as of platform API version 0.3 (lifecycle version 0.7.0+) but earlier versions
 	// have bugs that make using the creator problematic.
 	lifecycleSupportsCreator := !lifecycleVersion.LessThan(semver.MustParse(minLifecycleVersionSupportingCreator))
 
-	if lifecycleSupportsCreator && opts.TrustBuilder(opts.Builder) {
+	// The creator runs the exporter in the same container as the detect and build phases, which would expose secrets to it.
+	// It runs all phases in that container as well, so only applies a timeout of the create phase.
+	if lifecycleSupportsCreator && opts.TrustBuilder(opts.Builder) && detectHandler == nil && len(secrets) == 0 && !hasSeparatePhaseTimeouts(opts.PhaseTimeouts) {
 		lifecycleOpts.UseCreator = true
 		// no need to fetch a lifecycle image, it won't be used
+		if err := c.verifyLock(opts, lock); err != nil {
+			return err
+		}
+
 		if err := c.lifecycleExecutor.Execute(ctx, lifecycleOpts); err != nil {
 			return errors.Wrap(err, "executing lifecycle")
 		}
 
-		return c.logImageNameAndSha(ctx, opts.Publish, imageRef)
+		return c.processExportedImage(ctx, opts, imageRef, labels, lock, prov, metadataHandler)
 	}
 
 	if !opts.TrustBuilder(opts.Builder) {
@@ -395,16 +662,97 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 			}
 
 			lifecycleOpts.LifecycleImage = lifecycleImage.Name()
+
+			if opts.LockFile != "" {
+				lockedLifecycleImage, err := lockedImage(lifecycleImageName, lifecycleImage)
+				if err != nil {
+					return err
+				}
+				lock.LifecycleImage = &lockedLifecycleImage
+			}
 		} else {
 			return errors.Errorf("Lifecycle %s does not have an associated lifecycle image. Builder must be trusted.", lifecycleVersion.String())
 		}
 	}
 
+	if err := c.verifyLock(opts, lock); err != nil {
+		return err
+	}
+
 	if err := c.lifecycleExecutor.Execute(ctx, lifecycleOpts); err != nil {
 		return errors.Wrap(err, "executing lifecycle. This may be the result of using an untrusted builder")
 	}
 
-	return c.logImageNameAndSha(ctx, opts.Publish, imageRef)
+	if detectHandler != nil {
+		return nil
+	}
+
+	return c.processExportedImage(ctx, opts, imageRef, labels, lock, prov, metadataHandler)
+}
+
+// processExportedImage runs the steps that follow a successful export of the app image.
+func (c *Client) processExportedImage(ctx context.Context, opts BuildOptions, imageRef name.Reference, labels map[string]string, lock projectTypes.Lock, prov *buildProvenance, metadataHandler func(ImageMetadata)) error {
+	// the exported image is fetched once, for the steps below reading it
+	needsImage := len(labels) > 0 || writesProvenance(opts, prov) || opts.MetadataFile != "" || metadataHandler != nil || logging.IsQuiet(c.logger)
+	var img imgutil.Image
+	if needsImage || opts.EventHandler != nil {
+		var err error
+		img, err = c.imageFetcher.Fetch(ctx, imageRef.Name(), image.FetchOptions{Daemon: !opts.Publish, PullPolicy: image.PullNever, SkipVerification: true})
+		if err != nil {
+			err = errors.Wrap(err, "fetching built image")
+			if needsImage {
+				return err
+			}
+			// only the event handler reads it, see below
+			c.logger.Warnf("Not emitting export events: %s", err)
+		}
+	}
+
+	if err := c.setImageLabels(opts, img, labels); err != nil {
+		return err
+	}
+
+	if err := c.writeProvenance(opts, imageRef, img, prov); err != nil {
+		return err
+	}
+
+	if img != nil {
+		// the image is already exported, failing to describe it to the event handler doesn't fail the build
+		if err := c.emitExportEvents(opts.EventHandler, imageRef, img); err != nil {
+			c.logger.Warnf("Not emitting export events: %s", err)
+		}
+	}
+
+	if opts.OCILayoutDir != "" {
+		digest, err := layout.WriteDaemonImage(ctx, c.engine.APIClient(), imageRef, opts.OCILayoutDir)
+		if err != nil {
+			return errors.Wrap(err, "writing OCI layout")
+		}
+		c.logger.Infof("Wrote image %s with digest %s to OCI layout %s", style.Symbol(imageRef.Name()), style.Symbol(digest.String()), style.Symbol(opts.OCILayoutDir))
+	}
+
+	if err := c.writeLock(opts, lock); err != nil {
+		return err
+	}
+
+	if opts.MetadataFile != "" || metadataHandler != nil {
+		metadata, err := imageMetadata(imageRef.Name(), img, opts.AdditionalTags, opts.SBOMDestinationDir)
+		if err != nil {
+			return err
+		}
+
+		if opts.MetadataFile != "" {
+			if err := c.writeMetadataFile(opts.MetadataFile, metadata); err != nil {
+				return err
+			}
+		}
+
+		if metadataHandler != nil {
+			metadataHandler(metadata)
+		}
+	}
+
+	return c.logImageNameAndSha(imageRef, img)
 }
 
 func getFileFilter(descriptor projectTypes.Descriptor) (func(string) bool, error) {
@@ -471,11 +819,11 @@ func (c *Client) getBuilder(img imgutil.Image) (*builder.Builder, error) {
 	return bldr, nil
 }
 
-func (c *Client) validateRunImage(context context.Context, name string, pullPolicy image.PullPolicy, publish bool, expectedStack string) (imgutil.Image, error) {
+func (c *Client) validateRunImage(context context.Context, name string, pullPolicy image.PullPolicy, publish bool, expectedStack string, platform string) (imgutil.Image, error) {
 	if name == "" {
 		return nil, errors.New("run image must be specified")
 	}
-	img, err := c.imageFetcher.Fetch(context, name, image.FetchOptions{Daemon: !publish, PullPolicy: pullPolicy})
+	img, err := c.imageFetcher.Fetch(context, name, image.FetchOptions{Daemon: !publish, PullPolicy: pullPolicy, Platform: platform})
 	if err != nil {
 		return nil, err
 	}
@@ -679,12 +1027,13 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 // 	----------
 // 	- group:
 //		- A
-func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Image, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, stackID string, opts BuildOptions) (fetchedBPs []buildpack.Buildpack, order dist.Order, err error) {
+func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Image, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, stackID string, opts BuildOptions) (fetchedBPs []buildpack.Buildpack, order dist.Order, lockedBPs []projectTypes.LockedBuildpack, err error) {
 	pullPolicy := opts.PullPolicy
 	publish := opts.Publish
 	registry := opts.Registry
 	relativeBaseDir := opts.RelativeBaseDir
 	declaredBPs := opts.Buildpacks
+	inlineBPs := map[string]bool{}
 
 	// declare buildpacks provided by project descriptor when no buildpacks are declared
 	if len(declaredBPs) == 0 && len(opts.ProjectDescriptor.Build.Buildpacks) != 0 {
@@ -694,39 +1043,68 @@ func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Ima
 			switch {
 			case bp.ID != "" && bp.Script.Inline != "" && bp.URI == "":
 				if bp.Script.API == "" {
-					return nil, nil, errors.New("Missing API version for inline buildpack")
+					return nil, nil, nil, errors.New("Missing API version for inline buildpack")
 				}
 
 				pathToInlineBuildpack, err := createInlineBuildpack(bp, stackID)
 				if err != nil {
-					return nil, nil, errors.Wrap(err, "Could not create temporary inline buildpack")
+					return nil, nil, nil, errors.Wrap(err, "Could not create temporary inline buildpack")
 				}
 				declaredBPs = append(declaredBPs, pathToInlineBuildpack)
+				inlineBPs[pathToInlineBuildpack] = true
 			case bp.URI != "":
 				declaredBPs = append(declaredBPs, bp.URI)
 			case bp.ID != "" && bp.Version != "":
 				declaredBPs = append(declaredBPs, fmt.Sprintf("%s@%s", bp.ID, bp.Version))
 			default:
-				return nil, nil, errors.New("Invalid buildpack defined in project descriptor")
+				return nil, nil, nil, errors.New("Invalid buildpack defined in project descriptor")
 			}
 		}
 	}
 
-	order = dist.Order{{Group: []dist.BuildpackRef{}}}
-	for _, bp := range declaredBPs {
-		locatorType, err := buildpack.GetLocatorType(bp, relativeBaseDir, builderBPs)
+	locatorTypes := make([]buildpack.LocatorType, len(declaredBPs))
+	var downloads []buildpackDownload
+	for i, bp := range declaredBPs {
+		locatorTypes[i], err = buildpack.GetLocatorType(bp, relativeBaseDir, builderBPs)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
-		switch locatorType {
+		if locatorTypes[i] == buildpack.FromBuilderLocator || locatorTypes[i] == buildpack.IDLocator {
+			continue
+		}
+
+		imageOS, err := builderImage.OS()
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "getting OS from %s", style.Symbol(builderImage.Name()))
+		}
+		downloads = append(downloads, buildpackDownload{
+			uri: bp,
+			options: buildpack.DownloadOptions{
+				RegistryName:    registry,
+				ImageOS:         imageOS,
+				RelativeBaseDir: relativeBaseDir,
+				Daemon:          !publish,
+				PullPolicy:      pullPolicy,
+			},
+		})
+	}
+
+	downloaded, err := c.downloadBuildpacks(ctx, downloads)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "downloading buildpack")
+	}
+
+	order = dist.Order{{Group: []dist.BuildpackRef{}}}
+	for i, bp := range declaredBPs {
+		switch locatorTypes[i] {
 		case buildpack.FromBuilderLocator:
 			switch {
 			case len(order) == 0 || len(order[0].Group) == 0:
 				order = builderOrder
 			case len(order) > 1:
 				// This should only ever be possible if they are using from=builder twice which we don't allow
-				return nil, nil, errors.New("buildpacks from builder can only be defined once")
+				return nil, nil, nil, errors.New("buildpacks from builder can only be defined once")
 			default:
 				newOrder := dist.Order{}
 				groupToAdd := order[0].Group
@@ -744,26 +1122,28 @@ func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Ima
 				Version: version,
 			})
 		default:
-			imageOS, err := builderImage.OS()
-			if err != nil {
-				return fetchedBPs, order, errors.Wrapf(err, "getting OS from %s", style.Symbol(builderImage.Name()))
-			}
-			mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, bp, buildpack.DownloadOptions{
-				RegistryName:    registry,
-				ImageOS:         imageOS,
-				RelativeBaseDir: relativeBaseDir,
-				Daemon:          !publish,
-				PullPolicy:      pullPolicy,
-			})
-			if err != nil {
-				return fetchedBPs, order, errors.Wrap(err, "downloading buildpack")
-			}
+			mainBP, depBPs := downloaded[0].mainBP, downloaded[0].depBPs
+			downloaded = downloaded[1:]
+
 			fetchedBPs = append(append(fetchedBPs, mainBP), depBPs...)
+			if opts.LockFile != "" {
+				// inline buildpacks are locked by the project descriptor, not by their temporary location
+				uri := bp
+				if inlineBPs[bp] {
+					uri = ""
+				}
+
+				locked, err := lockedBuildpacks(uri, mainBP, depBPs)
+				if err != nil {
+					return fetchedBPs, order, lockedBPs, err
+				}
+				lockedBPs = append(lockedBPs, locked...)
+			}
 			order = appendBuildpackToOrder(order, mainBP.Descriptor().Info)
 		}
 	}
 
-	return fetchedBPs, order, nil
+	return fetchedBPs, order, lockedBPs, nil
 }
 
 func appendBuildpackToOrder(order dist.Order, bpInfo dist.BuildpackInfo) (newOrder dist.Order) {
@@ -779,9 +1159,9 @@ func appendBuildpackToOrder(order dist.Order, bpInfo dist.BuildpackInfo) (newOrd
 	return newOrder
 }
 
-func (c *Client) createEphemeralBuilder(rawBuilderImage imgutil.Image, env map[string]string, order dist.Order, buildpacks []buildpack.Buildpack) (*builder.Builder, error) {
+func (c *Client) createEphemeralBuilder(rawBuilderImage imgutil.Image, name string, env map[string]string, order dist.Order, buildpacks []buildpack.Buildpack) (*builder.Builder, error) {
 	origBuilderName := rawBuilderImage.Name()
-	bldr, err := builder.New(rawBuilderImage, fmt.Sprintf("pack.local/builder/%x:latest", randString(10)))
+	bldr, err := builder.New(rawBuilderImage, name)
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid builder %s", style.Symbol(origBuilderName))
 	}
@@ -843,6 +1223,90 @@ func processVolumes(imgOS string, volumes []string) (processed []string, warning
 	return processed, warnings, nil
 }
 
+func processSecrets(imgOS string, opts BuildOptions) ([]build.Secret, error) {
+	var (
+		ids     []string
+		sources = map[string]string{}
+	)
+	addSecret := func(id, source, baseDir string) error {
+		if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+			return errors.Errorf("secret id %s is invalid", style.Symbol(id))
+		}
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(baseDir, source)
+		}
+		source, err := filepath.Abs(source)
+		if err != nil {
+			return err
+		}
+		if _, ok := sources[id]; !ok {
+			ids = append(ids, id)
+		}
+		sources[id] = source
+		return nil
+	}
+
+	for _, secret := range opts.ProjectDescriptor.Build.Secrets {
+		if err := addSecret(secret.ID, secret.Source, opts.ProjectDescriptorBaseDir); err != nil {
+			return nil, err
+		}
+	}
+	for _, secret := range opts.Secrets {
+		if err := addSecret(secret.ID, secret.Source, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if imgOS == "windows" {
+		return nil, errors.New("secrets are not supported for Windows builders")
+	}
+
+	var secrets []build.Secret
+	for _, id := range ids {
+		info, err := os.Stat(sources[id])
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading secret %s", style.Symbol(id))
+		}
+		if !info.Mode().IsRegular() {
+			return nil, errors.Errorf("secret %s must be a file", style.Symbol(id))
+		}
+		secrets = append(secrets, build.Secret{ID: id, Source: sources[id]})
+	}
+	return secrets, nil
+}
+
+func processResources(config ContainerConfig) (dcontainer.Resources, error) {
+	if config.Memory < 0 {
+		return dcontainer.Resources{}, errors.New("memory limit must not be negative")
+	}
+	if config.CPUs < 0 {
+		return dcontainer.Resources{}, errors.New("cpus limit must not be negative")
+	}
+	if config.PidsLimit < 0 {
+		return dcontainer.Resources{}, errors.New("pids limit must not be negative")
+	}
+
+	resources := dcontainer.Resources{
+		Memory:   config.Memory,
+		NanoCPUs: int64(config.CPUs * 1e9),
+	}
+	if config.PidsLimit > 0 {
+		pidsLimit := config.PidsLimit
+		resources.PidsLimit = &pidsLimit
+	}
+	for _, u := range config.Ulimits {
+		ulimit, err := units.ParseUlimit(u)
+		if err != nil {
+			return dcontainer.Resources{}, errors.Wrapf(err, "invalid ulimit %s", style.Symbol(u))
+		}
+		resources.Ulimits = append(resources.Ulimits, ulimit)
+	}
+	return resources, nil
+}
+
 func processMode(mode string) string {
 	if mode == "" {
 		return "ro"
@@ -851,17 +1315,12 @@ func processMode(mode string) string {
 	return mode
 }
 
-func (c *Client) logImageNameAndSha(ctx context.Context, publish bool, imageRef name.Reference) error {
+func (c *Client) logImageNameAndSha(imageRef name.Reference, img imgutil.Image) error {
 	// The image name and sha are printed in the lifecycle logs, and there is no need to print it again, unless output is suppressed.
 	if !logging.IsQuiet(c.logger) {
 		return nil
 	}
 
-	img, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), image.FetchOptions{Daemon: !publish, PullPolicy: image.PullNever})
-	if err != nil {
-		return errors.Wrap(err, "fetching built image")
-	}
-
 	id, err := img.Identifier()
 	if err != nil {
 		return errors.Wrap(err, "reading image sha")
@@ -876,6 +1335,58 @@ func (c *Client) logImageNameAndSha(ctx context.Context, publish bool, imageRef
 	return err
 }
 
+// emitExportEvents emits an event for each layer recorded on the built image, followed by an event for the image itself.
+func (c *Client) emitExportEvents(handler events.Handler, imageRef name.Reference, img imgutil.Image) error {
+	if handler == nil {
+		return nil
+	}
+
+	var layersMd platform.LayersMetadata
+	if _, err := dist.GetLabel(img, platform.LayerMetadataLabel, &layersMd); err != nil {
+		return err
+	}
+
+	for _, bp := range layersMd.Buildpacks {
+		var layerNames []string
+		for layerName := range bp.Layers {
+			layerNames = append(layerNames, layerName)
+		}
+		sort.Strings(layerNames)
+
+		for _, layerName := range layerNames {
+			handler(events.Event{
+				Type:      events.LayerExported,
+				Time:      time.Now(),
+				Buildpack: &dist.BuildpackInfo{ID: bp.ID, Version: bp.Version},
+				Layer:     layerName,
+				Digest:    bp.Layers[layerName].SHA,
+			})
+		}
+	}
+
+	for _, layer := range layersMd.App {
+		handler(events.Event{
+			Type:   events.LayerExported,
+			Time:   time.Now(),
+			Layer:  "app",
+			Digest: layer.SHA,
+		})
+	}
+
+	id, err := img.Identifier()
+	if err != nil {
+		return errors.Wrap(err, "reading image sha")
+	}
+
+	handler(events.Event{
+		Type:   events.ImageExported,
+		Time:   time.Now(),
+		Image:  imageRef.Name(),
+		Digest: parseDigestFromImageID(id),
+	})
+	return nil
+}
+
 func parseDigestFromImageID(id imgutil.Identifier) string {
 	var digest string
 	switch v := id.(type) {
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+// BuildAll builds each app of opts.ProjectDescriptor to its own image, in the order they are declared.
+// The options apply to every app, except for the path, image, include and exclude rules, environment variables and
+// buildpacks declared by each app, which replace those of the build. App paths are relative to
+// opts.ProjectDescriptorBaseDir, or else to opts.AppPath.
+// Images and buildpacks are fetched once, and apps with the same buildpacks and environment variables share an
+// ephemeral builder. The first app to fail stops the build.
+func (c *Client) BuildAll(ctx context.Context, opts BuildOptions) error {
+	apps := opts.ProjectDescriptor.Apps
+	if len(apps) == 0 {
+		return errors.New("the project descriptor has no apps to build")
+	}
+
+	switch {
+	case opts.Image != "":
+		return errors.New("an image cannot be set when building all apps")
+	case len(opts.AdditionalTags) > 0:
+		return errors.New("additional tags cannot be set when building all apps")
+	case len(opts.Platforms) > 1:
+		return errors.New("all apps cannot be built for multiple platforms")
+	case opts.LockFile != "":
+		return errors.New("a lock file cannot be used when building all apps")
+	case opts.ProvenanceFile != "":
+		return errors.New("a provenance file cannot be used when building all apps")
+	case opts.MetadataFile != "":
+		return errors.New("a metadata file cannot be used when building all apps")
+	}
+
+	session := newBuildSession()
+	defer session.cleanup()
+	sessionClient := c.withSession(session)
+
+	for i, app := range apps {
+		c.logger.Infof("Building app %s (%d/%d)", style.Symbol(appName(app)), i+1, len(apps))
+
+		if err := sessionClient.Build(ctx, appBuildOptions(opts, app)); err != nil {
+			return errors.Wrapf(err, "building app %s", style.Symbol(appName(app)))
+		}
+	}
+
+	return nil
+}
+
+// appBuildOptions returns the options to build app with, from the options of the build of all apps.
+func appBuildOptions(opts BuildOptions, app projectTypes.App) BuildOptions {
+	baseDir := opts.ProjectDescriptorBaseDir
+	if baseDir == "" {
+		baseDir = opts.AppPath
+	}
+
+	appOpts := opts
+	appOpts.Image = app.Image
+	appOpts.AppPath = filepath.Join(baseDir, app.Path)
+
+	descriptor := opts.ProjectDescriptor
+	descriptor.Apps = nil
+	if app.Include != nil || app.Exclude != nil {
+		descriptor.Build.Include = app.Include
+		descriptor.Build.Exclude = app.Exclude
+	}
+	if len(app.Buildpacks) > 0 {
+		descriptor.Build.Buildpacks = app.Buildpacks
+	}
+	descriptor.Build.Env = append(append([]projectTypes.EnvVar{}, descriptor.Build.Env...), app.Env...)
+	appOpts.ProjectDescriptor = descriptor
+
+	return appOpts
+}
+
+func appName(app projectTypes.App) string {
+	if app.Name != "" {
+		return app.Name
+	}
+	return app.Path
+}
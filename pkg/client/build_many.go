@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// BuildManyOptions configures the builds run by BuildMany.
+type BuildManyOptions struct {
+	// Builds holds the options of each image to build. Each build must have its own Image.
+	Builds []BuildOptions
+
+	// Parallel is the maximum number of builds to run at the same time. Defaults to 1.
+	Parallel int
+}
+
+// BuildMany builds several images, running up to opts.Parallel builds at the same time.
+// Images are pulled and buildpacks downloaded once for all builds, and builds with the same builder, buildpacks and
+// environment variables share an ephemeral builder. The output of builds running at the same time is interleaved.
+// A failed build doesn't stop the others; the builds that failed are reported by a BuildManyError.
+func (c *Client) BuildMany(ctx context.Context, opts BuildManyOptions) error {
+	if len(opts.Builds) == 0 {
+		return errors.New("no builds to run")
+	}
+
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	images := map[string]bool{}
+	for _, buildOpts := range opts.Builds {
+		if buildOpts.Image == "" {
+			return errors.New("each build must have an image")
+		}
+		if images[buildOpts.Image] {
+			return errors.Errorf("image %s is built more than once", style.Symbol(buildOpts.Image))
+		}
+		images[buildOpts.Image] = true
+
+		if parallel > 1 && buildOpts.Interactive {
+			return errors.New("interactive builds cannot be run in parallel")
+		}
+	}
+
+	session := newBuildSession()
+	defer session.cleanup()
+	sessionClient := c.withSession(session)
+
+	var (
+		wg        sync.WaitGroup
+		slots     = make(chan struct{}, parallel)
+		buildErrs = make([]error, len(opts.Builds))
+	)
+	for i, buildOpts := range opts.Builds {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			buildErrs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, buildOpts BuildOptions) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			c.logger.Infof("Building image %s (%d/%d)", style.Symbol(buildOpts.Image), i+1, len(opts.Builds))
+			buildErrs[i] = sessionClient.Build(ctx, buildOpts)
+		}(i, buildOpts)
+	}
+	wg.Wait()
+
+	var failures []BuildFailure
+	for i, err := range buildErrs {
+		if err != nil {
+			failures = append(failures, BuildFailure{Image: opts.Builds[i].Image, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return BuildManyError{Failures: failures, Total: len(opts.Builds)}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/imageindex"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+// buildPlatforms builds the app image for each of the platforms and publishes an image index referencing the
+// platform specific images. The digest of the index is returned. See BuildOptions.Platforms.
+func (c *Client) buildPlatforms(ctx context.Context, opts BuildOptions) (string, error) {
+	if !opts.Publish {
+		return "", errors.New("building for multiple platforms requires publishing the image")
+	}
+
+	imageRef, err := c.parseTagReference(opts.Image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image name '%s'", opts.Image)
+	}
+	imageTag := imageRef.(name.Tag)
+
+	tags := []name.Reference{imageTag}
+	for _, additionalTag := range opts.AdditionalTags {
+		ref, err := c.parseTagReference(additionalTag)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid additional tag '%s'", additionalTag)
+		}
+		tags = append(tags, ref)
+	}
+
+	platforms, err := parsePlatforms(opts.Platforms)
+	if err != nil {
+		return "", err
+	}
+
+	var manifests []imageindex.Manifest
+	for _, platform := range platforms {
+		platformOpts, err := platformBuildOptions(opts, imageTag, platform)
+		if err != nil {
+			return "", err
+		}
+
+		c.logger.Infof("Building image %s for platform %s", style.Symbol(platformOpts.Image), style.Symbol(imageindex.PlatformString(platform)))
+		if err := c.build(ctx, platformOpts, nil, nil); err != nil {
+			return "", errors.Wrapf(err, "building for platform %s", style.Symbol(imageindex.PlatformString(platform)))
+		}
+
+		manifests = append(manifests, imageindex.Manifest{
+			Ref:      imageindex.PlatformTag(imageTag, platform),
+			Platform: platform,
+		})
+	}
+
+	digest, err := imageindex.Publish(c.keychain, manifests, tags...)
+	if err != nil {
+		return "", err
+	}
+
+	for _, tag := range tags {
+		c.logger.Infof("Published image index %s with digest %s", style.Symbol(tag.Name()), style.Symbol(digest.String()))
+	}
+	return digest.String(), nil
+}
+
+// platformBuildOptions returns the options of the build for a single platform.
+// The image and any image cache are suffixed with the platform, and bind caches use a subdirectory per platform,
+// so that builds for different platforms never share cached layers.
+func platformBuildOptions(opts BuildOptions, imageTag name.Tag, platform v1.Platform) (BuildOptions, error) {
+	platformOpts := opts
+	platformOpts.Image = imageindex.PlatformTag(imageTag, platform).Name()
+	platformOpts.Platforms = []string{imageindex.PlatformString(platform)}
+	platformOpts.AdditionalTags = nil
+
+	if opts.CacheImage != "" {
+		cacheImage, err := platformImageName(opts.CacheImage, platform)
+		if err != nil {
+			return BuildOptions{}, errors.Wrapf(err, "invalid cache image '%s'", opts.CacheImage)
+		}
+		platformOpts.CacheImage = cacheImage
+	}
+
+	switch opts.Cache.Build.Format {
+	case cache.CacheImage:
+		cacheImage, err := platformImageName(opts.Cache.Build.Source, platform)
+		if err != nil {
+			return BuildOptions{}, errors.Wrapf(err, "invalid cache image '%s'", opts.Cache.Build.Source)
+		}
+		platformOpts.Cache.Build.Source = cacheImage
+	case cache.CacheBind:
+		platformOpts.Cache.Build.Source = filepath.Join(opts.Cache.Build.Source, imageindex.PlatformSuffix(platform))
+	}
+
+	return platformOpts, nil
+}
+
+func platformImageName(imageName string, platform v1.Platform) (string, error) {
+	tag, err := name.NewTag(imageName, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	return imageindex.PlatformTag(tag, platform).Name(), nil
+}
+
+func parsePlatforms(platforms []string) ([]v1.Platform, error) {
+	var parsed []v1.Platform
+	seen := map[string]bool{}
+	for _, p := range platforms {
+		platform, err := imageindex.ParsePlatform(p)
+		if err != nil {
+			return nil, err
+		}
+
+		key := imageindex.PlatformString(platform)
+		if seen[key] {
+			return nil, errors.Errorf("platform %s is specified more than once", style.Symbol(key))
+		}
+		seen[key] = true
+
+		parsed = append(parsed, platform)
+	}
+	return parsed, nil
+}
+
+// validateBuilderPlatform ensures the builder image fetched for a platform is built for it,
+// as single platform builder images are returned as is by registries and daemons.
+func validateBuilderPlatform(builderImage imgutil.Image, platform string) error {
+	return validateImagePlatform(builderImage, "builder", platform)
+}
+
+// validateImagePlatform ensures an image fetched for a platform is built for it.
+// The kind of image is used in errors, such as 'builder' or 'build image'.
+func validateImagePlatform(img imgutil.Image, kind, platform string) error {
+	imgOS, err := img.OS()
+	if err != nil {
+		return errors.Wrapf(err, "getting %s OS", kind)
+	}
+
+	imgArch, err := img.Architecture()
+	if err != nil {
+		return errors.Wrapf(err, "getting %s architecture", kind)
+	}
+
+	expected, err := imageindex.ParsePlatform(platform)
+	if err != nil {
+		return err
+	}
+
+	if imgOS != expected.OS || imgArch != expected.Architecture {
+		return errors.Errorf("%s platform %s does not match platform %s", kind, style.Symbol(fmt.Sprintf("%s/%s", imgOS, imgArch)), style.Symbol(platform))
+	}
+	return nil
+}
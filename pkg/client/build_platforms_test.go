@@ -0,0 +1,369 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/fakes"
+	remoteimg "github.com/buildpacks/imgutil/remote"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/builder"
+	cfg "github.com/buildpacks/pack/internal/config"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/signature"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildPlatforms(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildPlatforms", testBuildPlatforms, spec.Report(report.Terminal{}))
+}
+
+func testBuildPlatforms(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		fakeImageFetcher *platformImageFetcher
+		fakeLifecycle    *publishingLifecycle
+		mockController   *gomock.Controller
+		server           *httptest.Server
+		registryHost     string
+		tmpDir           string
+		outBuf           bytes.Buffer
+
+		builderName = "example.com/some/builder:tag"
+		stackID     = "some.stack.id"
+	)
+
+	newBuilderImage := func(arch string) *fakes.Image {
+		img := newFakeBuilderImage(t, tmpDir, builderName, stackID, "default/run", builder.DefaultLifecycleVersion, newLinuxImage)
+		h.AssertNil(t, img.SetArchitecture(arch))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "build:mixinB", "mixinX", "build:mixinY"]`))
+		return img
+	}
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "build-platforms-test")
+		h.AssertNil(t, err)
+
+		server = httptest.NewServer(registry.New())
+		u, err := url.Parse(server.URL)
+		h.AssertNil(t, err)
+		registryHost = u.Host
+
+		fakeImageFetcher = &platformImageFetcher{
+			FakeImageFetcher: ifakes.NewFakeImageFetcher(),
+			platformImages: map[string]imgutil.Image{
+				builderName + "@linux/amd64": newBuilderImage("amd64"),
+				builderName + "@linux/arm64": newBuilderImage("arm64"),
+			},
+		}
+
+		runImage := newLinuxImage("default/run", "", nil)
+		h.AssertNil(t, runImage.SetLabel("io.buildpacks.stack.id", stackID))
+		h.AssertNil(t, runImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "run:mixinC", "mixinX", "run:mixinZ"]`))
+		fakeImageFetcher.RemoteImages[runImage.Name()] = runImage
+
+		lifecycleImage := newLinuxImage(fmt.Sprintf("%s:%s", cfg.DefaultLifecycleImageRepo, builder.DefaultLifecycleVersion), "", nil)
+		fakeImageFetcher.LocalImages[lifecycleImage.Name()] = lifecycleImage
+
+		fakeLifecycle = &publishingLifecycle{}
+
+		mockController = gomock.NewController(t)
+		mockDockerClient := testmocks.NewMockCommonAPIClient(mockController)
+		mockDockerClient.EXPECT().ImageRemove(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		subject = &Client{
+			logger:            logging.NewLogWithWriters(&outBuf, &outBuf),
+			keychain:          authn.DefaultKeychain,
+			imageFetcher:      fakeImageFetcher,
+			lifecycleExecutor: fakeLifecycle,
+			engine:            engine.NewDocker(mockDockerClient),
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		server.Close()
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Build", func() {
+		when("a single platform is given", func() {
+			it("uses the builder for the platform", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:     registryHost + "/some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Publish:   true,
+					Platforms: []string{"linux/arm64"},
+				}))
+
+				h.AssertEq(t, len(fakeLifecycle.opts), 1)
+				h.AssertEq(t, fakeLifecycle.opts[0].Image.Name(), registryHost+"/some/app:latest")
+				h.AssertEq(t, fakeImageFetcher.FetchCalls[builderName].Platform, "linux/arm64")
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["default/run"].Platform, "linux/arm64")
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["buildpacksio/lifecycle:0.13.3"].Platform, "linux/arm64")
+
+				_, err := remote.Index(mustParseReference(t, registryHost+"/some/app:latest"))
+				h.AssertNotNil(t, err)
+			})
+
+			it("signs the published image rather than the image its tag refers to", func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				h.AssertNil(t, err)
+				der, err := x509.MarshalPKCS8PrivateKey(key)
+				h.AssertNil(t, err)
+				keyPath := filepath.Join(tmpDir, "cosign.key")
+				h.AssertNil(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+				ref := mustParseReference(t, registryHost+"/some/app:1.0")
+				fakeLifecycle.onPublish = func(opts build.LifecycleOptions, digest v1.Hash) error {
+					fakeImageFetcher.RemoteImages[opts.Image.Name()] = fakes.NewImage(opts.Image.Name(), "", remoteimg.DigestIdentifier{Digest: ref.Context().Digest(digest.String())})
+
+					// another image is pushed to the tag concurrently
+					other, err := random.Image(1024, 1)
+					if err != nil {
+						return err
+					}
+					return remote.Write(opts.Image, other)
+				}
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      ref.Name(),
+					AppPath:    tmpDir,
+					Builder:    builderName,
+					Publish:    true,
+					Platforms:  []string{"linux/arm64"},
+					SigningKey: keyPath,
+				}))
+
+				built := ref.Context().Digest(fakeLifecycle.digests[0].String())
+				h.AssertNil(t, signature.Verify(built, &key.PublicKey, authn.DefaultKeychain))
+				desc, err := remote.Get(ref)
+				h.AssertNil(t, err)
+				h.AssertNotNil(t, signature.Verify(ref.Context().Digest(desc.Digest.String()), &key.PublicKey, authn.DefaultKeychain))
+			})
+
+			it("fails when the builder doesn't match the platform", func() {
+				fakeImageFetcher.platformImages[builderName+"@linux/arm64"] = newBuilderImage("amd64")
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:     registryHost + "/some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Publish:   true,
+					Platforms: []string{"linux/arm64"},
+				})
+				h.AssertError(t, err, "builder platform 'linux/amd64' does not match platform 'linux/arm64'")
+			})
+
+			it("fails for an invalid platform", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:     registryHost + "/some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Platforms: []string{"arm64"},
+				})
+				h.AssertError(t, err, "invalid platform 'arm64'")
+			})
+		})
+
+		when("multiple platforms are given", func() {
+			it("builds each platform and publishes an image index", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:          registryHost + "/some/app:1.0",
+					AppPath:        tmpDir,
+					AdditionalTags: []string{registryHost + "/some/app:latest"},
+					Builder:        builderName,
+					Publish:        true,
+					Platforms:      []string{"linux/amd64", "linux/arm64"},
+				}))
+
+				h.AssertEq(t, len(fakeLifecycle.opts), 2)
+				h.AssertEq(t, fakeLifecycle.opts[0].Image.Name(), registryHost+"/some/app:1.0-linux-amd64")
+				h.AssertEq(t, fakeLifecycle.opts[1].Image.Name(), registryHost+"/some/app:1.0-linux-arm64")
+				for _, opts := range fakeLifecycle.opts {
+					h.AssertEq(t, len(opts.AdditionalTags), 0)
+				}
+
+				for _, tag := range []string{"1.0", "latest"} {
+					index, err := remote.Index(mustParseReference(t, registryHost+"/some/app:"+tag))
+					h.AssertNil(t, err)
+
+					manifest, err := index.IndexManifest()
+					h.AssertNil(t, err)
+					h.AssertEq(t, len(manifest.Manifests), 2)
+					h.AssertEq(t, manifest.Manifests[0].Platform.Architecture, "amd64")
+					h.AssertEq(t, manifest.Manifests[0].Digest, fakeLifecycle.digests[0])
+					h.AssertEq(t, manifest.Manifests[1].Platform.Architecture, "arm64")
+					h.AssertEq(t, manifest.Manifests[1].Digest, fakeLifecycle.digests[1])
+				}
+
+				h.AssertContains(t, outBuf.String(), "Published image index '"+registryHost+"/some/app:1.0'")
+			})
+
+			it("signs the published image index when a signing key is given", func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				h.AssertNil(t, err)
+				der, err := x509.MarshalPKCS8PrivateKey(key)
+				h.AssertNil(t, err)
+				keyPath := filepath.Join(tmpDir, "cosign.key")
+				h.AssertNil(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      registryHost + "/some/app:1.0",
+					AppPath:    tmpDir,
+					Builder:    builderName,
+					Publish:    true,
+					Platforms:  []string{"linux/amd64", "linux/arm64"},
+					SigningKey: keyPath,
+				}))
+
+				ref := mustParseReference(t, registryHost+"/some/app:1.0")
+				desc, err := remote.Get(ref)
+				h.AssertNil(t, err)
+				h.AssertNil(t, signature.Verify(ref.Context().Digest(desc.Digest.String()), &key.PublicKey, authn.DefaultKeychain))
+			})
+
+			it("separates the caches of each platform", func() {
+				var cacheOpts cache.CacheOpts
+				h.AssertNil(t, cacheOpts.Set("type=build,format=bind,source="+filepath.Join(tmpDir, "cache")))
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      registryHost + "/some/app",
+					AppPath:    tmpDir,
+					CacheImage: registryHost + "/some/cache",
+					Cache:      cacheOpts,
+					Builder:    builderName,
+					Publish:    true,
+					Platforms:  []string{"linux/amd64", "linux/arm64"},
+				}))
+
+				h.AssertEq(t, fakeLifecycle.opts[0].CacheImage, registryHost+"/some/cache:latest-linux-amd64")
+				h.AssertEq(t, fakeLifecycle.opts[0].Cache.Build.Source, filepath.Join(tmpDir, "cache", "linux-amd64"))
+				h.AssertEq(t, fakeLifecycle.opts[1].CacheImage, registryHost+"/some/cache:latest-linux-arm64")
+				h.AssertEq(t, fakeLifecycle.opts[1].Cache.Build.Source, filepath.Join(tmpDir, "cache", "linux-arm64"))
+			})
+
+			it("requires publishing", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:     "some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Platforms: []string{"linux/amd64", "linux/arm64"},
+				})
+				h.AssertError(t, err, "building for multiple platforms requires publishing the image")
+			})
+
+			it("fails when a platform is repeated", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:     registryHost + "/some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Publish:   true,
+					Platforms: []string{"linux/amd64", "linux/amd64"},
+				})
+				h.AssertError(t, err, "platform 'linux/amd64' is specified more than once")
+			})
+
+			it("reports the platform that failed", func() {
+				delete(fakeImageFetcher.platformImages, builderName+"@linux/arm64")
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:     registryHost + "/some/app",
+					AppPath:   tmpDir,
+					Builder:   builderName,
+					Publish:   true,
+					Platforms: []string{"linux/amd64", "linux/arm64"},
+				})
+				h.AssertError(t, err, "building for platform 'linux/arm64'")
+			})
+		})
+	})
+}
+
+func mustParseReference(t *testing.T, ref string) name.Reference {
+	t.Helper()
+
+	parsed, err := name.ParseReference(ref)
+	h.AssertNil(t, err)
+	return parsed
+}
+
+// platformImageFetcher returns images registered for the requested platform before falling back to the fake fetcher.
+type platformImageFetcher struct {
+	*ifakes.FakeImageFetcher
+	platformImages map[string]imgutil.Image
+}
+
+func (f *platformImageFetcher) Fetch(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+	if img, ok := f.platformImages[name+"@"+options.Platform]; ok {
+		f.FetchCalls[name] = &ifakes.FetchArgs{Daemon: options.Daemon, PullPolicy: options.PullPolicy, Platform: options.Platform, SkipVerification: options.SkipVerification}
+		return img, nil
+	}
+
+	return f.FakeImageFetcher.Fetch(ctx, name, options)
+}
+
+// publishingLifecycle publishes a random image for each execution, as the lifecycle would when publishing.
+// When set, onPublish is called with the digest of each published image.
+type publishingLifecycle struct {
+	opts      []build.LifecycleOptions
+	digests   []v1.Hash
+	onPublish func(opts build.LifecycleOptions, digest v1.Hash) error
+}
+
+func (l *publishingLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
+	l.opts = append(l.opts, opts)
+
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		return err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return err
+	}
+	l.digests = append(l.digests, digest)
+
+	if err := remote.Write(opts.Image, img); err != nil {
+		return err
+	}
+
+	if l.onPublish == nil {
+		return nil
+	}
+	return l.onPublish(opts, digest)
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"time"
+
+	"github.com/buildpacks/pack/pkg/events"
+)
+
+// BuildReport summarizes the time and resources spent in each lifecycle phase of a build.
+// It is assembled from the events emitted during a build, see BuildOptions.EventHandler.
+type BuildReport struct {
+	// Phases lists each lifecycle phase that was run, in order.
+	Phases []PhaseReport `json:"phases"`
+}
+
+// PhaseReport describes the time and resources spent in a single lifecycle phase.
+type PhaseReport struct {
+	// Name of the lifecycle phase, e.g. `detector`.
+	Name string `json:"name"`
+
+	// Duration is the wall-clock time taken by the phase.
+	Duration time.Duration `json:"duration"`
+
+	// PhaseStats describes the resources used by the phase. They are zero when not reported by the phase event.
+	// LayersSize is only populated when BuildOptions.MeasureLayers is set.
+	events.PhaseStats
+
+	// Error is the error message of a failed phase.
+	Error string `json:"error,omitempty"`
+}
+
+// Record adds the phase described by a PhaseFinished event to the report. Other events are ignored.
+func (r *BuildReport) Record(e events.Event) {
+	if e.Type != events.PhaseFinished {
+		return
+	}
+
+	phase := PhaseReport{
+		Name:     e.Phase,
+		Duration: e.Duration,
+		Error:    e.Error,
+	}
+	if e.Stats != nil {
+		phase.PhaseStats = *e.Stats
+	}
+
+	r.Phases = append(r.Phases, phase)
+}
+
+// TotalDuration is the sum of the durations of all recorded phases.
+func (r BuildReport) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, phase := range r.Phases {
+		total += phase.Duration
+	}
+	return total
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/events"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildReport(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildReport", testBuildReport, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildReport(t *testing.T, when spec.G, it spec.S) {
+	when("#Record", func() {
+		it("records finished phases", func() {
+			var subject BuildReport
+
+			subject.Record(events.Event{Type: events.PhaseStarted, Phase: "detector"})
+			subject.Record(events.Event{
+				Type:     events.PhaseFinished,
+				Phase:    "detector",
+				Duration: 2 * time.Second,
+				Stats: &events.PhaseStats{
+					ContainerCreate: 100 * time.Millisecond,
+					ContainerStart:  200 * time.Millisecond,
+					ContainerWait:   time.Second,
+					BytesCopied:     1024,
+					LayersSize:      2048,
+				},
+			})
+			subject.Record(events.Event{Type: events.PhaseFinished, Phase: "builder", Duration: 3 * time.Second, Error: "some-error"})
+
+			h.AssertEq(t, subject.Phases, []PhaseReport{
+				{
+					Name:     "detector",
+					Duration: 2 * time.Second,
+					PhaseStats: events.PhaseStats{
+						ContainerCreate: 100 * time.Millisecond,
+						ContainerStart:  200 * time.Millisecond,
+						ContainerWait:   time.Second,
+						BytesCopied:     1024,
+						LayersSize:      2048,
+					},
+				},
+				{
+					Name:     "builder",
+					Duration: 3 * time.Second,
+					Error:    "some-error",
+				},
+			})
+			h.AssertEq(t, subject.TotalDuration(), 5*time.Second)
+
+			// the stats are inlined in the JSON of the report
+			data, err := json.Marshal(subject.Phases[0])
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(data), `"containerCreate":100000000`)
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/events"
+)
+
+// BuildResult describes the app image built by BuildWithResult, and the build itself.
+type BuildResult struct {
+	// ImageMetadata describes the app image: its name and digest, the tags it was saved to, its run image, and the
+	// buildpacks and processes of the group selected to build it.
+	ImageMetadata
+
+	// StartedAt is when the build started.
+	StartedAt time.Time
+
+	// Duration is the time the build took.
+	Duration time.Duration
+
+	// Phases lists each lifecycle phase that was run, in order, with the time and resources it spent.
+	Phases []PhaseReport
+}
+
+// BuildWithResult builds the app image like Build, and returns a description of the image it built.
+// It cannot be used to build for multiple platforms.
+func (c *Client) BuildWithResult(ctx context.Context, opts BuildOptions) (BuildResult, error) {
+	if len(opts.Platforms) > 1 {
+		return BuildResult{}, errors.New("a build result cannot be returned when building for multiple platforms")
+	}
+
+	var report BuildReport
+	handler := opts.EventHandler
+	opts.EventHandler = func(e events.Event) {
+		report.Record(e)
+		if handler != nil {
+			handler(e)
+		}
+	}
+
+	result := BuildResult{StartedAt: time.Now()}
+	err := c.buildImage(ctx, opts, func(metadata ImageMetadata) {
+		result.ImageMetadata = metadata
+	})
+	if err != nil {
+		return BuildResult{}, err
+	}
+
+	result.Duration = time.Since(result.StartedAt)
+	result.Phases = report.Phases
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// buildSession shares the images pulled, the buildpacks downloaded and the ephemeral builders created between several
+// builds, which may run concurrently. See Client.withSession.
+// Failures are shared as well, except those of a build whose context is cancelled or times out: the next build needing
+// the same image, buildpack or ephemeral builder tries again with its own context.
+type buildSession struct {
+	mu                sync.Mutex
+	pulls             map[string]*sessionPull
+	downloads         map[string]*sessionDownload
+	ephemeralBuilders map[string]*sessionBuilder
+}
+
+type sessionPull struct {
+	mu   sync.Mutex
+	done bool
+	err  error
+}
+
+type sessionDownload struct {
+	mu     sync.Mutex
+	done   bool
+	mainBP buildpack.Buildpack
+	depBPs []buildpack.Buildpack
+	err    error
+}
+
+type sessionBuilder struct {
+	mu     sync.Mutex
+	done   bool
+	bldr   *builder.Builder
+	remove func()
+	err    error
+}
+
+func newBuildSession() *buildSession {
+	return &buildSession{
+		pulls:             map[string]*sessionPull{},
+		downloads:         map[string]*sessionDownload{},
+		ephemeralBuilders: map[string]*sessionBuilder{},
+	}
+}
+
+// withSession returns a copy of the client whose builds share the images, buildpacks and ephemeral builders of
+// session. The ephemeral builders that aren't kept are removed by cleanup once all builds of the session are done.
+func (c *Client) withSession(session *buildSession) *Client {
+	sessionClient := *c
+	sessionClient.session = session
+	sessionClient.imageFetcher = &sessionImageFetcher{ImageFetcher: c.imageFetcher, session: session}
+	sessionClient.buildpackDownloader = &sessionBuildpackDownloader{BuildpackDownloader: c.buildpackDownloader, session: session}
+	return &sessionClient
+}
+
+// cleanup removes the ephemeral builders created during the session that aren't kept for later builds.
+func (s *buildSession) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.ephemeralBuilders {
+		if entry.remove != nil {
+			entry.remove()
+		}
+	}
+}
+
+// ephemeralBuilder returns the ephemeral builder of key, creating it on first use.
+func (s *buildSession) ephemeralBuilder(key string, create func() (*builder.Builder, func(), error)) (*builder.Builder, error) {
+	s.mu.Lock()
+	entry, ok := s.ephemeralBuilders[key]
+	if !ok {
+		entry = &sessionBuilder{}
+		s.ephemeralBuilders[key] = entry
+	}
+	s.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if !entry.done {
+		bldr, remove, err := create()
+		if isContextError(err) {
+			return nil, err
+		}
+		entry.bldr, entry.remove, entry.err, entry.done = bldr, remove, err, true
+	}
+	return entry.bldr, entry.err
+}
+
+func (s *buildSession) pull(key string) *sessionPull {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.pulls[key]
+	if !ok {
+		entry = &sessionPull{}
+		s.pulls[key] = entry
+	}
+	return entry
+}
+
+func (s *buildSession) download(key string) *sessionDownload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.downloads[key]
+	if !ok {
+		entry = &sessionDownload{}
+		s.downloads[key] = entry
+	}
+	return entry
+}
+
+// sessionImageFetcher pulls each image to the daemon once per session. Images are fetched again from the daemon on
+// later uses, since a new image is needed every time: creating an ephemeral builder modifies its builder image.
+type sessionImageFetcher struct {
+	ImageFetcher
+	session *buildSession
+}
+
+func (f *sessionImageFetcher) Fetch(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+	if !options.Daemon || options.PullPolicy == image.PullNever {
+		return f.ImageFetcher.Fetch(ctx, name, options)
+	}
+
+	entry := f.session.pull(name + "|" + options.Platform)
+	entry.mu.Lock()
+	if !entry.done {
+		defer entry.mu.Unlock()
+		img, err := f.ImageFetcher.Fetch(ctx, name, options)
+		if !isContextError(err) {
+			entry.err, entry.done = err, true
+		}
+		return img, err
+	}
+	err := entry.err
+	entry.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	options.PullPolicy = image.PullNever
+	return f.ImageFetcher.Fetch(ctx, name, options)
+}
+
+// sessionBuildpackDownloader downloads each buildpack once per session.
+type sessionBuildpackDownloader struct {
+	BuildpackDownloader
+	session *buildSession
+}
+
+func (d *sessionBuildpackDownloader) Download(ctx context.Context, buildpackURI string, opts buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+	entry := d.session.download(fmt.Sprintf("%s|%+v", buildpackURI, opts))
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if !entry.done {
+		mainBP, depBPs, err := d.BuildpackDownloader.Download(ctx, buildpackURI, opts)
+		if isContextError(err) {
+			return nil, nil, err
+		}
+		entry.mainBP, entry.depBPs, entry.err, entry.done = mainBP, depBPs, err, true
+	}
+	return entry.mainBP, entry.depBPs, entry.err
+}
+
+// isContextError reports whether err comes from a cancelled or timed out context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildSession(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildSession", testBuildSession, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildSession(t *testing.T, when spec.G, it spec.S) {
+	var (
+		session        *buildSession
+		mockController *gomock.Controller
+	)
+
+	it.Before(func() {
+		session = newBuildSession()
+		mockController = gomock.NewController(t)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#sessionImageFetcher", func() {
+		var (
+			fetcher *sessionImageFetcher
+			calls   []image.FetchOptions
+			errs    []error
+		)
+
+		it.Before(func() {
+			calls, errs = nil, nil
+			fetcher = &sessionImageFetcher{
+				ImageFetcher: imageFetcherFunc(func(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+					calls = append(calls, options)
+					var err error
+					if len(errs) > 0 {
+						err, errs = errs[0], errs[1:]
+					}
+					if err != nil {
+						return nil, err
+					}
+					return fakes.NewImage(name, "", nil), nil
+				}),
+				session: session,
+			}
+		})
+
+		it("pulls an image again after a cancelled pull", func() {
+			errs = []error{context.Canceled}
+			options := image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways}
+
+			_, err := fetcher.Fetch(context.TODO(), "some/image", options)
+			h.AssertError(t, err, context.Canceled.Error())
+
+			_, err = fetcher.Fetch(context.TODO(), "some/image", options)
+			h.AssertNil(t, err)
+			_, err = fetcher.Fetch(context.TODO(), "some/image", options)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(calls), 3)
+			h.AssertEq(t, calls[1].PullPolicy, image.PullAlways)
+			h.AssertEq(t, calls[2].PullPolicy, image.PullNever)
+		})
+
+		it("shares the errors of failed pulls", func() {
+			errs = []error{errors.New("some-error")}
+			options := image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways}
+
+			_, err := fetcher.Fetch(context.TODO(), "some/image", options)
+			h.AssertError(t, err, "some-error")
+			_, err = fetcher.Fetch(context.TODO(), "some/image", options)
+			h.AssertError(t, err, "some-error")
+
+			h.AssertEq(t, len(calls), 1)
+		})
+	})
+
+	when("#sessionBuildpackDownloader", func() {
+		var (
+			mockDownloader *testmocks.MockBuildpackDownloader
+			downloader     *sessionBuildpackDownloader
+		)
+
+		it.Before(func() {
+			mockDownloader = testmocks.NewMockBuildpackDownloader(mockController)
+			downloader = &sessionBuildpackDownloader{BuildpackDownloader: mockDownloader, session: session}
+		})
+
+		it("downloads a buildpack again after a timed out download", func() {
+			gomock.InOrder(
+				mockDownloader.EXPECT().Download(gomock.Any(), "some/bp", gomock.Any()).Return(nil, nil, context.DeadlineExceeded),
+				mockDownloader.EXPECT().Download(gomock.Any(), "some/bp", gomock.Any()).Return(nil, nil, nil),
+			)
+
+			_, _, err := downloader.Download(context.TODO(), "some/bp", buildpack.DownloadOptions{})
+			h.AssertError(t, err, context.DeadlineExceeded.Error())
+
+			for i := 0; i < 2; i++ {
+				_, _, err = downloader.Download(context.TODO(), "some/bp", buildpack.DownloadOptions{})
+				h.AssertNil(t, err)
+			}
+		})
+
+		it("shares the errors of failed downloads", func() {
+			mockDownloader.EXPECT().Download(gomock.Any(), "some/bp", gomock.Any()).Return(nil, nil, errors.New("some-error")).Times(1)
+
+			for i := 0; i < 2; i++ {
+				_, _, err := downloader.Download(context.TODO(), "some/bp", buildpack.DownloadOptions{})
+				h.AssertError(t, err, "some-error")
+			}
+		})
+	})
+
+	when("#ephemeralBuilder", func() {
+		it("creates the ephemeral builder again after a cancelled creation", func() {
+			var creations int
+			create := func() (*builder.Builder, func(), error) {
+				creations++
+				if creations == 1 {
+					return nil, nil, context.Canceled
+				}
+				return nil, func() {}, nil
+			}
+
+			_, err := session.ephemeralBuilder("some-key", create)
+			h.AssertError(t, err, context.Canceled.Error())
+
+			for i := 0; i < 2; i++ {
+				_, err = session.ephemeralBuilder("some-key", create)
+				h.AssertNil(t, err)
+			}
+			h.AssertEq(t, creations, 2)
+		})
+	})
+}
+
+type imageFetcherFunc func(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error)
+
+func (f imageFetcherFunc) Fetch(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+	return f(ctx, name, options)
+}
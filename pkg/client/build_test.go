@@ -5,15 +5,19 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,9 +27,14 @@ import (
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/imgutil/remote"
 	"github.com/buildpacks/lifecycle/api"
+	lifecycleBuildpack "github.com/buildpacks/lifecycle/buildpack"
 	"github.com/buildpacks/lifecycle/platform"
 	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/go-units"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	"github.com/onsi/gomega/ghttp"
 	"github.com/pkg/errors"
@@ -36,13 +45,17 @@ import (
 	"github.com/buildpacks/pack/internal/builder"
 	cfg "github.com/buildpacks/pack/internal/config"
 	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/provenance"
 	rg "github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/project"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -80,7 +93,21 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		var err error
 
 		fakeImageFetcher = ifakes.NewFakeImageFetcher()
-		fakeLifecycle = &ifakes.FakeLifecycle{}
+		fakeLifecycle = &ifakes.FakeLifecycle{
+			OnExecute: func(opts build.LifecycleOptions) {
+				// the app image is exported, unless it's only detected or a test provides it
+				if opts.DetectHandler != nil {
+					return
+				}
+				images := fakeImageFetcher.LocalImages
+				if opts.Publish {
+					images = fakeImageFetcher.RemoteImages
+				}
+				if _, ok := images[opts.Image.Name()]; !ok {
+					images[opts.Image.Name()] = fakes.NewImage(opts.Image.Name(), "", nil)
+				}
+			},
+		}
 
 		tmpDir, err = ioutil.TempDir("", "build-test")
 		h.AssertNil(t, err)
@@ -131,7 +158,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			imageFetcher:        fakeImageFetcher,
 			downloader:          blobDownloader,
 			lifecycleExecutor:   fakeLifecycle,
-			docker:              docker,
+			engine:              engine.NewDocker(docker),
 			buildpackDownloader: buildpackDownloader,
 		}
 	})
@@ -146,6 +173,42 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 	})
 
 	when("#Build", func() {
+		when("DockerHost option", func() {
+			it("passes the docker host to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					DockerHost: "tcp://example.com:1234",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.DockerHost, "tcp://example.com:1234")
+			})
+
+			when("the container engine is podman", func() {
+				it.Before(func() {
+					podman, err := engine.NewPodman("unix:///some/podman.sock")
+					h.AssertNil(t, err)
+					subject.engine = podman
+				})
+
+				it("passes the podman socket to the lifecycle", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.DockerHost, "unix:///some/podman.sock")
+				})
+
+				it("keeps the docker host set", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						DockerHost: "unix:///other/podman.sock",
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.DockerHost, "unix:///other/podman.sock")
+				})
+			})
+		})
+
 		when("Workspace option", func() {
 			it("uses the specified dir", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -1554,11 +1617,29 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		when("ProjectDescriptor", func() {
 			when("project metadata", func() {
+				// the app is outside of a git repository, unlike the working directory of the tests
+				var appPath string
+
+				it.Before(func() {
+					// the OCI labels derived from the project information are set on the built image
+					builtImage := fakes.NewImage("index.docker.io/some/app:latest", "", nil)
+					fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+
+					var err error
+					appPath, err = ioutil.TempDir("", "build-app-test")
+					h.AssertNil(t, err)
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(appPath))
+				})
+
 				when("not experimental", func() {
 					it("does not set project source", func() {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    defaultBuilderName,
+							AppPath:    appPath,
 							ClearCache: true,
 							ProjectDescriptor: projectTypes.Descriptor{
 								Project: projectTypes.Project{
@@ -1583,6 +1664,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 							err := subject.Build(context.TODO(), BuildOptions{
 								Image:             "some/app",
 								Builder:           defaultBuilderName,
+								AppPath:           appPath,
 								ClearCache:        true,
 								ProjectDescriptor: projectTypes.Descriptor{},
 							})
@@ -1596,6 +1678,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    defaultBuilderName,
+							AppPath:    appPath,
 							ClearCache: true,
 							ProjectDescriptor: projectTypes.Descriptor{
 								Project: projectTypes.Project{
@@ -1614,6 +1697,117 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						})
 					})
 				})
+
+				when("the app is a git repository", func() {
+					var (
+						appPath string
+						commit  string
+					)
+
+					it.Before(func() {
+						var err error
+						appPath, err = ioutil.TempDir("", "build-git-test")
+						h.AssertNil(t, err)
+
+						repo, hash := createGitRepo(t, appPath)
+						addRemote(t, repo, "origin", "https://example.com/some/app.git")
+						commit = hash.String()
+					})
+
+					it.After(func() {
+						h.AssertNil(t, os.RemoveAll(appPath))
+					})
+
+					it("sets the git source", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:   "some/app",
+							Builder: defaultBuilderName,
+							AppPath: appPath,
+							ProjectDescriptor: projectTypes.Descriptor{
+								Project: projectTypes.Project{
+									Version:   "1.2.3",
+									SourceURL: "https://example.com",
+								},
+							},
+						}))
+
+						h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source, &platform.ProjectSource{
+							Type:    "git",
+							Version: map[string]interface{}{"commit": commit},
+							Metadata: map[string]interface{}{
+								"branch": "master",
+								"url":    "https://example.com/some/app.git",
+							},
+						})
+					})
+
+					it("fills in the source of the project descriptor with the git source", func() {
+						subject.experimental = true
+
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:   "some/app",
+							Builder: defaultBuilderName,
+							AppPath: appPath,
+							ProjectDescriptor: projectTypes.Descriptor{
+								Project: projectTypes.Project{
+									Version:   "1.2.3",
+									SourceURL: "https://example.com",
+								},
+							},
+						}))
+
+						h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source, &platform.ProjectSource{
+							Type:    "project",
+							Version: map[string]interface{}{"declared": "1.2.3", "commit": commit},
+							Metadata: map[string]interface{}{
+								"branch": "master",
+								"url":    "https://example.com",
+							},
+						})
+					})
+
+					it("doesn't search for the repository of an app file", func() {
+						jar, err := ioutil.ReadFile(filepath.Join("testdata", "jar-file.jar"))
+						h.AssertNil(t, err)
+						jarPath := filepath.Join(appPath, "app.jar")
+						h.AssertNil(t, ioutil.WriteFile(jarPath, jar, 0600))
+
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:   "some/app",
+							Builder: defaultBuilderName,
+							AppPath: jarPath,
+						}))
+
+						h.AssertNil(t, fakeLifecycle.Opts.ProjectMetadata.Source)
+					})
+
+					it("records the state of the working tree with CheckGitDirty", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:         "some/app",
+							Builder:       defaultBuilderName,
+							AppPath:       appPath,
+							CheckGitDirty: true,
+						}))
+
+						h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["dirty"], false)
+					})
+
+					it("sets the revision label", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:   "some/app",
+							Builder: defaultBuilderName,
+							AppPath: appPath,
+						}))
+
+						builtImage := fakeImageFetcher.LocalImages["index.docker.io/some/app:latest"]
+						revision, err := builtImage.Label("org.opencontainers.image.revision")
+						h.AssertNil(t, err)
+						h.AssertEq(t, revision, commit)
+						source, err := builtImage.Label("org.opencontainers.image.source")
+						h.AssertNil(t, err)
+						h.AssertEq(t, source, "https://example.com/some/app.git")
+					})
+				})
 			})
 		})
 
@@ -1634,6 +1828,65 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("ephemeral builder", func() {
+			it("names the ephemeral builder after the builder and what is added to it", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}))
+				firstName := fakeLifecycle.Opts.Builder.Name()
+				h.AssertContains(t, firstName, "pack.local/builder/")
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), firstName)
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Buildpacks: []string{"buildpack.1.id@buildpack.1.version"},
+				}))
+				h.AssertNotEq(t, fakeLifecycle.Opts.Builder.Name(), firstName)
+			})
+
+			it("reuses the ephemeral builder of a previous build", func() {
+				opts := BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}
+				h.AssertNil(t, subject.Build(context.TODO(), opts))
+				ephemeralBuilderName := fakeLifecycle.Opts.Builder.Name()
+
+				cachedBuilderImage := newFakeBuilderImage(t, tmpDir, ephemeralBuilderName, defaultBuilderStackID, defaultRunImageName, builder.DefaultLifecycleVersion, newLinuxImage)
+				h.AssertNil(t, cachedBuilderImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "build:mixinB", "mixinX", "build:mixinY"]`))
+				fakeImageFetcher.LocalImages[ephemeralBuilderName] = cachedBuilderImage
+
+				h.AssertNil(t, subject.Build(context.TODO(), opts))
+				h.AssertTrue(t, fakeLifecycle.Opts.Builder.Image() == cachedBuilderImage)
+				h.AssertFalse(t, cachedBuilderImage.IsSaved())
+			})
+
+			it("doesn't reuse the ephemeral builder of a build with environment variables", func() {
+				opts := BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Env:     map[string]string{"key1": "value1"},
+				}
+				h.AssertNil(t, subject.Build(context.TODO(), opts))
+				ephemeralBuilderName := fakeLifecycle.Opts.Builder.Name()
+				h.AssertContains(t, ephemeralBuilderName, "pack.local/builder/")
+
+				cachedBuilderImage := newFakeBuilderImage(t, tmpDir, ephemeralBuilderName, defaultBuilderStackID, defaultRunImageName, builder.DefaultLifecycleVersion, newLinuxImage)
+				fakeImageFetcher.LocalImages[ephemeralBuilderName] = cachedBuilderImage
+
+				h.AssertNil(t, subject.Build(context.TODO(), opts))
+				h.AssertNotEq(t, fakeLifecycle.Opts.Builder.Name(), ephemeralBuilderName)
+				h.AssertFalse(t, fakeLifecycle.Opts.Builder.Image() == cachedBuilderImage)
+			})
+		})
+
 		when("Publish option", func() {
 			var remoteRunImage, builderWithoutLifecycleImageOrCreator *fakes.Image
 
@@ -1840,118 +2093,543 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
-		when("PullPolicy", func() {
-			when("never", func() {
-				it("uses the local builder and run images without updating", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-						Image:      "some/app",
-						Builder:    defaultBuilderName,
-						PullPolicy: image.PullNever,
-					}))
+		when("OCILayoutDir option", func() {
+			it("fails when publishing", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					Publish:      true,
+					OCILayoutDir: filepath.Join(tmpDir, "some-layout"),
+				})
+				h.AssertError(t, err, "an OCI layout output cannot be used when publishing")
+			})
+		})
 
-					args := fakeImageFetcher.FetchCalls["default/run"]
-					h.AssertEq(t, args.Daemon, true)
-					h.AssertEq(t, args.PullPolicy, image.PullNever)
+		when("LockFile option", func() {
+			var lockFile string
 
-					args = fakeImageFetcher.FetchCalls[defaultBuilderName]
-					h.AssertEq(t, args.Daemon, true)
-					h.AssertEq(t, args.PullPolicy, image.PullNever)
+			it.Before(func() {
+				lockFile = filepath.Join(tmpDir, "project.lock")
+				defaultBuilderImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:builder-id"})
+				fakeDefaultRunImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:run-id"})
+				fakeLifecycleImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:lifecycle-id"})
+			})
 
-					args = fakeImageFetcher.FetchCalls["buildpacksio/lifecycle:0.13.3"]
-					h.AssertEq(t, args.Daemon, true)
-					h.AssertEq(t, args.PullPolicy, image.PullNever)
-					h.AssertEq(t, args.Platform, "linux/amd64")
-				})
+			buildOpts := func(locked bool) BuildOptions {
+				return BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					Buildpacks:   []string{filepath.Join("testdata", "buildpack")},
+					TrustBuilder: func(string) bool { return false },
+					LockFile:     lockFile,
+					Locked:       locked,
+				}
+			}
+
+			it("writes the digests of the resolved images and buildpacks", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), buildOpts(false)))
+
+				lock, err := project.ReadLock(lockFile)
+				h.AssertNil(t, err)
+				h.AssertEq(t, lock.Builder, projectTypes.LockedImage{Image: defaultBuilderName, Digest: "sha256:builder-id"})
+				h.AssertEq(t, lock.RunImage, projectTypes.LockedImage{Image: defaultRunImageName, Digest: "sha256:run-id"})
+				h.AssertEq(t, *lock.LifecycleImage, projectTypes.LockedImage{Image: fakeLifecycleImage.Name(), Digest: "sha256:lifecycle-id"})
+				h.AssertEq(t, len(lock.Buildpacks), 1)
+				h.AssertEq(t, lock.Buildpacks[0].URI, filepath.Join("testdata", "buildpack"))
+				h.AssertEq(t, lock.Buildpacks[0].ID, "bp.one")
+				h.AssertEq(t, lock.Buildpacks[0].Version, "1.2.3")
+				h.AssertContains(t, lock.Buildpacks[0].Digest, "sha256:")
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("Wrote lock file '%s'", lockFile))
 			})
 
-			when("always", func() {
-				it("uses pulls the builder and run image before using them", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-						Image:      "some/app",
-						Builder:    defaultBuilderName,
-						PullPolicy: image.PullAlways,
-					}))
+			it("records the registry digest of remote images", func() {
+				digest, err := name.NewDigest("default/run@sha256:" + strings.Repeat("a", 64))
+				h.AssertNil(t, err)
+				remoteRunImage := fakes.NewImage("default/run", "", remote.DigestIdentifier{Digest: digest})
+				h.AssertNil(t, remoteRunImage.SetLabel("io.buildpacks.stack.id", defaultBuilderStackID))
+				h.AssertNil(t, remoteRunImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "mixinX", "run:mixinZ"]`))
+				fakeImageFetcher.RemoteImages[remoteRunImage.Name()] = remoteRunImage
 
-					args := fakeImageFetcher.FetchCalls["default/run"]
-					h.AssertEq(t, args.Daemon, true)
-					h.AssertEq(t, args.PullPolicy, image.PullAlways)
+				opts := buildOpts(false)
+				opts.Publish = true
+				h.AssertNil(t, subject.Build(context.TODO(), opts))
 
-					args = fakeImageFetcher.FetchCalls[defaultBuilderName]
-					h.AssertEq(t, args.Daemon, true)
-					h.AssertEq(t, args.PullPolicy, image.PullAlways)
-				})
+				lock, err := project.ReadLock(lockFile)
+				h.AssertNil(t, err)
+				h.AssertEq(t, lock.RunImage.Digest, "sha256:"+strings.Repeat("a", 64))
 			})
-		})
 
-		when("ProxyConfig option", func() {
-			when("ProxyConfig is nil", func() {
-				it.Before(func() {
-					h.AssertNil(t, os.Setenv("http_proxy", "other-http-proxy"))
-					h.AssertNil(t, os.Setenv("https_proxy", "other-https-proxy"))
-					h.AssertNil(t, os.Setenv("no_proxy", "other-no-proxy"))
+			when("Locked is true", func() {
+				it("builds when the resolved images and buildpacks match the lock file", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), buildOpts(false)))
+					contents, err := ioutil.ReadFile(lockFile)
+					h.AssertNil(t, err)
+
+					fakeLifecycle.Opts = build.LifecycleOptions{}
+					h.AssertNil(t, subject.Build(context.TODO(), buildOpts(true)))
+					h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
+
+					newContents, err := ioutil.ReadFile(lockFile)
+					h.AssertNil(t, err)
+					h.AssertEq(t, string(newContents), string(contents))
 				})
 
-				when("*_PROXY env vars are set", func() {
-					it.Before(func() {
-						h.AssertNil(t, os.Setenv("HTTP_PROXY", "some-http-proxy"))
-						h.AssertNil(t, os.Setenv("HTTPS_PROXY", "some-https-proxy"))
-						h.AssertNil(t, os.Setenv("NO_PROXY", "some-no-proxy"))
-					})
+				it("fails before running the lifecycle when a resolved image differs", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), buildOpts(false)))
+					fakeDefaultRunImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:other-run-id"})
 
-					it.After(func() {
-						h.AssertNilE(t, os.Unsetenv("HTTP_PROXY"))
-						h.AssertNilE(t, os.Unsetenv("HTTPS_PROXY"))
-						h.AssertNilE(t, os.Unsetenv("NO_PROXY"))
-					})
+					fakeLifecycle.Opts = build.LifecycleOptions{}
+					err := subject.Build(context.TODO(), buildOpts(true))
+					h.AssertError(t, err, fmt.Sprintf("resolved images and buildpacks differ from lock file '%s'", lockFile))
+					h.AssertError(t, err, "run image 'default/run' resolved to 'sha256:other-run-id', locked to 'sha256:run-id'")
+					h.AssertNil(t, fakeLifecycle.Opts.Builder)
+				})
 
-					it("defaults to the *_PROXY environment variables", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-							Image:   "some/app",
-							Builder: defaultBuilderName,
-						}))
-						h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "some-http-proxy")
-						h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "some-https-proxy")
-						h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "some-no-proxy")
-					})
+				it("fails when a buildpack differs", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), buildOpts(false)))
+
+					opts := buildOpts(true)
+					opts.Buildpacks = nil
+					err := subject.Build(context.TODO(), opts)
+					h.AssertError(t, err, "0 buildpacks resolved, 1 locked")
 				})
 
-				it("falls back to the *_proxy environment variables", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-						Image:   "some/app",
-						Builder: defaultBuilderName,
-					}))
-					h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "other-http-proxy")
-					h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "other-https-proxy")
-					h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "other-no-proxy")
+				it("fails when the lock file doesn't exist", func() {
+					err := subject.Build(context.TODO(), buildOpts(true))
+					h.AssertError(t, err, fmt.Sprintf("reading lock file '%s'", lockFile))
 				})
-			}, spec.Sequential())
 
-			when("ProxyConfig is not nil", func() {
-				it("passes the values through", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-						Image:   "some/app",
-						Builder: defaultBuilderName,
-						ProxyConfig: &ProxyConfig{
-							HTTPProxy:  "custom-http-proxy",
-							HTTPSProxy: "custom-https-proxy",
-							NoProxy:    "custom-no-proxy",
-						},
-					}))
-					h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "custom-http-proxy")
-					h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "custom-https-proxy")
-					h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "custom-no-proxy")
+				it("requires a lock file", func() {
+					opts := buildOpts(true)
+					opts.LockFile = ""
+					h.AssertError(t, subject.Build(context.TODO(), opts), "a locked build requires a lock file")
 				})
 			})
+
+			it("can't be used with multiple platforms", func() {
+				opts := buildOpts(false)
+				opts.Publish = true
+				opts.Platforms = []string{"linux/amd64", "linux/arm64"}
+				h.AssertError(t, subject.Build(context.TODO(), opts), "a lock file cannot be used when building for multiple platforms")
+			})
 		})
 
-		when("Network option", func() {
-			it("passes the value through", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-					Image:   "some/app",
-					Builder: defaultBuilderName,
-					ContainerConfig: ContainerConfig{
-						Network: "some-network",
-					},
+		when("ProvenanceFile option", func() {
+			var (
+				provenanceFile string
+				builtDigest    string
+			)
+
+			it.Before(func() {
+				provenanceFile = filepath.Join(tmpDir, "provenance.json")
+				builtDigest = "sha256:" + strings.Repeat("b", 64)
+				defaultBuilderImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:" + strings.Repeat("1", 64)})
+				fakeDefaultRunImage.SetIdentifier(local.IDIdentifier{ImageID: "sha256:" + strings.Repeat("2", 64)})
+			})
+
+			readStatement := func() provenance.Statement {
+				t.Helper()
+				data, err := ioutil.ReadFile(provenanceFile)
+				h.AssertNil(t, err)
+
+				var statement provenance.Statement
+				h.AssertNil(t, json.Unmarshal(data, &statement))
+				return statement
+			}
+
+			it("writes the provenance of the app image", func() {
+				builtImage := fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{ImageID: builtDigest})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{"buildpacks": [{"id": "some/bp", "version": "1.2.3"}]}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        defaultBuilderName,
+					ProvenanceFile: provenanceFile,
+					Env:            map[string]string{"SOME_TOKEN": "some-secret-value"},
+				}))
+
+				statement := readStatement()
+				h.AssertEq(t, statement.Type, provenance.StatementType)
+				h.AssertEq(t, statement.PredicateType, provenance.PredicateType)
+				h.AssertEq(t, statement.Subject, []provenance.Subject{
+					{Name: "index.docker.io/some/app", Digest: map[string]string{"sha256": strings.Repeat("b", 64)}},
+				})
+
+				predicate := statement.Predicate
+				h.AssertEq(t, predicate.Materials, []provenance.Material{
+					{URI: defaultBuilderName, Digest: map[string]string{"sha256": strings.Repeat("1", 64)}},
+					{URI: defaultRunImageName, Digest: map[string]string{"sha256": strings.Repeat("2", 64)}},
+				})
+				h.AssertEq(t, predicate.BuildConfig["lifecycleVersion"], builder.DefaultLifecycleVersion)
+				h.AssertEq(t, predicate.BuildConfig["buildpacks"], []interface{}{
+					map[string]interface{}{"id": "some/bp", "version": "1.2.3"},
+				})
+				h.AssertEq(t, predicate.Invocation.Parameters["image"], "some/app")
+				h.AssertEq(t, predicate.Invocation.Parameters["env"], []interface{}{"SOME_TOKEN"})
+				data, err := ioutil.ReadFile(provenanceFile)
+				h.AssertNil(t, err)
+				h.AssertNotContains(t, string(data), "some-secret-value")
+				h.AssertNotNil(t, predicate.Metadata.BuildStartedOn)
+				h.AssertNotNil(t, predicate.Metadata.BuildFinishedOn)
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("Wrote provenance file '%s'", provenanceFile))
+			})
+
+			when("publishing", func() {
+				var server *httptest.Server
+
+				it.Before(func() {
+					server = httptest.NewServer(registry.New())
+					subject.keychain = authn.DefaultKeychain
+				})
+
+				it.After(func() {
+					server.Close()
+				})
+
+				it("attaches the provenance to the published image", func() {
+					u, err := url.Parse(server.URL)
+					h.AssertNil(t, err)
+					imageName := u.Host + "/some/app"
+
+					digest, err := name.NewDigest(imageName + "@" + builtDigest)
+					h.AssertNil(t, err)
+					builtImage := fakes.NewImage(imageName+":latest", "", remote.DigestIdentifier{Digest: digest})
+					fakeImageFetcher.RemoteImages[builtImage.Name()] = builtImage
+					fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:          imageName,
+						Builder:        defaultBuilderName,
+						Publish:        true,
+						ProvenanceFile: provenanceFile,
+					}))
+
+					attTag, err := provenance.Tag(digest)
+					h.AssertNil(t, err)
+					attImage, err := ggcrremote.Image(attTag)
+					h.AssertNil(t, err)
+					manifest, err := attImage.Manifest()
+					h.AssertNil(t, err)
+					h.AssertEq(t, len(manifest.Layers), 1)
+					h.AssertEq(t, manifest.Layers[0].MediaType, provenance.EnvelopeMediaType)
+					h.AssertContains(t, outBuf.String(), fmt.Sprintf("Attached provenance '%s'", attTag.Name()))
+				})
+			})
+
+			it("errors when building for multiple platforms", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        defaultBuilderName,
+					Publish:        true,
+					Platforms:      []string{"linux/amd64", "linux/arm64"},
+					ProvenanceFile: provenanceFile,
+				})
+				h.AssertError(t, err, "a provenance file cannot be used when building for multiple platforms")
+			})
+		})
+
+		when("MetadataFile option", func() {
+			var metadataFile string
+
+			it.Before(func() {
+				metadataFile = filepath.Join(tmpDir, "metadata.json")
+			})
+
+			readMetadata := func() ImageMetadata {
+				t.Helper()
+				data, err := ioutil.ReadFile(metadataFile)
+				h.AssertNil(t, err)
+
+				var metadata ImageMetadata
+				h.AssertNil(t, json.Unmarshal(data, &metadata))
+				return metadata
+			}
+
+			it("writes the metadata of the app image", func() {
+				sbomDir := filepath.Join(tmpDir, "sbom")
+				h.AssertNil(t, os.MkdirAll(filepath.Join(sbomDir, "launch"), 0755))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(sbomDir, "launch", "sbom.cdx.json"), []byte("{}"), 0600))
+
+				builtImage := fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{ImageID: "sha256:" + strings.Repeat("b", 64)})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage": {"reference": "sha256:run-image"}, "stack": {"runImage": {"image": "default/run"}}}`))
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{
+  "buildpacks": [{"id": "some/bp", "version": "1.2.3", "homepage": "https://example.com/bp"}],
+  "processes": [{"type": "web", "command": "bundle", "args": ["exec", "rackup"]}]
+}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:              "some/app",
+					Builder:            defaultBuilderName,
+					AdditionalTags:     []string{"some/app:v1"},
+					SBOMDestinationDir: sbomDir,
+					MetadataFile:       metadataFile,
+				}))
+
+				h.AssertEq(t, readMetadata(), ImageMetadata{
+					Image:          "index.docker.io/some/app:latest",
+					Digest:         "sha256:" + strings.Repeat("b", 64),
+					AdditionalTags: []string{"some/app:v1"},
+					RunImage:       RunImageMetadata{Image: "default/run", Reference: "sha256:run-image"},
+					Buildpacks:     []BuildpackMetadata{{ID: "some/bp", Version: "1.2.3", Homepage: "https://example.com/bp"}},
+					Processes:      []ProcessMetadata{{Type: "web", Command: "bundle", Args: []string{"exec", "rackup"}}},
+					SBOM:           []string{filepath.Join(sbomDir, "launch", "sbom.cdx.json")},
+				})
+				h.AssertContains(t, outBuf.String(), fmt.Sprintf("Wrote metadata file '%s'", metadataFile))
+			})
+
+			it("fetches the app image once for the steps following its export", func() {
+				builtImage := fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{ImageID: "sha256:" + strings.Repeat("b", 64)})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage": {"reference": "sha256:run-image"}}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+				fetcher := &countingImageFetcher{FakeImageFetcher: fakeImageFetcher, calls: map[string]int{}}
+				subject.imageFetcher = fetcher
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					Labels:       map[string]string{"com.example.team": "payments"},
+					EventHandler: func(events.Event) {},
+					MetadataFile: metadataFile,
+				}))
+
+				h.AssertEq(t, fetcher.calls[builtImage.Name()], 1)
+				h.AssertEq(t, readMetadata().Digest, "sha256:"+strings.Repeat("b", 64))
+			})
+
+			it("errors when building for multiple platforms", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					Platforms:    []string{"linux/amd64", "linux/arm64"},
+					Publish:      true,
+					MetadataFile: metadataFile,
+				})
+				h.AssertError(t, err, "a metadata file cannot be used when building for multiple platforms")
+			})
+		})
+
+		when("ProjectDescriptor apps", func() {
+			var descriptor projectTypes.Descriptor
+
+			it.Before(func() {
+				for _, app := range []string{"api", "web", "worker"} {
+					h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "services", app), 0755))
+				}
+
+				descriptor = projectTypes.Descriptor{
+					Build: projectTypes.Build{
+						Env: []projectTypes.EnvVar{{Name: "SHARED", Value: "1"}},
+					},
+					Apps: []projectTypes.App{
+						{Name: "api", Path: filepath.Join("services", "api"), Image: "some/api"},
+						{Path: filepath.Join("services", "web"), Image: "some/web"},
+						{
+							Path:    filepath.Join("services", "worker"),
+							Image:   "some/worker",
+							Exclude: []string{"*.md"},
+							Env:     []projectTypes.EnvVar{{Name: "QUEUE", Value: "jobs"}},
+						},
+					},
+				}
+			})
+
+			it("builds each app to its own image", func() {
+				h.AssertNil(t, subject.BuildAll(context.TODO(), BuildOptions{
+					Builder:                  defaultBuilderName,
+					ProjectDescriptor:        descriptor,
+					ProjectDescriptorBaseDir: tmpDir,
+				}))
+
+				h.AssertEq(t, len(fakeLifecycle.Executions), 3)
+				for i, app := range []string{"api", "web", "worker"} {
+					execution := fakeLifecycle.Executions[i]
+					h.AssertEq(t, execution.Image.Name(), "index.docker.io/some/"+app+":latest")
+
+					appPath, err := filepath.EvalSymlinks(filepath.Join(tmpDir, "services", app))
+					h.AssertNil(t, err)
+					h.AssertEq(t, execution.AppPath, appPath)
+				}
+				h.AssertContains(t, outBuf.String(), "Building app 'api' (1/3)")
+				h.AssertContains(t, outBuf.String(), "Building app 'services/web' (2/3)")
+			})
+
+			it("shares the ephemeral builder between apps with the same buildpacks and env", func() {
+				h.AssertNil(t, subject.BuildAll(context.TODO(), BuildOptions{
+					Builder:                  defaultBuilderName,
+					ProjectDescriptor:        descriptor,
+					ProjectDescriptorBaseDir: tmpDir,
+				}))
+
+				api, web, worker := fakeLifecycle.Executions[0], fakeLifecycle.Executions[1], fakeLifecycle.Executions[2]
+				h.AssertTrue(t, api.Builder == web.Builder)
+				h.AssertTrue(t, api.Builder != worker.Builder)
+			})
+
+			it("pulls the builder image once", func() {
+				h.AssertNil(t, subject.BuildAll(context.TODO(), BuildOptions{
+					Builder:                  defaultBuilderName,
+					ProjectDescriptor:        descriptor,
+					ProjectDescriptorBaseDir: tmpDir,
+					PullPolicy:               image.PullAlways,
+				}))
+
+				// the builder image of the apps built after the first is read from the daemon
+				h.AssertEq(t, fakeImageFetcher.FetchCalls[defaultBuilderName].PullPolicy, image.PullNever)
+			})
+
+			it("applies the include and exclude rules of each app", func() {
+				h.AssertNil(t, subject.BuildAll(context.TODO(), BuildOptions{
+					Builder:                  defaultBuilderName,
+					ProjectDescriptor:        descriptor,
+					ProjectDescriptorBaseDir: tmpDir,
+				}))
+
+				h.AssertTrue(t, fakeLifecycle.Executions[0].FileFilter == nil)
+				h.AssertNotNil(t, fakeLifecycle.Executions[2].FileFilter)
+				h.AssertFalse(t, fakeLifecycle.Executions[2].FileFilter("README.md"))
+			})
+
+			it("stops at the first app that fails", func() {
+				descriptor.Apps[1].Path = "missing"
+
+				err := subject.BuildAll(context.TODO(), BuildOptions{
+					Builder:                  defaultBuilderName,
+					ProjectDescriptor:        descriptor,
+					ProjectDescriptorBaseDir: tmpDir,
+				})
+				h.AssertError(t, err, "building app 'missing'")
+				h.AssertEq(t, len(fakeLifecycle.Executions), 1)
+			})
+
+			it("errors without apps", func() {
+				err := subject.BuildAll(context.TODO(), BuildOptions{Builder: defaultBuilderName})
+				h.AssertError(t, err, "the project descriptor has no apps to build")
+			})
+
+			it("errors when an image is set", func() {
+				err := subject.BuildAll(context.TODO(), BuildOptions{
+					Image:             "some/app",
+					Builder:           defaultBuilderName,
+					ProjectDescriptor: descriptor,
+				})
+				h.AssertError(t, err, "an image cannot be set when building all apps")
+			})
+		})
+
+		when("PullPolicy", func() {
+			when("never", func() {
+				it("uses the local builder and run images without updating", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						PullPolicy: image.PullNever,
+					}))
+
+					args := fakeImageFetcher.FetchCalls["default/run"]
+					h.AssertEq(t, args.Daemon, true)
+					h.AssertEq(t, args.PullPolicy, image.PullNever)
+
+					args = fakeImageFetcher.FetchCalls[defaultBuilderName]
+					h.AssertEq(t, args.Daemon, true)
+					h.AssertEq(t, args.PullPolicy, image.PullNever)
+
+					args = fakeImageFetcher.FetchCalls["buildpacksio/lifecycle:0.13.3"]
+					h.AssertEq(t, args.Daemon, true)
+					h.AssertEq(t, args.PullPolicy, image.PullNever)
+					h.AssertEq(t, args.Platform, "linux/amd64")
+				})
+			})
+
+			when("always", func() {
+				it("uses pulls the builder and run image before using them", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						PullPolicy: image.PullAlways,
+					}))
+
+					args := fakeImageFetcher.FetchCalls["default/run"]
+					h.AssertEq(t, args.Daemon, true)
+					h.AssertEq(t, args.PullPolicy, image.PullAlways)
+
+					args = fakeImageFetcher.FetchCalls[defaultBuilderName]
+					h.AssertEq(t, args.Daemon, true)
+					h.AssertEq(t, args.PullPolicy, image.PullAlways)
+				})
+			})
+		})
+
+		when("ProxyConfig option", func() {
+			when("ProxyConfig is nil", func() {
+				it.Before(func() {
+					h.AssertNil(t, os.Setenv("http_proxy", "other-http-proxy"))
+					h.AssertNil(t, os.Setenv("https_proxy", "other-https-proxy"))
+					h.AssertNil(t, os.Setenv("no_proxy", "other-no-proxy"))
+				})
+
+				when("*_PROXY env vars are set", func() {
+					it.Before(func() {
+						h.AssertNil(t, os.Setenv("HTTP_PROXY", "some-http-proxy"))
+						h.AssertNil(t, os.Setenv("HTTPS_PROXY", "some-https-proxy"))
+						h.AssertNil(t, os.Setenv("NO_PROXY", "some-no-proxy"))
+					})
+
+					it.After(func() {
+						h.AssertNilE(t, os.Unsetenv("HTTP_PROXY"))
+						h.AssertNilE(t, os.Unsetenv("HTTPS_PROXY"))
+						h.AssertNilE(t, os.Unsetenv("NO_PROXY"))
+					})
+
+					it("defaults to the *_PROXY environment variables", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:   "some/app",
+							Builder: defaultBuilderName,
+						}))
+						h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "some-http-proxy")
+						h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "some-https-proxy")
+						h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "some-no-proxy")
+					})
+				})
+
+				it("falls back to the *_proxy environment variables", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "other-http-proxy")
+					h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "other-https-proxy")
+					h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "other-no-proxy")
+				})
+			}, spec.Sequential())
+
+			when("ProxyConfig is not nil", func() {
+				it("passes the values through", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						ProxyConfig: &ProxyConfig{
+							HTTPProxy:  "custom-http-proxy",
+							HTTPSProxy: "custom-https-proxy",
+							NoProxy:    "custom-no-proxy",
+						},
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "custom-http-proxy")
+					h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "custom-https-proxy")
+					h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "custom-no-proxy")
+				})
+			})
+		})
+
+		when("Network option", func() {
+			it("passes the value through", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						Network: "some-network",
+					},
 				}))
 				h.AssertEq(t, fakeLifecycle.Opts.Network, "some-network")
 			})
@@ -2448,41 +3126,384 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
-		when("gid option", func() {
-			it("gid is passthroughs to lifecycle", func() {
+		when("Timeout option", func() {
+			it("passes the phase timeouts to the lifecycle", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-					Workspace: "app",
-					Builder:   defaultBuilderName,
-					Image:     "example.com/some/repo:tag",
-					GroupID:   2,
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					PhaseTimeouts: map[string]time.Duration{"detect": 2 * time.Minute, "build": 20 * time.Minute},
 				}))
-				h.AssertEq(t, fakeLifecycle.Opts.GID, 2)
-			})
-		})
 
-		when("RegistryMirrors option", func() {
-			it("translates run image before passing to lifecycle", func() {
-				subject.registryMirrors = map[string]string{
-					"index.docker.io": "10.0.0.1",
-				}
+				h.AssertEq(t, fakeLifecycle.Opts.PhaseTimeouts, map[string]time.Duration{"detect": 2 * time.Minute, "build": 20 * time.Minute})
+			})
 
+			it("doesn't use the creator with timeouts of separate phases", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-					Builder: defaultBuilderName,
-					Image:   "example.com/some/repo:tag",
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					TrustBuilder:  func(string) bool { return true },
+					PhaseTimeouts: map[string]time.Duration{"create": 30 * time.Minute, "build": 20 * time.Minute},
 				}))
-				h.AssertEq(t, fakeLifecycle.Opts.RunImage, "10.0.0.1/default/run:latest")
+
+				h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
 			})
-		})
 
-		when("previous-image option", func() {
-			it("previous-image is passed to lifecycle", func() {
+			it("uses the creator with a timeout of the create phase", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-					Workspace:     "app",
+					Image:         "some/app",
 					Builder:       defaultBuilderName,
-					Image:         "example.com/some/repo:tag",
-					PreviousImage: "example.com/some/new:tag",
+					TrustBuilder:  func(string) bool { return true },
+					PhaseTimeouts: map[string]time.Duration{"create": 30 * time.Minute},
 				}))
-				h.AssertEq(t, fakeLifecycle.Opts.PreviousImage, "example.com/some/new:tag")
+
+				h.AssertEq(t, fakeLifecycle.Opts.UseCreator, true)
+			})
+
+			it("errors with an unknown phase", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					PhaseTimeouts: map[string]time.Duration{"compile": time.Minute},
+				})
+				h.AssertError(t, err, "unknown phase 'compile' in phase timeouts")
+			})
+
+			it("returns a timeout error when a phase times out", func() {
+				subject.lifecycleExecutor = lifecycleFunc(func(context.Context, build.LifecycleOptions) error {
+					return build.PhaseTimeoutError{Phase: "build", Timeout: 20 * time.Minute}
+				})
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					PhaseTimeouts: map[string]time.Duration{"build": 20 * time.Minute},
+				})
+				h.AssertEq(t, err, TimeoutError{Phase: "build", Timeout: 20 * time.Minute})
+				h.AssertError(t, err, "phase 'build' timed out after 20m0s")
+			})
+
+			it("returns a timeout error when the build times out", func() {
+				subject.lifecycleExecutor = lifecycleFunc(func(ctx context.Context, _ build.LifecycleOptions) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Timeout: 100 * time.Millisecond,
+				})
+				h.AssertEq(t, err, TimeoutError{Timeout: 100 * time.Millisecond})
+				h.AssertError(t, err, "build timed out after 100ms")
+			})
+		})
+
+		when("Labels option", func() {
+			var builtImage *fakes.Image
+
+			it.Before(func() {
+				builtImage = fakes.NewImage("index.docker.io/some/app:latest", "", nil)
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+				fakeImageFetcher.RemoteImages[builtImage.Name()] = builtImage
+			})
+
+			it.After(func() {
+				h.AssertNilE(t, builtImage.Cleanup())
+			})
+
+			assertLabel := func(key, expected string) {
+				t.Helper()
+				label, err := builtImage.Label(key)
+				h.AssertNil(t, err)
+				h.AssertEq(t, label, expected)
+			}
+
+			it("sets the labels on the app image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        defaultBuilderName,
+					AdditionalTags: []string{"some/app:v1"},
+					Labels:         map[string]string{"com.example.team": "payments"},
+				}))
+
+				assertLabel("com.example.team", "payments")
+				h.AssertTrue(t, builtImage.IsSaved())
+				h.AssertSliceContains(t, builtImage.SavedNames(), "some/app:v1")
+			})
+
+			it("sets the labels on the published image", func() {
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Publish: true,
+					Labels:  map[string]string{"com.example.team": "payments"},
+				}))
+
+				assertLabel("com.example.team", "payments")
+				args := fakeImageFetcher.FetchCalls[builtImage.Name()]
+				h.AssertFalse(t, args.Daemon)
+			})
+
+			it("sets the OCI labels derived from the project descriptor", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ProjectDescriptor: projectTypes.Descriptor{
+						Project: projectTypes.Project{
+							Version:   "1.2.3",
+							SourceURL: "https://github.com/example/app",
+							Licenses:  []projectTypes.License{{Type: "MIT"}, {Type: "Apache-2.0"}},
+						},
+					},
+				}))
+
+				h.AssertTrue(t, builtImage.IsSaved())
+				assertLabel("org.opencontainers.image.source", "https://github.com/example/app")
+				assertLabel("org.opencontainers.image.version", "1.2.3")
+				assertLabel("org.opencontainers.image.licenses", "MIT AND Apache-2.0")
+			})
+
+			it("replaces the labels of the project descriptor with the provided labels", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ProjectDescriptor: projectTypes.Descriptor{
+						Project: projectTypes.Project{Version: "1.2.3"},
+						Build: projectTypes.Build{
+							Labels: map[string]string{
+								"com.example.team":                 "billing",
+								"com.example.tier":                 "backend",
+								"org.opencontainers.image.version": "1.2.3-rc1",
+							},
+						},
+					},
+					Labels: map[string]string{"com.example.team": "payments"},
+				}))
+
+				assertLabel("com.example.team", "payments")
+				assertLabel("com.example.tier", "backend")
+				assertLabel("org.opencontainers.image.version", "1.2.3-rc1")
+			})
+
+			it("doesn't save the image when there are no labels", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					AppPath: tmpDir,
+				}))
+
+				h.AssertFalse(t, builtImage.IsSaved())
+			})
+
+			when("a label is reserved", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						Labels:  map[string]string{"io.buildpacks.build.metadata": "{}"},
+					})
+					h.AssertError(t, err, "label 'io.buildpacks.build.metadata' is reserved for buildpacks metadata")
+				})
+			})
+		})
+
+		when("resource limits are provided", func() {
+			it("passes them to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						Memory:    512 * 1024 * 1024,
+						CPUs:      1.5,
+						PidsLimit: 256,
+						Ulimits:   []string{"nofile=1024:2048"},
+					},
+				}))
+
+				resources := fakeLifecycle.Opts.Resources
+				h.AssertEq(t, resources.Memory, int64(512*1024*1024))
+				h.AssertEq(t, resources.NanoCPUs, int64(1500000000))
+				h.AssertEq(t, *resources.PidsLimit, int64(256))
+				h.AssertEq(t, len(resources.Ulimits), 1)
+				h.AssertEq(t, *resources.Ulimits[0], units.Ulimit{Name: "nofile", Soft: 1024, Hard: 2048})
+			})
+
+			it("doesn't limit the containers by default", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.Resources.Memory, int64(0))
+				h.AssertEq(t, fakeLifecycle.Opts.Resources.NanoCPUs, int64(0))
+				h.AssertNil(t, fakeLifecycle.Opts.Resources.PidsLimit)
+			})
+
+			when("a ulimit is invalid", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						ContainerConfig: ContainerConfig{
+							Ulimits: []string{"nofile"},
+						},
+					})
+					h.AssertError(t, err, "invalid ulimit 'nofile'")
+				})
+			})
+
+			when("a limit is negative", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						ContainerConfig: ContainerConfig{
+							CPUs: -1,
+						},
+					})
+					h.AssertError(t, err, "cpus limit must not be negative")
+				})
+			})
+		})
+
+		when("Secrets option", func() {
+			var secretFile string
+
+			it.Before(func() {
+				secretFile = filepath.Join(tmpDir, "npmrc")
+				h.AssertNil(t, ioutil.WriteFile(secretFile, []byte("some-token"), 0600))
+			})
+
+			it("passes the secrets to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Secrets: []Secret{{ID: "npm", Source: secretFile}},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Secrets, []build.Secret{{ID: "npm", Source: secretFile}})
+			})
+
+			it("reads the secrets of the project descriptor relative to its directory", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ProjectDescriptor: projectTypes.Descriptor{
+						Build: projectTypes.Build{
+							Secrets: []projectTypes.Secret{{ID: "npm", Source: "npmrc"}, {ID: "other", Source: "npmrc"}},
+						},
+					},
+					ProjectDescriptorBaseDir: tmpDir,
+					Secrets:                  []Secret{{ID: "other", Source: secretFile}},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Secrets, []build.Secret{
+					{ID: "npm", Source: secretFile},
+					{ID: "other", Source: secretFile},
+				})
+			})
+
+			it("doesn't use the creator", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					TrustBuilder: func(string) bool { return true },
+					Secrets:      []Secret{{ID: "npm", Source: secretFile}},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+			})
+
+			when("KeepOnFailure option", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:         "some/app",
+						Builder:       defaultBuilderName,
+						Secrets:       []Secret{{ID: "npm", Source: secretFile}},
+						KeepOnFailure: true,
+					})
+					h.AssertError(t, err, "failed containers can't be kept when building with secrets")
+				})
+			})
+
+			when("the secret file doesn't exist", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						Secrets: []Secret{{ID: "npm", Source: filepath.Join(tmpDir, "missing")}},
+					})
+					h.AssertError(t, err, "reading secret 'npm'")
+				})
+			})
+
+			when("the secret is a directory", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						Secrets: []Secret{{ID: "npm", Source: tmpDir}},
+					})
+					h.AssertError(t, err, "secret 'npm' must be a file")
+				})
+			})
+
+			when("the secret id is invalid", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						Secrets: []Secret{{ID: "../npm", Source: secretFile}},
+					})
+					h.AssertError(t, err, "secret id '../npm' is invalid")
+				})
+			})
+
+			when("the builder is a windows builder", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultWindowsBuilderName,
+						Secrets: []Secret{{ID: "npm", Source: secretFile}},
+					})
+					h.AssertError(t, err, "secrets are not supported for Windows builders")
+				})
+			})
+		})
+
+		when("gid option", func() {
+			it("gid is passthroughs to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Workspace: "app",
+					Builder:   defaultBuilderName,
+					Image:     "example.com/some/repo:tag",
+					GroupID:   2,
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.GID, 2)
+			})
+		})
+
+		when("RegistryMirrors option", func() {
+			it("translates run image before passing to lifecycle", func() {
+				subject.registryMirrors = map[string]string{
+					"index.docker.io": "10.0.0.1",
+				}
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: defaultBuilderName,
+					Image:   "example.com/some/repo:tag",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.RunImage, "10.0.0.1/default/run:latest")
+			})
+		})
+
+		when("previous-image option", func() {
+			it("previous-image is passed to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Workspace:     "app",
+					Builder:       defaultBuilderName,
+					Image:         "example.com/some/repo:tag",
+					PreviousImage: "example.com/some/new:tag",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.PreviousImage, "example.com/some/new:tag")
 			})
 		})
 
@@ -2507,6 +3528,341 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, fakeLifecycle.Opts.SBOMDestinationDir, "some-destination-dir")
 			})
 		})
+
+		when("event handler option", func() {
+			var (
+				builtImage     *fakes.Image
+				receivedEvents []events.Event
+				eventHandler   events.Handler
+			)
+
+			it.Before(func() {
+				receivedEvents = nil
+				eventHandler = func(e events.Event) {
+					receivedEvents = append(receivedEvents, e)
+				}
+
+				builtImage = fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{
+					ImageID: "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4",
+				})
+				h.AssertNil(t, builtImage.SetLabel(platform.LayerMetadataLabel, `{
+  "app": [{"sha": "sha256:app-layer"}],
+  "buildpacks": [{"key": "some/bp", "version": "1.2.3", "layers": {"b-layer": {"sha": "sha256:b-layer"}, "a-layer": {"sha": "sha256:a-layer"}}}]
+}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+			})
+
+			it.After(func() {
+				h.AssertNilE(t, builtImage.Cleanup())
+			})
+
+			it("passthroughs to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "some/app",
+					EventHandler: eventHandler,
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.EventHandler)
+			})
+
+			it("emits an event for each exported layer and the image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "some/app",
+					EventHandler: eventHandler,
+				}))
+
+				h.AssertEq(t, len(receivedEvents), 4)
+				h.AssertEq(t, receivedEvents[0].Type, events.LayerExported)
+				h.AssertEq(t, receivedEvents[0].Layer, "a-layer")
+				h.AssertEq(t, receivedEvents[0].Digest, "sha256:a-layer")
+				h.AssertEq(t, receivedEvents[0].Buildpack.ID, "some/bp")
+				h.AssertEq(t, receivedEvents[1].Layer, "b-layer")
+				h.AssertEq(t, receivedEvents[2].Layer, "app")
+				h.AssertEq(t, receivedEvents[2].Digest, "sha256:app-layer")
+				h.AssertEq(t, receivedEvents[3].Type, events.ImageExported)
+				h.AssertEq(t, receivedEvents[3].Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, receivedEvents[3].Digest, "sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+			})
+
+			it("doesn't fail the build when the exported image can't be described", func() {
+				delete(fakeImageFetcher.LocalImages, builtImage.Name())
+				fakeLifecycle.OnExecute = nil
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "some/app",
+					AppPath:      tmpDir,
+					EventHandler: eventHandler,
+				}))
+
+				h.AssertEq(t, len(receivedEvents), 0)
+				h.AssertContains(t, outBuf.String(), "Not emitting export events")
+			})
+
+			it("doesn't verify the signature of the exported image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					Image:        "some/app",
+					EventHandler: eventHandler,
+				}))
+
+				h.AssertEq(t, fakeImageFetcher.FetchCalls[builtImage.Name()].SkipVerification, true)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls[defaultBuilderName].SkipVerification, false)
+			})
+		})
+	})
+
+	when("#BuildWithResult", func() {
+		it("returns the metadata of the app image and the phases run", func() {
+			builtImage := fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{ImageID: "sha256:" + strings.Repeat("b", 64)})
+			h.AssertNil(t, builtImage.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage": {"reference": "sha256:run-image"}, "stack": {"runImage": {"image": "default/run"}}}`))
+			h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{
+  "buildpacks": [{"id": "some/bp", "version": "1.2.3"}],
+  "processes": [{"type": "web", "command": "bundle", "args": ["exec", "rackup"]}]
+}`))
+			fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+
+			subject.lifecycleExecutor = lifecycleFunc(func(_ context.Context, opts build.LifecycleOptions) error {
+				opts.EventHandler(events.Event{Type: events.PhaseStarted, Phase: "detector"})
+				opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "detector", Duration: time.Second})
+				opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "builder", Duration: 2 * time.Second})
+				return nil
+			})
+
+			var receivedEvents []events.Event
+			result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+				Image:          "some/app",
+				Builder:        defaultBuilderName,
+				AdditionalTags: []string{"some/app:v1"},
+				EventHandler: func(e events.Event) {
+					receivedEvents = append(receivedEvents, e)
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, result.ImageMetadata, ImageMetadata{
+				Image:          "index.docker.io/some/app:latest",
+				Digest:         "sha256:" + strings.Repeat("b", 64),
+				AdditionalTags: []string{"some/app:v1"},
+				RunImage:       RunImageMetadata{Image: "default/run", Reference: "sha256:run-image"},
+				Buildpacks:     []BuildpackMetadata{{ID: "some/bp", Version: "1.2.3"}},
+				Processes:      []ProcessMetadata{{Type: "web", Command: "bundle", Args: []string{"exec", "rackup"}}},
+			})
+			h.AssertEq(t, result.Phases, []PhaseReport{
+				{Name: "detector", Duration: time.Second},
+				{Name: "builder", Duration: 2 * time.Second},
+			})
+			h.AssertFalse(t, result.StartedAt.IsZero())
+			h.AssertTrue(t, result.Duration > 0)
+
+			h.AssertTrue(t, len(receivedEvents) >= 3)
+			h.AssertEq(t, receivedEvents[0].Phase, "detector")
+		})
+
+		it("returns the error of a failed build", func() {
+			subject.lifecycleExecutor = lifecycleFunc(func(context.Context, build.LifecycleOptions) error {
+				return errors.New("some-error")
+			})
+
+			_, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+				Image:   "some/app",
+				Builder: defaultBuilderName,
+			})
+			h.AssertError(t, err, "some-error")
+		})
+
+		it("errors when building for multiple platforms", func() {
+			_, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+				Image:     "some/app",
+				Builder:   defaultBuilderName,
+				Platforms: []string{"linux/amd64", "linux/arm64"},
+				Publish:   true,
+			})
+			h.AssertError(t, err, "a build result cannot be returned when building for multiple platforms")
+		})
+	})
+
+	when("#BuildMany", func() {
+		var appDir string
+
+		it.Before(func() {
+			appDir = filepath.Join(tmpDir, "app")
+			h.AssertNil(t, os.MkdirAll(appDir, 0755))
+		})
+
+		it("builds each image", func() {
+			h.AssertNil(t, subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/api", Builder: defaultBuilderName, AppPath: appDir},
+					{Image: "some/web", Builder: defaultBuilderName, AppPath: appDir},
+				},
+			}))
+
+			h.AssertEq(t, len(fakeLifecycle.Executions), 2)
+			h.AssertEq(t, fakeLifecycle.Executions[0].Image.Name(), "index.docker.io/some/api:latest")
+			h.AssertEq(t, fakeLifecycle.Executions[1].Image.Name(), "index.docker.io/some/web:latest")
+			h.AssertContains(t, outBuf.String(), "Building image 'some/api' (1/2)")
+			h.AssertContains(t, outBuf.String(), "Building image 'some/web' (2/2)")
+		})
+
+		it("shares the ephemeral builder between builds with the same buildpacks and env", func() {
+			h.AssertNil(t, subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/api", Builder: defaultBuilderName, AppPath: appDir},
+					{Image: "some/web", Builder: defaultBuilderName, AppPath: appDir},
+					{Image: "some/worker", Builder: defaultBuilderName, AppPath: appDir, Env: map[string]string{"QUEUE": "jobs"}},
+				},
+			}))
+
+			api, web, worker := fakeLifecycle.Executions[0], fakeLifecycle.Executions[1], fakeLifecycle.Executions[2]
+			h.AssertTrue(t, api.Builder == web.Builder)
+			h.AssertTrue(t, api.Builder != worker.Builder)
+		})
+
+		it("pulls the builder image once", func() {
+			h.AssertNil(t, subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/api", Builder: defaultBuilderName, AppPath: appDir, PullPolicy: image.PullAlways},
+					{Image: "some/web", Builder: defaultBuilderName, AppPath: appDir, PullPolicy: image.PullAlways},
+				},
+			}))
+
+			// the builder image of the second build is read from the daemon
+			h.AssertEq(t, fakeImageFetcher.FetchCalls[defaultBuilderName].PullPolicy, image.PullNever)
+		})
+
+		it("runs up to Parallel builds at the same time", func() {
+			lifecycle := &concurrentLifecycle{}
+			subject.lifecycleExecutor = lifecycle
+
+			var builds []BuildOptions
+			for i := 0; i < 3; i++ {
+				// fake images are shared by every fetch, so each build gets its own builder image
+				builderName := fmt.Sprintf("example.com/builder-%d:tag", i)
+				builderImage := newFakeBuilderImage(t, tmpDir, builderName, defaultBuilderStackID, defaultRunImageName, builder.DefaultLifecycleVersion, newLinuxImage)
+				h.AssertNil(t, builderImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "build:mixinB", "mixinX", "build:mixinY"]`))
+				fakeImageFetcher.LocalImages[builderName] = builderImage
+				builds = append(builds, BuildOptions{Image: fmt.Sprintf("some/app-%d", i), Builder: builderName, AppPath: appDir})
+			}
+
+			h.AssertNil(t, subject.BuildMany(context.TODO(), BuildManyOptions{Builds: builds, Parallel: 2}))
+			h.AssertEq(t, lifecycle.max, 2)
+		})
+
+		it("runs every build and reports those that failed", func() {
+			err := subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/api", Builder: defaultBuilderName, AppPath: filepath.Join(tmpDir, "missing")},
+					{Image: "some/web", Builder: defaultBuilderName, AppPath: appDir},
+				},
+			})
+
+			h.AssertError(t, err, "1 of 2 builds failed: 'some/api': ")
+			buildErr, ok := err.(BuildManyError)
+			h.AssertTrue(t, ok)
+			h.AssertEq(t, buildErr.Failures[0].Image, "some/api")
+			h.AssertEq(t, len(fakeLifecycle.Executions), 1)
+		})
+
+		it("errors without builds", func() {
+			err := subject.BuildMany(context.TODO(), BuildManyOptions{})
+			h.AssertError(t, err, "no builds to run")
+		})
+
+		it("errors when an image is built more than once", func() {
+			err := subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/app", Builder: defaultBuilderName, AppPath: appDir},
+					{Image: "some/app", Builder: defaultBuilderName, AppPath: appDir},
+				},
+			})
+			h.AssertError(t, err, "image 'some/app' is built more than once")
+		})
+
+		it("errors when interactive builds run in parallel", func() {
+			err := subject.BuildMany(context.TODO(), BuildManyOptions{
+				Builds: []BuildOptions{
+					{Image: "some/api", Builder: defaultBuilderName, AppPath: appDir, Interactive: true},
+					{Image: "some/web", Builder: defaultBuilderName, AppPath: appDir},
+				},
+				Parallel: 2,
+			})
+			h.AssertError(t, err, "interactive builds cannot be run in parallel")
+		})
+	})
+
+	when("#Detect", func() {
+		var detected build.DetectResult
+
+		it.Before(func() {
+			detected = build.DetectResult{
+				Group: lifecycleBuildpack.Group{Group: []lifecycleBuildpack.GroupBuildpack{
+					{ID: "some/bp", Version: "1.2.3"},
+				}},
+				Plan: platform.BuildPlan{Entries: []platform.BuildPlanEntry{{
+					Providers: []lifecycleBuildpack.GroupBuildpack{{ID: "some/bp", Version: "1.2.3"}},
+					Requires:  []lifecycleBuildpack.Require{{Name: "some-dep", Version: "4.5.6"}},
+				}}},
+			}
+			fakeLifecycle.DetectResult = detected
+		})
+
+		it("returns the group and plan resolved by the lifecycle", func() {
+			result, err := subject.Detect(context.TODO(), BuildOptions{
+				Builder: defaultBuilderName,
+				Image:   "some/app",
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, fakeLifecycle.Opts.DetectOnly, true)
+			h.AssertEq(t, result.Group, detected.Group.Group)
+			h.AssertEq(t, result.Plan, detected.Plan)
+		})
+
+		it("runs the phases separately for trusted builders", func() {
+			_, err := subject.Detect(context.TODO(), BuildOptions{
+				Builder:      defaultBuilderName,
+				Image:        "some/app",
+				TrustBuilder: func(string) bool { return true },
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, fakeLifecycle.Opts.DetectOnly, true)
+			h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+		})
+
+		it("doesn't process an exported image", func() {
+			var receivedEvents []events.Event
+			_, err := subject.Detect(context.TODO(), BuildOptions{
+				Builder: defaultBuilderName,
+				Image:   "some/app",
+				EventHandler: func(e events.Event) {
+					receivedEvents = append(receivedEvents, e)
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(receivedEvents), 0)
+		})
+
+		it("fails for multiple platforms", func() {
+			_, err := subject.Detect(context.TODO(), BuildOptions{
+				Builder:   defaultBuilderName,
+				Image:     "some/app",
+				Platforms: []string{"linux/amd64", "linux/arm64"},
+			})
+			h.AssertError(t, err, "detection cannot be run for multiple platforms")
+		})
+
+		it("fails for interactive builds", func() {
+			_, err := subject.Detect(context.TODO(), BuildOptions{
+				Builder:     defaultBuilderName,
+				Image:       "some/app",
+				Interactive: true,
+			})
+			h.AssertError(t, err, "detection cannot be run interactively")
+		})
 	})
 }
 
@@ -2623,3 +3979,47 @@ func (f *executeFailsLifecycle) Execute(_ context.Context, opts build.LifecycleO
 	f.Opts = opts
 	return errors.New("")
 }
+
+// lifecycleFunc is a lifecycle executor running the function.
+type lifecycleFunc func(ctx context.Context, opts build.LifecycleOptions) error
+
+func (f lifecycleFunc) Execute(ctx context.Context, opts build.LifecycleOptions) error {
+	return f(ctx, opts)
+}
+
+// concurrentLifecycle records the greatest number of executions running at the same time.
+type concurrentLifecycle struct {
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (f *concurrentLifecycle) Execute(_ context.Context, _ build.LifecycleOptions) error {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.max {
+		f.max = f.running
+	}
+	f.mu.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	return nil
+}
+
+// countingImageFetcher counts the fetches of each image.
+type countingImageFetcher struct {
+	*ifakes.FakeImageFetcher
+	calls map[string]int
+	mu    sync.Mutex
+}
+
+func (f *countingImageFetcher) Fetch(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+	f.mu.Lock()
+	f.calls[name]++
+	f.mu.Unlock()
+	return f.FakeImageFetcher.Fetch(ctx, name, options)
+}
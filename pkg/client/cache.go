@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/style"
+	pcache "github.com/buildpacks/pack/pkg/cache"
+)
+
+const (
+	cacheVolumePrefix = "pack-cache-"
+	buildCacheType    = "build"
+	launchCacheType   = "launch"
+)
+
+// CacheVolume describes a cache volume created by pack for an app image.
+type CacheVolume struct {
+	// Name of the volume.
+	Name string
+
+	// Image is the app image the cache was created for.
+	// It is empty when the volume was not used by a build since pack started recording cache usage.
+	Image string
+
+	// Type is either `build` or `launch`.
+	Type string
+
+	// Size in bytes of the volume contents, or -1 when the daemon doesn't report it.
+	Size int64
+
+	// CreatedAt is when the volume was created.
+	CreatedAt time.Time
+
+	// LastUsed is when the volume was last used by a build.
+	// It is zero when the volume was not used by a build since pack started recording cache usage.
+	LastUsed time.Time
+}
+
+// PruneCachesOptions selects the cache volumes to remove.
+// When no option is set all cache volumes are removed.
+type PruneCachesOptions struct {
+	// OlderThan removes volumes that were last used, or created when the last use is unknown, before this duration ago.
+	OlderThan time.Duration
+
+	// Image removes the volumes of this app image.
+	Image string
+
+	// DryRun reports the volumes that would be removed without removing them.
+	DryRun bool
+}
+
+// ListCaches returns the cache volumes created by pack, sorted by name.
+func (c *Client) ListCaches(ctx context.Context) ([]CacheVolume, error) {
+	list, err := c.engine.VolumeList(ctx, filters.NewArgs(filters.Arg("name", cacheVolumePrefix)))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing volumes")
+	}
+
+	usage, err := c.cacheUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := c.volumeSizes(ctx)
+
+	var volumes []CacheVolume
+	for _, vol := range list.Volumes {
+		// the name filter matches substrings, keep only the volumes named by pack
+		if !strings.HasPrefix(vol.Name, cacheVolumePrefix) {
+			continue
+		}
+
+		volume := CacheVolume{
+			Name: vol.Name,
+			Type: cacheTypeFromVolumeName(vol.Name),
+			Size: -1,
+		}
+		if size, ok := sizes[vol.Name]; ok {
+			volume.Size = size
+		}
+		if createdAt, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+			volume.CreatedAt = createdAt
+		}
+		if entry, ok := usage[vol.Name]; ok {
+			volume.Image = entry.Image
+			volume.LastUsed = entry.LastUsed
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes, nil
+}
+
+// InspectCache returns the cache volumes of an app image.
+func (c *Client) InspectCache(ctx context.Context, imageName string) ([]CacheVolume, error) {
+	names, err := c.cacheVolumeNames(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := c.ListCaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var imageVolumes []CacheVolume
+	for _, volume := range volumes {
+		if !names[volume.Name] {
+			continue
+		}
+
+		if volume.Image == "" {
+			volume.Image = imageName
+		}
+		imageVolumes = append(imageVolumes, volume)
+	}
+
+	if len(imageVolumes) == 0 {
+		return nil, errors.Errorf("no cache volumes found for image %s", style.Symbol(imageName))
+	}
+	return imageVolumes, nil
+}
+
+// PruneCaches removes the cache volumes selected by opts and returns them.
+// Volumes that are in use by a container are skipped.
+func (c *Client) PruneCaches(ctx context.Context, opts PruneCachesOptions) ([]CacheVolume, error) {
+	var imageVolumeNames map[string]bool
+	if opts.Image != "" {
+		var err error
+		imageVolumeNames, err = c.cacheVolumeNames(opts.Image)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	volumes, err := c.ListCaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+
+	var pruned []CacheVolume
+	for _, volume := range volumes {
+		if imageVolumeNames != nil && !imageVolumeNames[volume.Name] {
+			continue
+		}
+
+		if opts.OlderThan > 0 {
+			lastUsed := volume.LastUsed
+			if lastUsed.IsZero() {
+				lastUsed = volume.CreatedAt
+			}
+			if lastUsed.After(cutoff) {
+				continue
+			}
+		}
+
+		if !opts.DryRun {
+			if err := c.engine.VolumeRemove(ctx, volume.Name, false); err != nil {
+				c.logger.Warnf("Skipping cache volume %s: %s", style.Symbol(volume.Name), err)
+				continue
+			}
+		}
+		pruned = append(pruned, volume)
+	}
+
+	if !opts.DryRun && len(pruned) > 0 && c.cacheUsageLog != nil {
+		var names []string
+		for _, volume := range pruned {
+			names = append(names, volume.Name)
+		}
+		if err := c.cacheUsageLog.Remove(names...); err != nil {
+			return pruned, err
+		}
+	}
+
+	return pruned, nil
+}
+
+// recordCacheUsage records that the volume caches of the build are being used.
+// Failing to record usage doesn't fail the build, it only affects the output of the cache commands.
+func (c *Client) recordCacheUsage(imageRef name.Reference, opts BuildOptions) {
+	if c.cacheUsageLog == nil {
+		return
+	}
+
+	var volumes []*cache.VolumeCache
+	if opts.CacheImage == "" && opts.Cache.Build.Format == pcache.CacheVolume {
+		volumes = append(volumes, cache.NewVolumeCache(imageRef, buildCacheType, c.engine))
+	}
+	if !opts.Publish && opts.Cache.Launch.Format == pcache.CacheVolume {
+		volumes = append(volumes, cache.NewVolumeCache(imageRef, launchCacheType, c.engine))
+	}
+
+	now := time.Now()
+	for _, volume := range volumes {
+		entry := cache.UsageEntry{
+			Image:    imageRef.Name(),
+			Type:     cacheTypeFromVolumeName(volume.Name()),
+			LastUsed: now,
+		}
+		if err := c.cacheUsageLog.Record(volume.Name(), entry); err != nil {
+			c.logger.Debugf("Unable to record usage of cache %s: %s", style.Symbol(volume.Name()), err)
+		}
+	}
+}
+
+func (c *Client) cacheUsage() (map[string]cache.UsageEntry, error) {
+	if c.cacheUsageLog == nil {
+		return map[string]cache.UsageEntry{}, nil
+	}
+	return c.cacheUsageLog.Entries()
+}
+
+func (c *Client) cacheVolumeNames(imageName string) (map[string]bool, error) {
+	imageRef, err := c.parseTagReference(imageName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", imageName)
+	}
+
+	return map[string]bool{
+		cache.NewVolumeCache(imageRef, buildCacheType, c.engine).Name():  true,
+		cache.NewVolumeCache(imageRef, launchCacheType, c.engine).Name(): true,
+	}, nil
+}
+
+// volumeSizes returns the size of each volume reported by the daemon.
+// Computing disk usage is best effort, as some daemons don't support it.
+func (c *Client) volumeSizes(ctx context.Context) map[string]int64 {
+	sizes := map[string]int64{}
+
+	usage, err := c.engine.DiskUsage(ctx)
+	if err != nil {
+		c.logger.Debugf("Unable to get volume sizes: %s", err)
+		return sizes
+	}
+
+	for _, vol := range usage.Volumes {
+		if vol.UsageData != nil && vol.UsageData.Size >= 0 {
+			sizes[vol.Name] = vol.UsageData.Size
+		}
+	}
+	return sizes
+}
+
+func cacheTypeFromVolumeName(volumeName string) string {
+	switch {
+	case strings.HasSuffix(volumeName, "."+buildCacheType):
+		return buildCacheType
+	case strings.HasSuffix(volumeName, "."+launchCacheType):
+		return launchCacheType
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Cache", testCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		usageLog         *cache.UsageLog
+		out              bytes.Buffer
+		tmpDir           string
+
+		appBuildVolume, appLaunchVolume, otherBuildVolume string
+		lastUsed                                          = time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+		createdAt                                         = time.Now().Add(-72 * time.Hour).UTC().Truncate(time.Second)
+	)
+
+	volumeNameFor := func(imageName, suffix string) string {
+		ref, err := name.ParseReference(imageName, name.WeakValidation)
+		h.AssertNil(t, err)
+		return cache.NewVolumeCache(ref, suffix, nil).Name()
+	}
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cache-test")
+		h.AssertNil(t, err)
+
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+		usageLog = cache.NewUsageLog(filepath.Join(tmpDir, "cache-usage.json"))
+		subject = &Client{
+			logger:        logging.NewLogWithWriters(&out, &out),
+			engine:        engine.NewDocker(mockDockerClient),
+			cacheUsageLog: usageLog,
+		}
+
+		appBuildVolume = volumeNameFor("some/app", "build")
+		appLaunchVolume = volumeNameFor("some/app", "launch")
+		otherBuildVolume = volumeNameFor("other/app", "build")
+
+		h.AssertNil(t, usageLog.Record(appBuildVolume, cache.UsageEntry{Image: "index.docker.io/some/app:latest", Type: "build", LastUsed: lastUsed}))
+		h.AssertNil(t, usageLog.Record(appLaunchVolume, cache.UsageEntry{Image: "index.docker.io/some/app:latest", Type: "launch", LastUsed: time.Now()}))
+
+		mockDockerClient.EXPECT().
+			VolumeList(gomock.Any(), filters.NewArgs(filters.Arg("name", "pack-cache-"))).
+			Return(volume.VolumeListOKBody{Volumes: []*types.Volume{
+				{Name: appBuildVolume, CreatedAt: createdAt.Format(time.RFC3339)},
+				{Name: appLaunchVolume, CreatedAt: createdAt.Format(time.RFC3339)},
+				{Name: otherBuildVolume, CreatedAt: createdAt.Format(time.RFC3339)},
+				{Name: "some-volume-with-pack-cache-in-its-name"},
+			}}, nil).
+			AnyTimes()
+		mockDockerClient.EXPECT().
+			DiskUsage(gomock.Any()).
+			Return(types.DiskUsage{Volumes: []*types.Volume{
+				{Name: appBuildVolume, UsageData: &types.VolumeUsageData{Size: 1024}},
+				{Name: appLaunchVolume, UsageData: &types.VolumeUsageData{Size: -1}},
+			}}, nil).
+			AnyTimes()
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#ListCaches", func() {
+		it("lists the volumes created by pack", func() {
+			volumes, err := subject.ListCaches(context.TODO())
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(volumes), 3)
+			for _, vol := range volumes {
+				switch vol.Name {
+				case appBuildVolume:
+					h.AssertEq(t, vol, CacheVolume{
+						Name:      appBuildVolume,
+						Image:     "index.docker.io/some/app:latest",
+						Type:      "build",
+						Size:      1024,
+						CreatedAt: createdAt,
+						LastUsed:  lastUsed,
+					})
+				case appLaunchVolume:
+					h.AssertEq(t, vol.Type, "launch")
+					h.AssertEq(t, vol.Size, int64(-1))
+				case otherBuildVolume:
+					h.AssertEq(t, vol.Image, "")
+					h.AssertEq(t, vol.Type, "build")
+					h.AssertEq(t, vol.Size, int64(-1))
+					h.AssertTrue(t, vol.LastUsed.IsZero())
+				default:
+					t.Fatalf("unexpected volume %s", vol.Name)
+				}
+			}
+		})
+
+		when("disk usage is not available", func() {
+			it("reports unknown sizes", func() {
+				mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+				subject.engine = engine.NewDocker(mockDockerClient)
+				mockDockerClient.EXPECT().
+					VolumeList(gomock.Any(), gomock.Any()).
+					Return(volume.VolumeListOKBody{Volumes: []*types.Volume{{Name: appBuildVolume}}}, nil)
+				mockDockerClient.EXPECT().
+					DiskUsage(gomock.Any()).
+					Return(types.DiskUsage{}, errors.New("not supported"))
+
+				volumes, err := subject.ListCaches(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(volumes), 1)
+				h.AssertEq(t, volumes[0].Size, int64(-1))
+			})
+		})
+	})
+
+	when("#InspectCache", func() {
+		it("returns the volumes of the image", func() {
+			volumes, err := subject.InspectCache(context.TODO(), "some/app")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(volumes), 2)
+			h.AssertEq(t, volumes[0].Image, "index.docker.io/some/app:latest")
+			h.AssertEq(t, volumes[1].Image, "index.docker.io/some/app:latest")
+		})
+
+		it("uses the image name when usage was not recorded", func() {
+			volumes, err := subject.InspectCache(context.TODO(), "other/app")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(volumes), 1)
+			h.AssertEq(t, volumes[0].Name, otherBuildVolume)
+			h.AssertEq(t, volumes[0].Image, "other/app")
+		})
+
+		it("fails when the image has no caches", func() {
+			_, err := subject.InspectCache(context.TODO(), "missing/app")
+			h.AssertError(t, err, "no cache volumes found for image 'missing/app'")
+		})
+	})
+
+	when("#PruneCaches", func() {
+		it("removes the volumes of the image", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, false).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appLaunchVolume, false).Return(nil)
+
+			pruned, err := subject.PruneCaches(context.TODO(), PruneCachesOptions{Image: "some/app"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(pruned), 2)
+
+			entries, err := usageLog.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 0)
+		})
+
+		it("removes the volumes last used before the given duration", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, false).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), otherBuildVolume, false).Return(nil)
+
+			pruned, err := subject.PruneCaches(context.TODO(), PruneCachesOptions{OlderThan: 24 * time.Hour})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(pruned), 2)
+			h.AssertEq(t, pruned[0].Name, otherBuildVolume)
+			h.AssertEq(t, pruned[1].Name, appBuildVolume)
+
+			entries, err := usageLog.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 1)
+			h.AssertEq(t, entries[appLaunchVolume].Type, "launch")
+		})
+
+		it("combines the image and the duration", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, false).Return(nil)
+
+			pruned, err := subject.PruneCaches(context.TODO(), PruneCachesOptions{Image: "some/app", OlderThan: 24 * time.Hour})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(pruned), 1)
+		})
+
+		it("skips volumes that can't be removed", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, false).Return(errors.New("volume is in use"))
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appLaunchVolume, false).Return(nil)
+
+			pruned, err := subject.PruneCaches(context.TODO(), PruneCachesOptions{Image: "some/app"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(pruned), 1)
+			h.AssertEq(t, pruned[0].Name, appLaunchVolume)
+			h.AssertContains(t, out.String(), "volume is in use")
+		})
+
+		it("doesn't remove volumes in a dry run", func() {
+			pruned, err := subject.PruneCaches(context.TODO(), PruneCachesOptions{Image: "some/app", DryRun: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(pruned), 2)
+
+			entries, err := usageLog.Entries()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 2)
+		})
+	})
+}
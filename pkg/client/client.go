@@ -18,6 +18,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/imgutil/local"
@@ -28,10 +29,12 @@ import (
 
 	"github.com/buildpacks/pack"
 	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/cache"
 	iconfig "github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/engine"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 )
@@ -85,7 +88,7 @@ type BuildpackDownloader interface {
 // All settings on this object should be changed through ClientOption functions.
 type Client struct {
 	logger logging.Logger
-	docker dockerClient.CommonAPIClient
+	engine engine.ContainerEngine
 
 	keychain            authn.Keychain
 	imageFactory        ImageFactory
@@ -93,10 +96,15 @@ type Client struct {
 	downloader          BlobDownloader
 	lifecycleExecutor   LifecycleExecutor
 	buildpackDownloader BuildpackDownloader
+	cacheUsageLog       *cache.UsageLog
+	imageVerifier       image.Verifier
 
 	experimental    bool
 	registryMirrors map[string]string
 	version         string
+
+	// session is set on the copies of the client running several builds, see withSession.
+	session *buildSession
 }
 
 // Option is a type of function that mutate settings on the client.
@@ -153,7 +161,17 @@ func WithCacheDir(path string) Option {
 // WithDockerClient supply your own docker client.
 func WithDockerClient(docker dockerClient.CommonAPIClient) Option {
 	return func(c *Client) {
-		c.docker = docker
+		if docker != nil {
+			c.engine = engine.NewDocker(docker)
+		}
+	}
+}
+
+// WithContainerEngine supply your own container engine, such as Podman.
+// It replaces any docker client supplied with WithDockerClient.
+func WithContainerEngine(containerEngine engine.ContainerEngine) Option {
+	return func(c *Client) {
+		c.engine = containerEngine
 	}
 }
 
@@ -171,6 +189,22 @@ func WithRegistryMirrors(registryMirrors map[string]string) Option {
 	}
 }
 
+// WithImageVerifier sets a verifier for the signatures of the images that are fetched.
+// It is ignored when a Fetcher is supplied with WithFetcher.
+func WithImageVerifier(verifier image.Verifier) Option {
+	return func(c *Client) {
+		c.imageVerifier = verifier
+	}
+}
+
+// WithCacheUsageLog records the cache volumes used by builds, and when they were last used, in a file at path.
+// Without it, the app images of listed caches are unknown, and caches are pruned by the creation time of their volume.
+func WithCacheUsageLog(path string) Option {
+	return func(c *Client) {
+		c.cacheUsageLog = cache.NewUsageLog(path)
+	}
+}
+
 // WithKeychain sets keychain of credentials to image registries
 func WithKeychain(keychain authn.Keychain) Option {
 	return func(c *Client) {
@@ -195,15 +229,15 @@ func NewClient(opts ...Option) (*Client, error) {
 		client.logger = logging.NewSimpleLogger(os.Stderr)
 	}
 
-	if client.docker == nil {
-		var err error
-		client.docker, err = dockerClient.NewClientWithOpts(
+	if client.engine == nil {
+		docker, err := dockerClient.NewClientWithOpts(
 			dockerClient.FromEnv,
 			dockerClient.WithVersion(DockerAPIVersion),
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "creating docker client")
 		}
+		client.engine = engine.NewDocker(docker)
 	}
 
 	if client.downloader == nil {
@@ -215,12 +249,16 @@ func NewClient(opts ...Option) (*Client, error) {
 	}
 
 	if client.imageFetcher == nil {
-		client.imageFetcher = image.NewFetcher(client.logger, client.docker, image.WithRegistryMirrors(client.registryMirrors))
+		fetcherOpts := []image.FetcherOption{image.WithRegistryMirrors(client.registryMirrors)}
+		if client.imageVerifier != nil {
+			fetcherOpts = append(fetcherOpts, image.WithVerifier(client.imageVerifier))
+		}
+		client.imageFetcher = image.NewFetcher(client.logger, client.engine.APIClient(), fetcherOpts...)
 	}
 
 	if client.imageFactory == nil {
 		client.imageFactory = &imageFactory{
-			dockerClient: client.docker,
+			dockerClient: client.engine.APIClient(),
 			keychain:     client.keychain,
 		}
 	}
@@ -236,16 +274,22 @@ func NewClient(opts ...Option) (*Client, error) {
 		)
 	}
 
-	client.lifecycleExecutor = build.NewLifecycleExecutor(client.logger, client.docker)
+	client.lifecycleExecutor = build.NewLifecycleExecutor(client.logger, client.engine)
 
 	return client, nil
 }
 
 type registryResolver struct {
 	logger logging.Logger
+
+	// mu serializes the refreshes of the registry cache by builds running in parallel, such as those of BuildMany.
+	mu sync.Mutex
 }
 
 func (r *registryResolver) Resolve(registryName, bpName string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	cache, err := getRegistry(r.logger, registryName)
 	if err != nil {
 		return "", errors.Wrapf(err, "lookup registry %s", style.Symbol(registryName))
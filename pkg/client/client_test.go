@@ -10,6 +10,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/pkg/engine"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/testmocks"
 	h "github.com/buildpacks/pack/testhelpers"
@@ -93,7 +94,20 @@ func testClient(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, err)
 			cl, err := NewClient(WithDockerClient(docker))
 			h.AssertNil(t, err)
-			h.AssertSameInstance(t, cl.docker, docker)
+			h.AssertSameInstance(t, cl.engine.APIClient(), docker)
+		})
+	})
+
+	when("#WithContainerEngine", func() {
+		it("uses container engine provided", func() {
+			docker, err := dockerClient.NewClientWithOpts(
+				dockerClient.FromEnv,
+			)
+			h.AssertNil(t, err)
+			containerEngine := engine.NewDocker(docker)
+			cl, err := NewClient(WithContainerEngine(containerEngine))
+			h.AssertNil(t, err)
+			h.AssertSameInstance(t, cl.engine, containerEngine)
 		})
 	})
 
@@ -111,6 +125,20 @@ func testClient(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#WithCacheUsageLog", func() {
+		it("records cache usage in the file provided", func() {
+			cl, err := NewClient(WithCacheUsageLog("/some/cache-usage.json"))
+			h.AssertNil(t, err)
+			h.AssertNotNil(t, cl.cacheUsageLog)
+		})
+
+		it("doesn't record cache usage by default", func() {
+			cl, err := NewClient()
+			h.AssertNil(t, err)
+			h.AssertNil(t, cl.cacheUsageLog)
+		})
+	})
+
 	when("#WithRegistryMirror", func() {
 		it("uses registry mirrors provided", func() {
 			registryMirrors := map[string]string{
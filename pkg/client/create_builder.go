@@ -37,6 +37,9 @@ type CreateBuilderOptions struct {
 
 	// Strategy for updating images before a build.
 	PullPolicy image.PullPolicy
+
+	// Path to a private key, in the format used by cosign, to sign the published builder with.
+	SigningKey string
 }
 
 // CreateBuilder creates and saves a builder image to a registry with the provided options.
@@ -46,19 +49,49 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		return err
 	}
 
-	bldr, err := c.createBaseBuilder(ctx, opts)
+	signingKey, err := loadSigningKey(opts.SigningKey, opts.Publish)
+	if err != nil {
+		return err
+	}
+
+	var digest string
+	if len(opts.Config.Targets) > 0 {
+		digest, err = c.createBuilderTargets(ctx, opts)
+	} else {
+		digest, err = c.createBuilder(ctx, opts, "")
+	}
+
+	if err != nil || signingKey == nil {
+		return err
+	}
+
+	return c.signImages(signingKey, digest, opts.BuilderName)
+}
+
+// createBuilder creates and saves a builder image for the platform, in the form 'os/arch[/variant]'.
+// When platform is empty, the build image for the platform of the daemon or registry is used.
+// The digest of the builder image is returned when it is signed.
+func (c *Client) createBuilder(ctx context.Context, opts CreateBuilderOptions, platform string) (string, error) {
+	bldr, err := c.createBaseBuilder(ctx, opts, platform)
 	if err != nil {
-		return errors.Wrap(err, "failed to create builder")
+		return "", errors.Wrap(err, "failed to create builder")
 	}
 
-	if err := c.addBuildpacksToBuilder(ctx, opts, bldr); err != nil {
-		return errors.Wrap(err, "failed to add buildpacks to builder")
+	if err := c.addBuildpacksToBuilder(ctx, opts, bldr, platform); err != nil {
+		return "", errors.Wrap(err, "failed to add buildpacks to builder")
 	}
 
 	bldr.SetOrder(opts.Config.Order)
 	bldr.SetStack(opts.Config.Stack)
 
-	return bldr.Save(c.logger, builder.CreatorMetadata{Version: c.version})
+	if err := bldr.Save(c.logger, builder.CreatorMetadata{Version: c.version}); err != nil {
+		return "", err
+	}
+
+	if opts.SigningKey == "" {
+		return "", nil
+	}
+	return imageDigest(opts.BuilderName, bldr.Image())
 }
 
 func (c *Client) validateConfig(ctx context.Context, opts CreateBuilderOptions) error {
@@ -118,12 +151,18 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 	return nil
 }
 
-func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOptions) (*builder.Builder, error) {
-	baseImage, err := c.imageFetcher.Fetch(ctx, opts.Config.Stack.BuildImage, image.FetchOptions{Daemon: !opts.Publish, PullPolicy: opts.PullPolicy})
+func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOptions, platform string) (*builder.Builder, error) {
+	baseImage, err := c.imageFetcher.Fetch(ctx, opts.Config.Stack.BuildImage, image.FetchOptions{Daemon: !opts.Publish, PullPolicy: opts.PullPolicy, Platform: platform})
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch build image")
 	}
 
+	if platform != "" {
+		if err := validateImagePlatform(baseImage, "build image", platform); err != nil {
+			return nil, err
+		}
+	}
+
 	c.logger.Debugf("Creating builder %s from build-image %s", style.Symbol(opts.BuilderName), style.Symbol(baseImage.Name()))
 	bldr, err := builder.New(baseImage, opts.BuilderName)
 	if err != nil {
@@ -149,7 +188,12 @@ func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOption
 		)
 	}
 
-	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle, opts.RelativeBaseDir, os)
+	arch, err := baseImage.Architecture()
+	if err != nil {
+		return nil, errors.Wrap(err, "lookup image architecture")
+	}
+
+	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle, opts.RelativeBaseDir, os, arch)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch lifecycle")
 	}
@@ -159,7 +203,7 @@ func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOption
 	return bldr, nil
 }
 
-func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig, relativeBaseDir, os string) (builder.Lifecycle, error) {
+func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig, relativeBaseDir, os, arch string) (builder.Lifecycle, error) {
 	if config.Version != "" && config.URI != "" {
 		return nil, errors.Errorf(
 			"%s can only declare %s or %s, not both",
@@ -176,14 +220,14 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 			return nil, errors.Wrapf(err, "%s must be a valid semver", style.Symbol("lifecycle.version"))
 		}
 
-		uri = uriFromLifecycleVersion(*v, os)
+		uri = uriFromLifecycleVersion(*v, os, arch)
 	case config.URI != "":
 		uri, err = paths.FilePathToURI(config.URI, relativeBaseDir)
 		if err != nil {
 			return nil, err
 		}
 	default:
-		uri = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os)
+		uri = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os, arch)
 	}
 
 	blob, err := c.downloader.Download(ctx, uri)
@@ -199,7 +243,8 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 	return lifecycle, nil
 }
 
-func (c *Client) addBuildpacksToBuilder(ctx context.Context, opts CreateBuilderOptions, bldr *builder.Builder) error {
+func (c *Client) addBuildpacksToBuilder(ctx context.Context, opts CreateBuilderOptions, bldr *builder.Builder, platform string) error {
+	var downloads []buildpackDownload
 	for _, b := range opts.Config.Buildpacks {
 		c.logger.Debugf("Looking up buildpack %s", style.Symbol(b.DisplayString()))
 
@@ -208,17 +253,27 @@ func (c *Client) addBuildpacksToBuilder(ctx context.Context, opts CreateBuilderO
 			return errors.Wrapf(err, "getting OS from %s", style.Symbol(bldr.Image().Name()))
 		}
 
-		mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, b.URI, buildpack.DownloadOptions{
-			RegistryName:    opts.Registry,
-			ImageOS:         imageOS,
-			RelativeBaseDir: opts.RelativeBaseDir,
-			Daemon:          !opts.Publish,
-			PullPolicy:      opts.PullPolicy,
-			ImageName:       b.ImageName,
+		downloads = append(downloads, buildpackDownload{
+			uri: b.URI,
+			options: buildpack.DownloadOptions{
+				RegistryName:    opts.Registry,
+				ImageOS:         imageOS,
+				RelativeBaseDir: opts.RelativeBaseDir,
+				Daemon:          !opts.Publish,
+				PullPolicy:      opts.PullPolicy,
+				ImageName:       b.ImageName,
+				Platform:        platform,
+			},
 		})
-		if err != nil {
-			return errors.Wrap(err, "downloading buildpack")
-		}
+	}
+
+	downloaded, err := c.downloadBuildpacks(ctx, downloads)
+	if err != nil {
+		return errors.Wrap(err, "downloading buildpack")
+	}
+
+	for i, b := range opts.Config.Buildpacks {
+		mainBP, depBPs := downloaded[i].mainBP, downloaded[i].depBPs
 
 		err = validateBuildpack(mainBP, b.URI, b.ID, b.Version)
 		if err != nil {
@@ -263,10 +318,14 @@ func validateBuildpack(bp buildpack.Buildpack, source, expectedID, expectedBPVer
 	return nil
 }
 
-func uriFromLifecycleVersion(version semver.Version, os string) string {
+func uriFromLifecycleVersion(version semver.Version, os, arch string) string {
 	if os == "windows" {
 		return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+windows.x86-64.tgz", version.String(), version.String())
 	}
 
+	if arch == "arm64" {
+		return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+linux.arm64.tgz", version.String(), version.String())
+	}
+
 	return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+linux.x86-64.tgz", version.String(), version.String())
 }
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/imageindex"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// createBuilderTargets creates a builder for each of the configured targets. When publishing, each builder is
+// published to a tag suffixed with its platform and an image index referencing them is published to the builder name.
+// The digest of the published index is returned. See builder.Config.Targets.
+func (c *Client) createBuilderTargets(ctx context.Context, opts CreateBuilderOptions) (string, error) {
+	targets := opts.Config.Targets
+
+	if !opts.Publish && len(targets) > 1 {
+		return "", errors.New("creating a builder for multiple targets requires publishing the builder")
+	}
+
+	tag, err := name.NewTag(opts.BuilderName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid builder name %s", style.Symbol(opts.BuilderName))
+	}
+
+	var manifests []imageindex.Manifest
+	for _, target := range targets {
+		platform := v1.Platform{OS: target.OS, Architecture: target.Arch, Variant: target.Variant}
+
+		targetOpts := opts
+		targetOpts.Config.Targets = nil
+		// the builders of the targets are signed through the image index
+		targetOpts.SigningKey = ""
+		if target.BuildImage != "" {
+			targetOpts.Config.Stack.BuildImage = target.BuildImage
+		}
+		if target.Lifecycle != (pubbldr.LifecycleConfig{}) {
+			targetOpts.Config.Lifecycle = target.Lifecycle
+		}
+		if opts.Publish {
+			targetOpts.BuilderName = imageindex.PlatformTag(tag, platform).Name()
+		}
+
+		c.logger.Infof("Creating builder %s for target %s", style.Symbol(targetOpts.BuilderName), style.Symbol(target.String()))
+		if _, err := c.createBuilder(ctx, targetOpts, target.String()); err != nil {
+			return "", errors.Wrapf(err, "creating builder for target %s", style.Symbol(target.String()))
+		}
+
+		manifests = append(manifests, imageindex.Manifest{Ref: imageindex.PlatformTag(tag, platform), Platform: platform})
+	}
+
+	if !opts.Publish {
+		return "", nil
+	}
+
+	digest, err := imageindex.Publish(c.keychain, manifests, tag)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Infof("Published image index %s with digest %s", style.Symbol(tag.Name()), style.Symbol(digest.String()))
+	return digest.String(), nil
+}
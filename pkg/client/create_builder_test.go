@@ -3,8 +3,13 @@ package client_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,6 +18,11 @@ import (
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
@@ -23,6 +33,7 @@ import (
 	"github.com/buildpacks/pack/internal/builder"
 	ifakes "github.com/buildpacks/pack/internal/fakes"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/signature"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/archive"
 	"github.com/buildpacks/pack/pkg/blob"
@@ -726,6 +737,130 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("targets are configured", func() {
+			var (
+				server        *httptest.Server
+				registryHost  string
+				armBuildImage *fakes.Image
+			)
+
+			newBuildImage := func(name, arch string) *fakes.Image {
+				img := fakes.NewImage(name, "", nil)
+				h.AssertNil(t, img.SetArchitecture(arch))
+				h.AssertNil(t, img.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+				h.AssertNil(t, img.SetLabel("io.buildpacks.stack.mixins", `["mixinX", "build:mixinY"]`))
+				h.AssertNil(t, img.SetEnv("CNB_USER_ID", "1234"))
+				h.AssertNil(t, img.SetEnv("CNB_GROUP_ID", "4321"))
+				return img
+			}
+
+			it.Before(func() {
+				server = httptest.NewServer(registry.New())
+				u, err := url.Parse(server.URL)
+				h.AssertNil(t, err)
+				registryHost = u.Host
+
+				armBuildImage = newBuildImage("some/build-image-arm64", "arm64")
+
+				prepareFetcherWithRunImages()
+				opts.Config.Targets = []pubbldr.TargetConfig{
+					{Target: dist.Target{OS: "linux", Arch: "amd64"}},
+					{
+						Target:     dist.Target{OS: "linux", Arch: "arm64"},
+						BuildImage: "some/build-image-arm64",
+						Lifecycle:  pubbldr.LifecycleConfig{URI: "file:///some-lifecycle-arm64"},
+					},
+				}
+				mockDownloader.EXPECT().Download(gomock.Any(), "file:///some-lifecycle-arm64").Return(blob.NewBlob(filepath.Join("testdata", "lifecycle", "platform-0.4")), nil).AnyTimes()
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("publishes an image index referencing a builder per target", func() {
+				opts.BuilderName = registryHost + "/some/builder:1.0"
+				opts.Publish = true
+
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/build-image", image.FetchOptions{PullPolicy: image.PullAlways, Platform: "linux/amd64"}).
+					Return(&publishingImage{Image: fakeBuildImage}, nil)
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/build-image-arm64", image.FetchOptions{PullPolicy: image.PullAlways, Platform: "linux/arm64"}).
+					Return(&publishingImage{Image: armBuildImage}, nil)
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
+
+				h.AssertEq(t, fakeBuildImage.Name(), registryHost+"/some/builder:1.0-linux-amd64")
+				h.AssertEq(t, armBuildImage.Name(), registryHost+"/some/builder:1.0-linux-arm64")
+
+				ref, err := name.ParseReference(opts.BuilderName)
+				h.AssertNil(t, err)
+				index, err := remote.Index(ref)
+				h.AssertNil(t, err)
+
+				manifest, err := index.IndexManifest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(manifest.Manifests), 2)
+				h.AssertEq(t, manifest.Manifests[0].Platform.Architecture, "amd64")
+				h.AssertEq(t, manifest.Manifests[1].Platform.Architecture, "arm64")
+
+				h.AssertContains(t, out.String(), "Published image index '"+opts.BuilderName+"'")
+			})
+
+			it("signs the published image index when a signing key is given", func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				h.AssertNil(t, err)
+				keyPath := writeSigningKey(t, key)
+				defer os.Remove(keyPath)
+
+				opts.BuilderName = registryHost + "/some/builder:1.0"
+				opts.Publish = true
+				opts.SigningKey = keyPath
+
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image", gomock.Any()).Return(&publishingImage{Image: fakeBuildImage}, nil)
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image-arm64", gomock.Any()).Return(&publishingImage{Image: armBuildImage}, nil)
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
+
+				ref, err := name.ParseReference(opts.BuilderName)
+				h.AssertNil(t, err)
+				desc, err := remote.Get(ref)
+				h.AssertNil(t, err)
+				h.AssertNil(t, signature.Verify(ref.Context().Digest(desc.Digest.String()), &key.PublicKey, authn.DefaultKeychain))
+			})
+
+			it("fails when the build image doesn't match the target", func() {
+				opts.BuilderName = registryHost + "/some/builder:1.0"
+				opts.Publish = true
+
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/build-image", gomock.Any()).
+					Return(newBuildImage("some/build-image", "arm64"), nil)
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+				h.AssertError(t, err, "creating builder for target 'linux/amd64'")
+				h.AssertError(t, err, "build image platform 'linux/arm64' does not match platform 'linux/amd64'")
+			})
+
+			it("requires publishing for multiple targets", func() {
+				err := subject.CreateBuilder(context.TODO(), opts)
+				h.AssertError(t, err, "creating a builder for multiple targets requires publishing the builder")
+			})
+
+			it("creates the builder in the daemon for a single target", func() {
+				opts.Config.Targets = opts.Config.Targets[1:]
+
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/build-image-arm64", image.FetchOptions{Daemon: true, PullPolicy: image.PullAlways, Platform: "linux/arm64"}).
+					Return(armBuildImage, nil)
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
+				h.AssertEq(t, armBuildImage.Name(), "some/builder")
+				h.AssertEq(t, armBuildImage.IsSaved(), true)
+			})
+		})
 	})
 }
 
@@ -740,3 +875,26 @@ func (i fakeBadImageStruct) Name() string {
 func (i fakeBadImageStruct) Label(str string) (string, error) {
 	return "", errors.New("error here")
 }
+
+// publishingImage publishes a random image when saved, as a remote builder image would.
+type publishingImage struct {
+	*fakes.Image
+}
+
+func (i *publishingImage) Save(additionalNames ...string) error {
+	if err := i.Image.Save(additionalNames...); err != nil {
+		return err
+	}
+
+	ref, err := name.ParseReference(i.Name())
+	if err != nil {
+		return err
+	}
+
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		return err
+	}
+
+	return remote.Write(ref, img)
+}
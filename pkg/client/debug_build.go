@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const defaultDebugShell = "/bin/sh"
+
+var buildVolumePrefixes = []string{"pack-layers-", "pack-app-"}
+
+// DebugBuildOptions configures the shell started by DebugBuild.
+type DebugBuildOptions struct {
+	// ContainerID is the container of a failed lifecycle phase, kept by a build with KeepOnFailure.
+	ContainerID string
+
+	// Shell is the command started in the debug container. Defaults to /bin/sh.
+	Shell string
+
+	// Cleanup, when true, removes the failed container and the volumes of its build once the shell exits.
+	Cleanup bool
+
+	// In is read as the stdin of the shell.
+	In io.Reader
+
+	// Out and ErrOut receive the stdout and stderr of the shell.
+	// With TTY, both are written to Out.
+	Out, ErrOut io.Writer
+
+	// TTY, when true, allocates a pseudo-terminal for the shell, such as when In is a terminal.
+	TTY bool
+
+	// TerminalSize is the initial height and width of the pseudo-terminal.
+	TerminalSize [2]uint
+}
+
+// DebugBuild starts a shell in a new container with the image, volumes, environment and user of a failed lifecycle
+// phase, so that the state the phase failed in can be inspected. The debug container is removed when the shell exits.
+func (c *Client) DebugBuild(ctx context.Context, opts DebugBuildOptions) error {
+	if opts.ContainerID == "" {
+		return errors.New("container ID must be provided")
+	}
+
+	info, err := c.engine.ContainerInspect(ctx, opts.ContainerID)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting container %s", style.Symbol(opts.ContainerID))
+	}
+
+	shell := opts.Shell
+	if shell == "" {
+		shell = defaultDebugShell
+	}
+
+	ctrConf := &dcontainer.Config{
+		Image:        info.Image,
+		Cmd:          []string{shell},
+		Entrypoint:   []string{""},
+		Tty:          opts.TTY,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Labels:       map[string]string{"author": "pack"},
+	}
+	hostConf := &dcontainer.HostConfig{
+		ConsoleSize: opts.TerminalSize,
+	}
+	if info.Config != nil {
+		ctrConf.Env = info.Config.Env
+		ctrConf.User = info.Config.User
+		ctrConf.WorkingDir = info.Config.WorkingDir
+		if len(info.Config.Cmd) > 0 {
+			c.logger.Infof("The failed phase ran %s", style.Symbol(strings.Join(info.Config.Cmd, " ")))
+		}
+	}
+	if info.ContainerJSONBase != nil && info.HostConfig != nil {
+		hostConf.Binds = info.HostConfig.Binds
+		hostConf.NetworkMode = info.HostConfig.NetworkMode
+	}
+
+	ctr, err := c.engine.ContainerCreate(ctx, ctrConf, hostConf, nil, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "creating debug container")
+	}
+	defer c.engine.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	err = container.RunInteractive(ctx, c.engine, ctr.ID, opts.TTY, opts.In, opts.Out, opts.ErrOut)
+	var exitErr container.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		c.logger.Debugf("Shell exited with status code %d", exitErr.StatusCode)
+	case err != nil:
+		return errors.Wrap(err, "running debug container")
+	}
+
+	if opts.Cleanup {
+		return c.removeFailedBuild(info)
+	}
+
+	return nil
+}
+
+func (c *Client) removeFailedBuild(info types.ContainerJSON) error {
+	if err := c.engine.ContainerRemove(context.Background(), info.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+		return errors.Wrapf(err, "removing container %s", style.Symbol(info.ID))
+	}
+
+	for _, mnt := range info.Mounts {
+		if mnt.Type != mount.TypeVolume || !isBuildVolume(mnt.Name) {
+			continue
+		}
+		if err := c.engine.VolumeRemove(context.Background(), mnt.Name, true); err != nil {
+			return errors.Wrapf(err, "removing volume %s", style.Symbol(mnt.Name))
+		}
+	}
+
+	c.logger.Infof("Removed container %s and the volumes of its build", style.Symbol(info.ID))
+	return nil
+}
+
+func isBuildVolume(name string) bool {
+	for _, prefix := range buildVolumePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
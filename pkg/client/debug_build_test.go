@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDebugBuild(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DebugBuild", testDebugBuild, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDebugBuild(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out, shellOut    bytes.Buffer
+		stdin            string
+		exitCode         int64
+		failedContainer  types.ContainerJSON
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+		subject = &Client{
+			logger: logging.NewLogWithWriters(&out, &out),
+			engine: engine.NewDocker(mockDockerClient),
+		}
+		stdin = ""
+		exitCode = 0
+
+		failedContainer = types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "failed-container",
+				Image: "sha256:builder-image",
+				HostConfig: &dcontainer.HostConfig{
+					Binds:       []string{"pack-layers-abc:/layers", "pack-app-abc:/workspace", "pack-cache-abc:/cache"},
+					NetworkMode: "some-network",
+				},
+			},
+			Mounts: []types.MountPoint{
+				{Type: mount.TypeVolume, Name: "pack-layers-abc", Destination: "/layers"},
+				{Type: mount.TypeVolume, Name: "pack-app-abc", Destination: "/workspace"},
+				{Type: mount.TypeVolume, Name: "pack-cache-abc", Destination: "/cache"},
+			},
+			Config: &dcontainer.Config{
+				Env:  []string{"CNB_PLATFORM_API=0.8"},
+				User: "1000:1000",
+				Cmd:  []string{"/cnb/lifecycle/builder", "-log-level", "debug"},
+			},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	expectShell := func(assertConfig func(*dcontainer.Config, *dcontainer.HostConfig)) {
+		mockDockerClient.EXPECT().ContainerInspect(gomock.Any(), "failed-container").Return(failedContainer, nil)
+		mockDockerClient.EXPECT().
+			ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+			DoAndReturn(func(_ context.Context, config *dcontainer.Config, hostConfig *dcontainer.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (dcontainer.ContainerCreateCreatedBody, error) {
+				assertConfig(config, hostConfig)
+				return dcontainer.ContainerCreateCreatedBody{ID: "debug-container"}, nil
+			})
+
+		bodyChan := make(chan dcontainer.ContainerWaitOKBody, 1)
+		mockDockerClient.EXPECT().
+			ContainerWait(gomock.Any(), "debug-container", dcontainer.WaitConditionNextExit).
+			Return(bodyChan, make(chan error))
+
+		conn, server := net.Pipe()
+		mockDockerClient.EXPECT().
+			ContainerAttach(gomock.Any(), "debug-container", gomock.Any()).
+			Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)}, nil)
+
+		mockDockerClient.EXPECT().
+			ContainerStart(gomock.Any(), "debug-container", gomock.Any()).
+			DoAndReturn(func(context.Context, string, types.ContainerStartOptions) error {
+				go func() {
+					defer server.Close()
+					input := make([]byte, len(stdin))
+					_, err := io.ReadFull(server, input)
+					h.AssertNil(t, err)
+
+					_, err = stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte("received: " + string(input)))
+					h.AssertNil(t, err)
+					bodyChan <- dcontainer.ContainerWaitOKBody{StatusCode: exitCode}
+				}()
+				return nil
+			})
+
+		mockDockerClient.EXPECT().ContainerRemove(gomock.Any(), "debug-container", types.ContainerRemoveOptions{Force: true}).Return(nil)
+	}
+
+	when("#DebugBuild", func() {
+		it("starts a shell with the image, volumes, env and user of the failed phase", func() {
+			stdin = "ls /layers\n"
+			expectShell(func(config *dcontainer.Config, hostConfig *dcontainer.HostConfig) {
+				h.AssertEq(t, config.Image, "sha256:builder-image")
+				h.AssertEq(t, []string(config.Cmd), []string{"/bin/sh"})
+				h.AssertEq(t, config.Env, []string{"CNB_PLATFORM_API=0.8"})
+				h.AssertEq(t, config.User, "1000:1000")
+				h.AssertTrue(t, config.OpenStdin)
+				h.AssertEq(t, config.Labels, map[string]string{"author": "pack"})
+				h.AssertEq(t, hostConfig.Binds, failedContainer.HostConfig.Binds)
+				h.AssertEq(t, hostConfig.NetworkMode, dcontainer.NetworkMode("some-network"))
+			})
+
+			h.AssertNil(t, subject.DebugBuild(context.TODO(), DebugBuildOptions{
+				ContainerID: "failed-container",
+				In:          strings.NewReader(stdin),
+				Out:         &shellOut,
+				ErrOut:      &shellOut,
+			}))
+
+			h.AssertEq(t, shellOut.String(), "received: ls /layers\n")
+			h.AssertContains(t, out.String(), "The failed phase ran '/cnb/lifecycle/builder -log-level debug'")
+		})
+
+		it("starts the provided shell", func() {
+			expectShell(func(config *dcontainer.Config, _ *dcontainer.HostConfig) {
+				h.AssertEq(t, []string(config.Cmd), []string{"/bin/bash"})
+			})
+
+			h.AssertNil(t, subject.DebugBuild(context.TODO(), DebugBuildOptions{
+				ContainerID: "failed-container",
+				Shell:       "/bin/bash",
+				In:          strings.NewReader(""),
+				Out:         &shellOut,
+				ErrOut:      &shellOut,
+			}))
+		})
+
+		it("doesn't fail when the shell exits with a non-zero status", func() {
+			exitCode = 2
+			expectShell(func(*dcontainer.Config, *dcontainer.HostConfig) {})
+
+			h.AssertNil(t, subject.DebugBuild(context.TODO(), DebugBuildOptions{
+				ContainerID: "failed-container",
+				In:          strings.NewReader(""),
+				Out:         &shellOut,
+				ErrOut:      &shellOut,
+			}))
+		})
+
+		when("cleanup is requested", func() {
+			it("removes the failed container and the volumes of its build", func() {
+				expectShell(func(*dcontainer.Config, *dcontainer.HostConfig) {})
+				mockDockerClient.EXPECT().ContainerRemove(gomock.Any(), "failed-container", types.ContainerRemoveOptions{Force: true}).Return(nil)
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-layers-abc", true).Return(nil)
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-app-abc", true).Return(nil)
+
+				h.AssertNil(t, subject.DebugBuild(context.TODO(), DebugBuildOptions{
+					ContainerID: "failed-container",
+					Cleanup:     true,
+					In:          strings.NewReader(""),
+					Out:         &shellOut,
+					ErrOut:      &shellOut,
+				}))
+
+				h.AssertContains(t, out.String(), "Removed container 'failed-container' and the volumes of its build")
+			})
+		})
+
+		when("the container doesn't exist", func() {
+			it("errors", func() {
+				mockDockerClient.EXPECT().ContainerInspect(gomock.Any(), "missing-container").Return(types.ContainerJSON{}, io.EOF)
+
+				err := subject.DebugBuild(context.TODO(), DebugBuildOptions{ContainerID: "missing-container"})
+				h.AssertError(t, err, "inspecting container 'missing-container'")
+			})
+		})
+
+		when("no container is provided", func() {
+			it("errors", func() {
+				err := subject.DebugBuild(context.TODO(), DebugBuildOptions{})
+				h.AssertError(t, err, "container ID must be provided")
+			})
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+
+	"github.com/buildpacks/lifecycle/buildpack"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/build"
+)
+
+// DetectResult is the outcome of running detection against an app.
+type DetectResult struct {
+	// Group is the group of buildpacks that passed detection, in the order they would build the app.
+	Group []buildpack.GroupBuildpack
+
+	// Plan is the build plan resolved between the buildpacks of Group.
+	Plan platform.BuildPlan
+}
+
+// Detect runs detection against the app of opts, with the builder and buildpacks a Build with the same options
+// would use. No image is built or exported.
+// It returns the group of buildpacks selected for the app and the build plan they resolved.
+func (c *Client) Detect(ctx context.Context, opts BuildOptions) (DetectResult, error) {
+	if len(opts.Platforms) > 1 {
+		return DetectResult{}, errors.New("detection cannot be run for multiple platforms")
+	}
+
+	if opts.Interactive {
+		return DetectResult{}, errors.New("detection cannot be run interactively")
+	}
+
+	if err := validatePhaseTimeouts(opts.PhaseTimeouts); err != nil {
+		return DetectResult{}, err
+	}
+
+	ctx, cancel := withTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var result DetectResult
+	err := c.build(ctx, opts, func(detected build.DetectResult) {
+		result = DetectResult{
+			Group: detected.Group.Group,
+			Plan:  detected.Plan,
+		}
+	}, nil)
+	if err != nil {
+		return DetectResult{}, asTimeoutError(ctx, opts.Timeout, err)
+	}
+
+	return result, nil
+}
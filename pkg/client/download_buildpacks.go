@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/buildpacks/pack/pkg/buildpack"
+)
+
+// buildpackDownloadParallelism is the maximum number of buildpacks downloaded at the same time.
+const buildpackDownloadParallelism = 8
+
+// buildpackDownload is a buildpack to download with BuildpackDownloader.Download.
+type buildpackDownload struct {
+	uri     string
+	options buildpack.DownloadOptions
+}
+
+// downloadedBuildpack holds the buildpacks returned by BuildpackDownloader.Download for a buildpackDownload.
+type downloadedBuildpack struct {
+	mainBP buildpack.Buildpack
+	depBPs []buildpack.Buildpack
+}
+
+// downloadBuildpacks downloads buildpacks, up to buildpackDownloadParallelism at the same time, and returns them in the
+// order of downloads. Identical downloads are only done once.
+// Once a download fails no other download is started, and the error of the first failed download in downloads is
+// returned.
+func (c *Client) downloadBuildpacks(ctx context.Context, downloads []buildpackDownload) ([]downloadedBuildpack, error) {
+	var (
+		results = make([]downloadedBuildpack, len(downloads))
+		errs    = make([]error, len(downloads))
+		firsts  = map[string]int{}
+		dups    = map[int]int{}
+	)
+	for i, download := range downloads {
+		key := fmt.Sprintf("%s|%+v", download.uri, download.options)
+		if first, ok := firsts[key]; ok {
+			dups[i] = first
+			continue
+		}
+		firsts[key] = i
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+		slots  = make(chan struct{}, buildpackDownloadParallelism)
+	)
+	for i, download := range downloads {
+		if _, ok := dups[i]; ok {
+			continue
+		}
+
+		slots <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, download buildpackDownload) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, download.uri, download.options)
+			if err != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+				errs[i] = err
+				return
+			}
+			results[i] = downloadedBuildpack{mainBP: mainBP, depBPs: depBPs}
+		}(i, download)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, first := range dups {
+		results[i] = results[first]
+	}
+	return results, nil
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDownloadBuildpacks(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DownloadBuildpacks", testDownloadBuildpacks, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDownloadBuildpacks(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject                 *Client
+		mockController          *gomock.Controller
+		mockBuildpackDownloader *testmocks.MockBuildpackDownloader
+		out                     bytes.Buffer
+	)
+
+	fakeBuildpack := func(id string) buildpack.Buildpack {
+		bp, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+			API:    api.MustParse("0.3"),
+			Info:   dist.BuildpackInfo{ID: id, Version: "1.2.3"},
+			Stacks: []dist.Stack{{ID: "*"}},
+		}, 0644)
+		h.AssertNil(t, err)
+		return bp
+	}
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockBuildpackDownloader = testmocks.NewMockBuildpackDownloader(mockController)
+		subject = &Client{
+			logger:              logging.NewLogWithWriters(&out, &out),
+			buildpackDownloader: mockBuildpackDownloader,
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#downloadBuildpacks", func() {
+		it("returns the buildpacks in the order of the downloads", func() {
+			secondDone := make(chan struct{})
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "some/first", gomock.Any()).
+				DoAndReturn(func(context.Context, string, buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+					// the first download finishes last
+					<-secondDone
+					return fakeBuildpack("first"), []buildpack.Buildpack{fakeBuildpack("first-dep")}, nil
+				})
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "some/second", gomock.Any()).
+				DoAndReturn(func(context.Context, string, buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+					defer close(secondDone)
+					return fakeBuildpack("second"), nil, nil
+				})
+
+			downloaded, err := subject.downloadBuildpacks(context.TODO(), []buildpackDownload{{uri: "some/first"}, {uri: "some/second"}})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(downloaded), 2)
+			h.AssertEq(t, downloaded[0].mainBP.Descriptor().Info.ID, "first")
+			h.AssertEq(t, downloaded[0].depBPs[0].Descriptor().Info.ID, "first-dep")
+			h.AssertEq(t, downloaded[1].mainBP.Descriptor().Info.ID, "second")
+		})
+
+		it("downloads identical buildpacks once", func() {
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "some/bp", gomock.Any()).Return(fakeBuildpack("some-bp"), nil, nil).Times(1)
+
+			downloaded, err := subject.downloadBuildpacks(context.TODO(), []buildpackDownload{{uri: "some/bp"}, {uri: "some/bp"}})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(downloaded), 2)
+			h.AssertTrue(t, downloaded[0].mainBP == downloaded[1].mainBP)
+		})
+
+		it("limits the number of downloads at the same time", func() {
+			var (
+				mu               sync.Mutex
+				running, maxSeen int
+			)
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, uri string, _ buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+					mu.Lock()
+					running++
+					if running > maxSeen {
+						maxSeen = running
+					}
+					mu.Unlock()
+
+					bp := fakeBuildpack(uri)
+
+					mu.Lock()
+					running--
+					mu.Unlock()
+					return bp, nil, nil
+				}).
+				Times(3 * buildpackDownloadParallelism)
+
+			var downloads []buildpackDownload
+			for i := 0; i < 3*buildpackDownloadParallelism; i++ {
+				downloads = append(downloads, buildpackDownload{uri: fmt.Sprintf("some/bp-%d", i)})
+			}
+
+			downloaded, err := subject.downloadBuildpacks(context.TODO(), downloads)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(downloaded), len(downloads))
+			for i, bp := range downloaded {
+				h.AssertEq(t, bp.mainBP.Descriptor().Info.ID, fmt.Sprintf("some/bp-%d", i))
+			}
+			h.AssertTrue(t, maxSeen <= buildpackDownloadParallelism)
+		})
+
+		it("returns the error of the first failed download", func() {
+			secondDone := make(chan struct{})
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "some/first", gomock.Any()).
+				DoAndReturn(func(context.Context, string, buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+					<-secondDone
+					return nil, nil, errors.New("first-error")
+				})
+			mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "some/second", gomock.Any()).
+				DoAndReturn(func(context.Context, string, buildpack.DownloadOptions) (buildpack.Buildpack, []buildpack.Buildpack, error) {
+					defer close(secondDone)
+					return nil, nil, errors.New("second-error")
+				})
+
+			_, err := subject.downloadBuildpacks(context.TODO(), []buildpackDownload{{uri: "some/first"}, {uri: "some/second"}})
+			h.AssertError(t, err, "first-error")
+		})
+	})
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// ephemeralBuilderRepo is the repository of the ephemeral builders, created from the builder of a build with the
+// buildpacks and environment variables of the build.
+const ephemeralBuilderRepo = "pack.local/builder"
+
+// PruneEphemeralBuildersOptions configures PruneEphemeralBuilders.
+type PruneEphemeralBuildersOptions struct {
+	// DryRun reports the ephemeral builders that would be removed without removing them.
+	DryRun bool
+}
+
+// ephemeralBuilder returns the ephemeral builder adding env, order and buildpacks to the builder image, and a func
+// removing it once the build is done.
+// Ephemeral builders are kept on the daemon, named after a hash of the builder image and of what they add to it, so
+// that later builds with the same builder and buildpacks reuse them. See PruneEphemeralBuilders to remove them.
+// Builders with environment variables, which may hold credentials and are written to the builder layers, and builders
+// with buildpacks that can't be identified without reading them aren't kept: they are removed after the build, or
+// after the last build of the session sharing them.
+func (c *Client) ephemeralBuilder(ctx context.Context, rawBuilderImage imgutil.Image, platform string, env map[string]string, order dist.Order, buildpacks []buildpack.Buildpack) (*builder.Builder, func(), error) {
+	key, err := c.ephemeralBuilderKey(rawBuilderImage, platform, env, order, buildpacks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	create := func() (*builder.Builder, func(), error) {
+		if !ephemeralBuilderCacheable(env, buildpacks) {
+			return c.temporaryEphemeralBuilder(rawBuilderImage, env, order, buildpacks)
+		}
+		bldr, err := c.cachedEphemeralBuilder(ctx, key, rawBuilderImage, order, buildpacks)
+		return bldr, func() {}, err
+	}
+	if c.session != nil {
+		// the temporary ephemeral builders of a session are removed once all of its builds are done
+		bldr, err := c.session.ephemeralBuilder(key, create)
+		return bldr, func() {}, err
+	}
+	return create()
+}
+
+// ephemeralBuilderCacheable returns whether the ephemeral builder adding env and buildpacks to a builder can be kept
+// for later builds.
+func ephemeralBuilderCacheable(env map[string]string, buildpacks []buildpack.Buildpack) bool {
+	if len(env) > 0 {
+		return false
+	}
+	for _, bp := range buildpacks {
+		if _, ok := buildpack.LayerDiffID(bp); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedEphemeralBuilder returns the ephemeral builder of key from the daemon, or creates it when a previous build
+// didn't.
+func (c *Client) cachedEphemeralBuilder(ctx context.Context, key string, rawBuilderImage imgutil.Image, order dist.Order, buildpacks []buildpack.Buildpack) (*builder.Builder, error) {
+	name := fmt.Sprintf("%s/%s:latest", ephemeralBuilderRepo, key)
+
+	img, err := c.imageFetcher.Fetch(ctx, name, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever})
+	switch {
+	case err == nil:
+		bldr, err := builder.FromImage(img)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid ephemeral builder %s", style.Symbol(name))
+		}
+		c.logger.Debugf("Using ephemeral builder %s", style.Symbol(name))
+		return bldr, nil
+	case errors.Is(err, image.ErrNotFound):
+		return c.createEphemeralBuilder(rawBuilderImage, name, nil, order, buildpacks)
+	default:
+		return nil, errors.Wrapf(err, "fetching ephemeral builder %s", style.Symbol(name))
+	}
+}
+
+// temporaryEphemeralBuilder creates an ephemeral builder with a random name, and returns it with a func removing it.
+func (c *Client) temporaryEphemeralBuilder(rawBuilderImage imgutil.Image, env map[string]string, order dist.Order, buildpacks []buildpack.Buildpack) (*builder.Builder, func(), error) {
+	name := fmt.Sprintf("%s/%x:latest", ephemeralBuilderRepo, randString(10))
+	bldr, err := c.createEphemeralBuilder(rawBuilderImage, name, env, order, buildpacks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bldr, func() {
+		c.engine.ImageRemove(context.Background(), bldr.Name(), types.ImageRemoveOptions{Force: true})
+	}, nil
+}
+
+// ephemeralBuilderKey returns a hash of the builder image and of the env, order and buildpacks added to it.
+// Buildpacks are hashed by descriptor and layer diffID, so that a buildpack changed without a new version gets a new
+// ephemeral builder without reading its contents.
+func (c *Client) ephemeralBuilderKey(rawBuilderImage imgutil.Image, platform string, env map[string]string, order dist.Order, buildpacks []buildpack.Buildpack) (string, error) {
+	identifier, err := rawBuilderImage.Identifier()
+	if err != nil {
+		return "", errors.Wrapf(err, "identifying builder %s", style.Symbol(rawBuilderImage.Name()))
+	}
+
+	var envVars []string
+	for key, value := range env {
+		envVars = append(envVars, key+"="+value)
+	}
+	sort.Strings(envVars)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%s|%s|%v\n", c.version, identifier, platform, strings.Join(envVars, ","), order)
+	for _, bp := range buildpacks {
+		diffID, _ := buildpack.LayerDiffID(bp)
+		fmt.Fprintf(hash, "%+v|%s\n", bp.Descriptor(), diffID)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))[:32], nil
+}
+
+// PruneEphemeralBuilders removes the ephemeral builders kept on the daemon for later builds, and returns their names.
+// Ephemeral builders that can't be removed, such as those used by a running build, are skipped.
+func (c *Client) PruneEphemeralBuilders(ctx context.Context, opts PruneEphemeralBuildersOptions) ([]string, error) {
+	summaries, err := c.engine.ImageList(ctx, types.ImageListOptions{
+		Filters: filters.NewArgs(filters.Arg("reference", ephemeralBuilderRepo+"/*")),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing images")
+	}
+
+	var names []string
+	for _, summary := range summaries {
+		for _, tag := range summary.RepoTags {
+			if strings.HasPrefix(tag, ephemeralBuilderRepo+"/") {
+				names = append(names, tag)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var pruned []string
+	for _, name := range names {
+		if !opts.DryRun {
+			if _, err := c.engine.ImageRemove(ctx, name, types.ImageRemoveOptions{}); err != nil {
+				c.logger.Warnf("Skipping ephemeral builder %s: %s", style.Symbol(name), err)
+				continue
+			}
+		}
+		pruned = append(pruned, name)
+	}
+
+	return pruned, nil
+}
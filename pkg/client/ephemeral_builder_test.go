@@ -0,0 +1,125 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/engine"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestEphemeralBuilder(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "EphemeralBuilder", testEphemeralBuilder, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testEphemeralBuilder(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+		subject = &Client{
+			logger: logging.NewLogWithWriters(&out, &out),
+			engine: engine.NewDocker(mockDockerClient),
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#PruneEphemeralBuilders", func() {
+		it.Before(func() {
+			mockDockerClient.EXPECT().
+				ImageList(gomock.Any(), types.ImageListOptions{Filters: filters.NewArgs(filters.Arg("reference", "pack.local/builder/*"))}).
+				Return([]types.ImageSummary{
+					{ID: "sha256:some-image", RepoTags: []string{"pack.local/builder/bbbb:latest", "other/builder:latest"}},
+					{ID: "sha256:other-image", RepoTags: []string{"pack.local/builder/aaaa:latest"}},
+				}, nil)
+		})
+
+		it("removes the ephemeral builders", func() {
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/aaaa:latest", types.ImageRemoveOptions{})
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/bbbb:latest", types.ImageRemoveOptions{})
+
+			pruned, err := subject.PruneEphemeralBuilders(context.TODO(), PruneEphemeralBuildersOptions{})
+			h.AssertNil(t, err)
+			h.AssertEq(t, pruned, []string{"pack.local/builder/aaaa:latest", "pack.local/builder/bbbb:latest"})
+		})
+
+		it("skips ephemeral builders that can't be removed", func() {
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/aaaa:latest", gomock.Any()).Return(nil, errors.New("image is in use"))
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/bbbb:latest", gomock.Any())
+
+			pruned, err := subject.PruneEphemeralBuilders(context.TODO(), PruneEphemeralBuildersOptions{})
+			h.AssertNil(t, err)
+			h.AssertEq(t, pruned, []string{"pack.local/builder/bbbb:latest"})
+			h.AssertContains(t, out.String(), "image is in use")
+		})
+
+		it("doesn't remove ephemeral builders in a dry run", func() {
+			pruned, err := subject.PruneEphemeralBuilders(context.TODO(), PruneEphemeralBuildersOptions{DryRun: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, pruned, []string{"pack.local/builder/aaaa:latest", "pack.local/builder/bbbb:latest"})
+		})
+	})
+	when("#ephemeralBuilderCacheable", func() {
+		var packagedBP buildpack.Buildpack
+
+		it.Before(func() {
+			pkg := fakes.NewImage("example.com/some/package", "", nil)
+			h.AssertNil(t, dist.SetLabel(pkg, "io.buildpacks.buildpack.layers", dist.BuildpackLayers{
+				"some.bp": {"1.2.3": {API: api.MustParse("0.3"), LayerDiffID: "sha256:some-diff-id"}},
+			}))
+			h.AssertNil(t, dist.SetLabel(pkg, "io.buildpacks.buildpackage.metadata", buildpack.Metadata{
+				BuildpackInfo: dist.BuildpackInfo{ID: "some.bp", Version: "1.2.3"},
+			}))
+
+			var err error
+			packagedBP, _, err = buildpack.ExtractBuildpacks(pkg)
+			h.AssertNil(t, err)
+		})
+
+		it("keeps builders adding packaged buildpacks", func() {
+			h.AssertTrue(t, ephemeralBuilderCacheable(nil, []buildpack.Buildpack{packagedBP}))
+		})
+
+		it("doesn't keep builders with environment variables", func() {
+			h.AssertFalse(t, ephemeralBuilderCacheable(map[string]string{"TOKEN": "secret"}, []buildpack.Buildpack{packagedBP}))
+		})
+
+		it("doesn't keep builders adding buildpacks that weren't packaged", func() {
+			bp, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+				API:    api.MustParse("0.3"),
+				Info:   dist.BuildpackInfo{ID: "other.bp", Version: "1.2.3"},
+				Stacks: []dist.Stack{{ID: "*"}},
+			}, 0644)
+			h.AssertNil(t, err)
+
+			h.AssertFalse(t, ephemeralBuilderCacheable(nil, []buildpack.Buildpack{packagedBP, bp}))
+		})
+	})
+}
@@ -1,5 +1,13 @@
 package client
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
 // ExperimentError denotes that an experimental feature was trying to be used without experimental features enabled.
 type ExperimentError struct {
 	msg string
@@ -23,3 +31,42 @@ func NewSoftError() SoftError {
 func (se SoftError) Error() string {
 	return ""
 }
+
+// BuildManyError reports the builds run by BuildMany that failed.
+type BuildManyError struct {
+	// Failures lists the builds that failed, in the order they were given.
+	Failures []BuildFailure
+
+	// Total is the number of builds that were run.
+	Total int
+}
+
+// BuildFailure is the error of the build of Image.
+type BuildFailure struct {
+	Image string
+	Err   error
+}
+
+func (e BuildManyError) Error() string {
+	var failures []string
+	for _, failure := range e.Failures {
+		failures = append(failures, fmt.Sprintf("%s: %s", style.Symbol(failure.Image), failure.Err))
+	}
+	return fmt.Sprintf("%d of %d builds failed: %s", len(e.Failures), e.Total, strings.Join(failures, "; "))
+}
+
+// TimeoutError is returned when a build runs longer than its timeout, or one of its lifecycle phases runs longer than
+// the timeout of the phase. The containers of the build are stopped, and its volumes removed.
+type TimeoutError struct {
+	// Phase is the name of the phase that timed out, or empty when the build timed out.
+	Phase string
+
+	Timeout time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	if e.Phase == "" {
+		return fmt.Sprintf("build timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("phase %s timed out after %s", style.Symbol(e.Phase), e.Timeout)
+}
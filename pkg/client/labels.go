@@ -0,0 +1,96 @@
+package client
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Labels of the OCI image spec, set from the project information of the project descriptor and the git provenance of
+// the app.
+// See https://github.com/opencontainers/image-spec/blob/main/annotations.md.
+const (
+	ociSourceLabel   = "org.opencontainers.image.source"
+	ociVersionLabel  = "org.opencontainers.image.version"
+	ociLicensesLabel = "org.opencontainers.image.licenses"
+	ociRevisionLabel = "org.opencontainers.image.revision"
+)
+
+const reservedLabelPrefix = "io.buildpacks."
+
+// imageLabels returns the labels to set on the app image: the OCI labels derived from the git source of the app and
+// the project descriptor, replaced by the labels of the project descriptor, replaced by the labels of opts.
+func imageLabels(opts BuildOptions, source *platform.ProjectSource) (map[string]string, error) {
+	labels := map[string]string{}
+
+	if commit, ok := gitCommit(source); ok {
+		labels[ociRevisionLabel] = commit
+		if remoteURL, ok := source.Metadata["url"].(string); ok {
+			labels[ociSourceLabel] = remoteURL
+		}
+	}
+
+	project := opts.ProjectDescriptor.Project
+	if project.SourceURL != "" {
+		labels[ociSourceLabel] = project.SourceURL
+	}
+	if project.Version != "" {
+		labels[ociVersionLabel] = project.Version
+	}
+	var licenses []string
+	for _, license := range project.Licenses {
+		if license.Type != "" {
+			licenses = append(licenses, license.Type)
+		}
+	}
+	if len(licenses) > 0 {
+		labels[ociLicensesLabel] = strings.Join(licenses, " AND ")
+	}
+
+	for _, userLabels := range []map[string]string{opts.ProjectDescriptor.Build.Labels, opts.Labels} {
+		for key, value := range userLabels {
+			if key == "" {
+				return nil, errors.New("label key must not be empty")
+			}
+			if strings.HasPrefix(key, reservedLabelPrefix) {
+				return nil, errors.Errorf("label %s is reserved for buildpacks metadata", style.Symbol(key))
+			}
+			labels[key] = value
+		}
+	}
+
+	return labels, nil
+}
+
+// setImageLabels sets labels on the exported app image, and saves it again with its additional tags.
+// The exporter of the lifecycle doesn't set labels, so the labelled image replaces the exported one, with a new digest:
+// the digest logged by the exporter is the one of the image without labels.
+func (c *Client) setImageLabels(opts BuildOptions, img imgutil.Image, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		c.logger.Debugf("Setting label %s to %s", style.Symbol(key), style.Symbol(labels[key]))
+		if err := img.SetLabel(key, labels[key]); err != nil {
+			return errors.Wrapf(err, "setting label %s", style.Symbol(key))
+		}
+	}
+
+	if err := img.Save(opts.AdditionalTags...); err != nil {
+		return errors.Wrap(err, "saving image with labels")
+	}
+
+	return nil
+}
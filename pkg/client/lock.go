@@ -0,0 +1,155 @@
+package client
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/project"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+// lockedImage returns the lock entry of img, resolved from the reference imageName. The digest is the registry
+// digest of images read from a registry, and the image ID of images read from the daemon.
+func lockedImage(imageName string, img imgutil.Image) (projectTypes.LockedImage, error) {
+	identifier, err := img.Identifier()
+	if err != nil {
+		return projectTypes.LockedImage{}, errors.Wrapf(err, "resolving digest of image %s", style.Symbol(imageName))
+	}
+	if identifier == nil {
+		return projectTypes.LockedImage{}, errors.Errorf("resolving digest of image %s: image has no identifier", style.Symbol(imageName))
+	}
+
+	digest := identifier.String()
+	if ref, err := name.NewDigest(digest, name.WeakValidation); err == nil {
+		digest = ref.DigestStr()
+	}
+
+	return projectTypes.LockedImage{Image: imageName, Digest: digest}, nil
+}
+
+// lockedBuildpacks returns the lock entries of mainBP, declared by uri, and of the dependencies of its package,
+// which have no uri of their own.
+func lockedBuildpacks(uri string, mainBP buildpack.Buildpack, depBPs []buildpack.Buildpack) ([]projectTypes.LockedBuildpack, error) {
+	var locked []projectTypes.LockedBuildpack
+	for i, bp := range append([]buildpack.Buildpack{mainBP}, depBPs...) {
+		bpURI := uri
+		if i > 0 {
+			bpURI = ""
+		}
+
+		lockedBP, err := lockedBuildpack(bpURI, bp)
+		if err != nil {
+			return nil, err
+		}
+		locked = append(locked, lockedBP)
+	}
+
+	return locked, nil
+}
+
+// lockedBuildpack returns the lock entry of bp, declared by uri. The digest is computed from the buildpack layer.
+func lockedBuildpack(uri string, bp buildpack.Buildpack) (projectTypes.LockedBuildpack, error) {
+	info := bp.Descriptor().Info
+
+	rc, err := bp.Open()
+	if err != nil {
+		return projectTypes.LockedBuildpack{}, errors.Wrapf(err, "opening buildpack %s", style.Symbol(info.FullName()))
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return projectTypes.LockedBuildpack{}, errors.Wrapf(err, "computing digest of buildpack %s", style.Symbol(info.FullName()))
+	}
+
+	return projectTypes.LockedBuildpack{
+		URI:     uri,
+		ID:      info.ID,
+		Version: info.Version,
+		Digest:  fmt.Sprintf("sha256:%x", hash.Sum(nil)),
+	}, nil
+}
+
+// verifyLock fails when Locked is set and the images and buildpacks resolved by the build differ from those
+// recorded in the lock file.
+func (c *Client) verifyLock(opts BuildOptions, lock projectTypes.Lock) error {
+	if !opts.Locked {
+		return nil
+	}
+
+	lockedLock, err := project.ReadLock(opts.LockFile)
+	if err != nil {
+		return errors.Wrapf(err, "reading lock file %s", style.Symbol(opts.LockFile))
+	}
+
+	diffs := lockDifferences(lockedLock, lock)
+	if len(diffs) > 0 {
+		return errors.Errorf("resolved images and buildpacks differ from lock file %s:\n- %s", style.Symbol(opts.LockFile), strings.Join(diffs, "\n- "))
+	}
+
+	c.logger.Debugf("Resolved images and buildpacks match lock file %s", style.Symbol(opts.LockFile))
+	return nil
+}
+
+// writeLock writes the lock file unless the build only verifies it.
+func (c *Client) writeLock(opts BuildOptions, lock projectTypes.Lock) error {
+	if opts.LockFile == "" || opts.Locked {
+		return nil
+	}
+
+	if err := project.WriteLock(opts.LockFile, lock); err != nil {
+		return errors.Wrapf(err, "writing lock file %s", style.Symbol(opts.LockFile))
+	}
+
+	c.logger.Infof("Wrote lock file %s", style.Symbol(opts.LockFile))
+	return nil
+}
+
+func lockDifferences(locked, resolved projectTypes.Lock) []string {
+	var diffs []string
+	diffImage := func(kind string, locked, resolved projectTypes.LockedImage) {
+		switch {
+		case locked.Image != resolved.Image:
+			diffs = append(diffs, fmt.Sprintf("%s %s is locked to %s", kind, style.Symbol(resolved.Image), style.Symbol(locked.Image)))
+		case locked.Digest != resolved.Digest:
+			diffs = append(diffs, fmt.Sprintf("%s %s resolved to %s, locked to %s", kind, style.Symbol(resolved.Image), style.Symbol(resolved.Digest), style.Symbol(locked.Digest)))
+		}
+	}
+
+	diffImage("builder", locked.Builder, resolved.Builder)
+	diffImage("run image", locked.RunImage, resolved.RunImage)
+
+	switch {
+	case locked.LifecycleImage == nil && resolved.LifecycleImage != nil:
+		diffs = append(diffs, fmt.Sprintf("lifecycle image %s is not locked", style.Symbol(resolved.LifecycleImage.Image)))
+	case locked.LifecycleImage != nil && resolved.LifecycleImage == nil:
+		diffs = append(diffs, fmt.Sprintf("locked lifecycle image %s is not used", style.Symbol(locked.LifecycleImage.Image)))
+	case locked.LifecycleImage != nil:
+		diffImage("lifecycle image", *locked.LifecycleImage, *resolved.LifecycleImage)
+	}
+
+	if len(locked.Buildpacks) != len(resolved.Buildpacks) {
+		return append(diffs, fmt.Sprintf("%d buildpacks resolved, %d locked", len(resolved.Buildpacks), len(locked.Buildpacks)))
+	}
+
+	for i, bp := range resolved.Buildpacks {
+		lockedBP := locked.Buildpacks[i]
+		resolvedName := fmt.Sprintf("%s@%s", bp.ID, bp.Version)
+		switch {
+		case lockedBP.URI != bp.URI || lockedBP.ID != bp.ID || lockedBP.Version != bp.Version:
+			diffs = append(diffs, fmt.Sprintf("buildpack %s is locked to %s", style.Symbol(resolvedName), style.Symbol(fmt.Sprintf("%s@%s", lockedBP.ID, lockedBP.Version))))
+		case lockedBP.Digest != bp.Digest:
+			diffs = append(diffs, fmt.Sprintf("buildpack %s resolved to %s, locked to %s", style.Symbol(resolvedName), style.Symbol(bp.Digest), style.Symbol(lockedBP.Digest)))
+		}
+	}
+
+	return diffs
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// ImageMetadata describes an image built or rebased by pack, as written to the metadata file of a build or rebase.
+// See BuildOptions.MetadataFile and RebaseOptions.MetadataFile.
+type ImageMetadata struct {
+	// Image is the name of the image.
+	Image string `json:"image"`
+
+	// Digest is the digest of the published image, or the ID of the image in the daemon.
+	Digest string `json:"digest"`
+
+	// AdditionalTags are the other names the image was saved to.
+	AdditionalTags []string `json:"additionalTags,omitempty"`
+
+	// RunImage is the run image the image is based on.
+	RunImage RunImageMetadata `json:"runImage"`
+
+	// Buildpacks are the buildpacks of the group selected to build the image, in order.
+	Buildpacks []BuildpackMetadata `json:"buildpacks"`
+
+	// Processes are the process types the image can launch.
+	Processes []ProcessMetadata `json:"processes"`
+
+	// SBOM lists the SBOM files written to BuildOptions.SBOMDestinationDir.
+	SBOM []string `json:"sbom,omitempty"`
+}
+
+// RunImageMetadata describes the run image of an image.
+type RunImageMetadata struct {
+	// Image is the name of the run image of the stack.
+	Image string `json:"image"`
+
+	// Reference is the digest reference of the run image used, or its ID in the daemon.
+	Reference string `json:"reference"`
+}
+
+// BuildpackMetadata describes a buildpack that built an image.
+type BuildpackMetadata struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	Homepage string `json:"homepage,omitempty"`
+}
+
+// ProcessMetadata describes a process type of an image.
+type ProcessMetadata struct {
+	Type    string   `json:"type"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// imageMetadata returns the metadata of img, saved as imageName and additionalTags, with the SBOM files of sbomDir.
+func imageMetadata(imageName string, img imgutil.Image, additionalTags []string, sbomDir string) (ImageMetadata, error) {
+	locked, err := lockedImage(imageName, img)
+	if err != nil {
+		return ImageMetadata{}, err
+	}
+
+	var layersMD platform.LayersMetadata
+	if _, err := dist.GetLabel(img, platform.LayerMetadataLabel, &layersMD); err != nil {
+		return ImageMetadata{}, err
+	}
+
+	var buildMD platform.BuildMetadata
+	if _, err := dist.GetLabel(img, platform.BuildMetadataLabel, &buildMD); err != nil {
+		return ImageMetadata{}, err
+	}
+
+	metadata := ImageMetadata{
+		Image:          imageName,
+		Digest:         locked.Digest,
+		AdditionalTags: additionalTags,
+		RunImage: RunImageMetadata{
+			Image:     layersMD.Stack.RunImage.Image,
+			Reference: layersMD.RunImage.Reference,
+		},
+		Buildpacks: []BuildpackMetadata{},
+		Processes:  []ProcessMetadata{},
+	}
+	for _, bp := range buildMD.Buildpacks {
+		metadata.Buildpacks = append(metadata.Buildpacks, BuildpackMetadata{ID: bp.ID, Version: bp.Version, Homepage: bp.Homepage})
+	}
+	for _, process := range buildMD.Processes {
+		metadata.Processes = append(metadata.Processes, ProcessMetadata{
+			Type:    process.Type,
+			Command: process.Command,
+			Args:    process.Args,
+		})
+	}
+
+	if sbomDir != "" {
+		err := filepath.Walk(sbomDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				metadata.SBOM = append(metadata.SBOM, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return ImageMetadata{}, errors.Wrapf(err, "listing SBOM files of %s", style.Symbol(sbomDir))
+		}
+	}
+
+	return metadata, nil
+}
+
+// writeMetadataFile writes metadata to path as JSON.
+func (c *Client) writeMetadataFile(path string, metadata ImageMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding image metadata")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "writing metadata file %s", style.Symbol(path))
+	}
+	c.logger.Infof("Wrote metadata file %s", style.Symbol(path))
+
+	return nil
+}
@@ -3,9 +3,11 @@ package client
 import (
 	"context"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 
 	pubbldpkg "github.com/buildpacks/pack/buildpackage"
+	"github.com/buildpacks/pack/internal/imageindex"
 	"github.com/buildpacks/pack/internal/layer"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
@@ -50,6 +52,9 @@ type PackageBuildpackOptions struct {
 	// Name of the buildpack registry. Used to
 	// add buildpacks to a package.
 	Registry string
+
+	// Path to a private key, in the format used by cosign, to sign the published package with.
+	SigningKey string
 }
 
 // PackageBuildpack packages buildpack(s) into either an image or file.
@@ -58,52 +63,101 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		opts.Format = FormatImage
 	}
 
+	signingKey, err := loadSigningKey(opts.SigningKey, opts.Publish && opts.Format == FormatImage)
+	if err != nil {
+		return err
+	}
+
+	digest, err := c.packageBuildpack(ctx, opts)
+	if err != nil || signingKey == nil {
+		return err
+	}
+
+	return c.signImages(signingKey, digest, opts.Name)
+}
+
+// packageBuildpack packages the buildpack, returning the digest of the package image when it is signed.
+func (c *Client) packageBuildpack(ctx context.Context, opts PackageBuildpackOptions) (string, error) {
+	if len(opts.Config.Targets) > 0 {
+		return c.packageBuildpackTargets(ctx, opts)
+	}
+
 	if opts.Config.Platform.OS == "windows" && !c.experimental {
-		return NewExperimentError("Windows buildpackage support is currently experimental.")
+		return "", NewExperimentError("Windows buildpackage support is currently experimental.")
 	}
 
 	err := c.validateOSPlatform(ctx, opts.Config.Platform.OS, opts.Publish, opts.Format)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	packageBuilder, err := c.newPackageBuilder(ctx, opts, opts.Config.Buildpack.URI, v1.Platform{OS: opts.Config.Platform.OS})
+	if err != nil {
+		return "", err
+	}
+
+	switch opts.Format {
+	case FormatFile:
+		return "", packageBuilder.SaveAsFile(opts.Name, opts.Config.Platform.OS)
+	case FormatImage:
+		img, err := packageBuilder.SaveAsImage(opts.Name, opts.Publish, opts.Config.Platform.OS)
+		if err != nil {
+			return "", errors.Wrap(err, "saving image")
+		}
+		if opts.SigningKey == "" {
+			return "", nil
+		}
+		return imageDigest(opts.Name, img)
+	default:
+		return "", errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
 	}
+}
 
-	writerFactory, err := layer.NewWriterFactory(opts.Config.Platform.OS)
+// newPackageBuilder creates a package builder for the buildpack at bpURI and the configured dependencies.
+// Dependencies are fetched for the architecture of the platform when it has one.
+func (c *Client) newPackageBuilder(ctx context.Context, opts PackageBuildpackOptions, bpURI string, platform v1.Platform) (*buildpack.PackageBuilder, error) {
+	writerFactory, err := layer.NewWriterFactory(platform.OS)
 	if err != nil {
-		return errors.Wrap(err, "creating layer writer factory")
+		return nil, errors.Wrap(err, "creating layer writer factory")
 	}
 
 	packageBuilder := buildpack.NewBuilder(c.imageFactory)
 
-	bpURI := opts.Config.Buildpack.URI
 	if bpURI == "" {
-		return errors.New("buildpack URI must be provided")
+		return nil, errors.New("buildpack URI must be provided")
 	}
 
 	mainBlob, err := c.downloadBuildpackFromURI(ctx, bpURI, opts.RelativeBaseDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	bp, err := buildpack.FromRootBlob(mainBlob, writerFactory)
 	if err != nil {
-		return errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bpURI))
+		return nil, errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bpURI))
 	}
 
 	packageBuilder.SetBuildpack(bp)
 
+	var depPlatform string
+	if platform.Architecture != "" {
+		depPlatform = imageindex.PlatformString(platform)
+	}
+
 	for _, dep := range opts.Config.Dependencies {
 		var depBPs []buildpack.Buildpack
 		mainBP, deps, err := c.buildpackDownloader.Download(ctx, dep.URI, buildpack.DownloadOptions{
 			RegistryName:    opts.Registry,
 			RelativeBaseDir: opts.RelativeBaseDir,
-			ImageOS:         opts.Config.Platform.OS,
+			ImageOS:         platform.OS,
+			Platform:        depPlatform,
 			ImageName:       dep.ImageName,
 			Daemon:          !opts.Publish,
 			PullPolicy:      opts.PullPolicy,
 		})
 
 		if err != nil {
-			return errors.Wrapf(err, "packaging dependencies (uri=%s,image=%s)", style.Symbol(dep.URI), style.Symbol(dep.ImageName))
+			return nil, errors.Wrapf(err, "packaging dependencies (uri=%s,image=%s)", style.Symbol(dep.URI), style.Symbol(dep.ImageName))
 		}
 
 		depBPs = append([]buildpack.Buildpack{mainBP}, deps...)
@@ -112,15 +166,7 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		}
 	}
 
-	switch opts.Format {
-	case FormatFile:
-		return packageBuilder.SaveAsFile(opts.Name, opts.Config.Platform.OS)
-	case FormatImage:
-		_, err = packageBuilder.SaveAsImage(opts.Name, opts.Publish, opts.Config.Platform.OS)
-		return errors.Wrapf(err, "saving image")
-	default:
-		return errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
-	}
+	return packageBuilder, nil
 }
 
 func (c *Client) downloadBuildpackFromURI(ctx context.Context, uri, relativeBaseDir string) (blob.Blob, error) {
@@ -144,7 +190,7 @@ func (c *Client) validateOSPlatform(ctx context.Context, os string, publish bool
 		return nil
 	}
 
-	info, err := c.docker.Info(ctx)
+	info, err := c.engine.Info(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/imageindex"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+)
+
+// packageBuildpackTargets packages the buildpack for each of the configured targets. Files contain a manifest per
+// target, published images are referenced by an image index, whose digest is returned. See buildpackage.Config.Targets.
+func (c *Client) packageBuildpackTargets(ctx context.Context, opts PackageBuildpackOptions) (string, error) {
+	targets := opts.Config.Targets
+
+	if opts.Format == FormatImage && !opts.Publish && len(targets) > 1 {
+		return "", errors.New("packaging multiple targets as an image requires publishing the image")
+	}
+
+	for _, target := range targets {
+		if target.OS == "windows" && !c.experimental {
+			return "", NewExperimentError("Windows buildpackage support is currently experimental.")
+		}
+
+		if err := c.validateOSPlatform(ctx, target.OS, opts.Publish, opts.Format); err != nil {
+			return "", err
+		}
+	}
+
+	var packages []buildpack.PlatformPackage
+	for _, target := range targets {
+		platform := v1.Platform{OS: target.OS, Architecture: target.Arch, Variant: target.Variant}
+
+		bpURI := target.URI
+		if bpURI == "" {
+			bpURI = opts.Config.Buildpack.URI
+		}
+
+		c.logger.Debugf("Packaging buildpack for target %s", style.Symbol(target.String()))
+		packageBuilder, err := c.newPackageBuilder(ctx, opts, bpURI, platform)
+		if err != nil {
+			return "", errors.Wrapf(err, "packaging for target %s", style.Symbol(target.String()))
+		}
+
+		packages = append(packages, buildpack.PlatformPackage{Builder: packageBuilder, Platform: platform})
+	}
+
+	switch opts.Format {
+	case FormatFile:
+		return "", buildpack.SavePlatformsAsFile(opts.Name, packages)
+	case FormatImage:
+		if !opts.Publish {
+			_, err := packages[0].Builder.SaveAsPlatformImage(opts.Name, false, packages[0].Platform)
+			return "", errors.Wrapf(err, "saving image")
+		}
+
+		return c.publishPackageIndex(opts.Name, packages)
+	default:
+		return "", errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
+	}
+}
+
+// publishPackageIndex publishes each package to a tag suffixed with its platform,
+// then publishes an image index referencing them to the tag of imageName. The digest of the index is returned.
+func (c *Client) publishPackageIndex(imageName string, packages []buildpack.PlatformPackage) (string, error) {
+	tag, err := name.NewTag(imageName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image name %s", style.Symbol(imageName))
+	}
+
+	var manifests []imageindex.Manifest
+	for _, pkg := range packages {
+		platformTag := imageindex.PlatformTag(tag, pkg.Platform)
+		if _, err := pkg.Builder.SaveAsPlatformImage(platformTag.Name(), true, pkg.Platform); err != nil {
+			return "", errors.Wrapf(err, "saving image for target %s", style.Symbol(imageindex.PlatformString(pkg.Platform)))
+		}
+
+		manifests = append(manifests, imageindex.Manifest{Ref: platformTag, Platform: pkg.Platform})
+	}
+
+	digest, err := imageindex.Publish(c.keychain, manifests, tag)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Infof("Published image index %s with digest %s", style.Symbol(tag.Name()), style.Symbol(digest.String()))
+	return digest.String(), nil
+}
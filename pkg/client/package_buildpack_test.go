@@ -3,8 +3,16 @@ package client_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
@@ -14,6 +22,11 @@ import (
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -22,6 +35,8 @@ import (
 	cfg "github.com/buildpacks/pack/internal/config"
 	ifakes "github.com/buildpacks/pack/internal/fakes"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/signature"
+	"github.com/buildpacks/pack/pkg/archive"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
@@ -774,6 +789,193 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("targets are configured", func() {
+		var (
+			linuxAMD64 = pubbldpkg.Target{Target: dist.Target{OS: "linux", Arch: "amd64"}}
+			linuxARM64 = pubbldpkg.Target{Target: dist.Target{OS: "linux", Arch: "arm64", Variant: "v8"}}
+			bpURI      string
+		)
+
+		it.Before(func() {
+			bpURI = createBuildpack(dist.BuildpackDescriptor{
+				API:    api.MustParse("0.2"),
+				Info:   dist.BuildpackInfo{ID: "bp.basic", Version: "2.3.4"},
+				Stacks: []dist.Stack{{ID: "some.stack.id"}},
+			})
+		})
+
+		when("FormatFile", func() {
+			it("writes a manifest per target", func() {
+				tmpDir, err := ioutil.TempDir("", "package-buildpack")
+				h.AssertNil(t, err)
+				defer os.RemoveAll(tmpDir)
+
+				armBPURI := createBuildpack(dist.BuildpackDescriptor{
+					API:    api.MustParse("0.2"),
+					Info:   dist.BuildpackInfo{ID: "bp.basic", Version: "2.3.4-arm"},
+					Stacks: []dist.Stack{{ID: "some.stack.id"}},
+				})
+				armTarget := linuxARM64
+				armTarget.URI = armBPURI
+
+				packagePath := filepath.Join(tmpDir, "test.cnb")
+				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+					Format: client.FormatFile,
+					Name:   packagePath,
+					Config: pubbldpkg.Config{
+						Buildpack: dist.BuildpackURI{URI: bpURI},
+						Targets:   []pubbldpkg.Target{linuxAMD64, armTarget},
+					},
+					PullPolicy: image.PullNever,
+				}))
+
+				_, contents, err := archive.ReadTarEntry(mustOpen(t, packagePath), "/index.json")
+				h.AssertNil(t, err)
+
+				var index ggcrv1.IndexManifest
+				h.AssertNil(t, json.Unmarshal(contents, &index))
+				h.AssertEq(t, len(index.Manifests), 2)
+				h.AssertEq(t, *index.Manifests[0].Platform, ggcrv1.Platform{OS: "linux", Architecture: "amd64"})
+				h.AssertEq(t, *index.Manifests[1].Platform, ggcrv1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+			})
+		})
+
+		when("FormatImage", func() {
+			var (
+				server       *httptest.Server
+				registryHost string
+			)
+
+			it.Before(func() {
+				server = httptest.NewServer(registry.New())
+				u, err := url.Parse(server.URL)
+				h.AssertNil(t, err)
+				registryHost = u.Host
+
+				subject, err = client.NewClient(
+					client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+					client.WithDownloader(mockDownloader),
+					client.WithDockerClient(mockDockerClient),
+				)
+				h.AssertNil(t, err)
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("publishes an image index referencing an image per target", func() {
+				imageName := registryHost + "/some/package:1.0"
+				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+					Format: client.FormatImage,
+					Name:   imageName,
+					Config: pubbldpkg.Config{
+						Buildpack: dist.BuildpackURI{URI: bpURI},
+						Targets:   []pubbldpkg.Target{linuxAMD64, linuxARM64},
+					},
+					Publish:    true,
+					PullPolicy: image.PullAlways,
+				}))
+
+				ref, err := name.ParseReference(imageName)
+				h.AssertNil(t, err)
+				index, err := remote.Index(ref)
+				h.AssertNil(t, err)
+
+				manifest, err := index.IndexManifest()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(manifest.Manifests), 2)
+				h.AssertEq(t, manifest.Manifests[0].Platform.Architecture, "amd64")
+				h.AssertEq(t, manifest.Manifests[1].Platform.Architecture, "arm64")
+				h.AssertEq(t, manifest.Manifests[1].Platform.Variant, "v8")
+
+				armRef, err := name.ParseReference(registryHost + "/some/package:1.0-linux-arm64-v8")
+				h.AssertNil(t, err)
+				armImage, err := remote.Image(armRef)
+				h.AssertNil(t, err)
+				configFile, err := armImage.ConfigFile()
+				h.AssertNil(t, err)
+				h.AssertEq(t, configFile.Architecture, "arm64")
+
+				h.AssertContains(t, out.String(), "Published image index '"+imageName+"'")
+			})
+
+			it("signs the published image index when a signing key is given", func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				h.AssertNil(t, err)
+				keyPath := writeSigningKey(t, key)
+				defer os.Remove(keyPath)
+
+				imageName := registryHost + "/some/package:1.0"
+				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+					Format: client.FormatImage,
+					Name:   imageName,
+					Config: pubbldpkg.Config{
+						Buildpack: dist.BuildpackURI{URI: bpURI},
+						Targets:   []pubbldpkg.Target{linuxAMD64, linuxARM64},
+					},
+					Publish:    true,
+					PullPolicy: image.PullAlways,
+					SigningKey: keyPath,
+				}))
+
+				ref, err := name.ParseReference(imageName)
+				h.AssertNil(t, err)
+				desc, err := remote.Get(ref)
+				h.AssertNil(t, err)
+				h.AssertNil(t, signature.Verify(ref.Context().Digest(desc.Digest.String()), &key.PublicKey, authn.DefaultKeychain))
+				h.AssertContains(t, out.String(), "Signed image '"+ref.Context().Digest(desc.Digest.String()).Name()+"'")
+			})
+
+			it("requires publishing for multiple targets", func() {
+				err := subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+					Format: client.FormatImage,
+					Name:   "some/package",
+					Config: pubbldpkg.Config{
+						Buildpack: dist.BuildpackURI{URI: bpURI},
+						Targets:   []pubbldpkg.Target{linuxAMD64, linuxARM64},
+					},
+				})
+				h.AssertError(t, err, "packaging multiple targets as an image requires publishing the image")
+			})
+		})
+
+		it("fails without experimental for Windows targets", func() {
+			err := subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+				Format: client.FormatFile,
+				Name:   "some.cnb",
+				Config: pubbldpkg.Config{
+					Buildpack: dist.BuildpackURI{URI: bpURI},
+					Targets:   []pubbldpkg.Target{{Target: dist.Target{OS: "windows", Arch: "amd64"}}},
+				},
+			})
+			h.AssertError(t, err, "Windows buildpackage support is currently experimental.")
+		})
+	})
+
+	when("a signing key is given", func() {
+		it("requires publishing", func() {
+			err := subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+				Format:     client.FormatFile,
+				Name:       "some.cnb",
+				Config:     pubbldpkg.Config{Platform: dist.Platform{OS: "linux"}},
+				SigningKey: "some-key",
+			})
+			h.AssertError(t, err, "signing requires publishing the image")
+		})
+
+		it("fails before packaging when the key is invalid", func() {
+			err := subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+				Format:     client.FormatImage,
+				Name:       "some/package",
+				Config:     pubbldpkg.Config{Platform: dist.Platform{OS: "linux"}},
+				Publish:    true,
+				SigningKey: "some-missing-key",
+			})
+			h.AssertError(t, err, "loading signing key")
+		})
+	})
+
 	when("unknown format is provided", func() {
 		it("should error", func() {
 			mockDockerClient.EXPECT().Info(context.TODO()).Return(types.Info{OSType: "linux"}, nil).AnyTimes()
@@ -803,3 +1005,26 @@ func assertPackageBPFileHasBuildpacks(t *testing.T, path string, descriptors []d
 	h.AssertNil(t, err)
 	h.AssertBuildpacksHaveDescriptors(t, append([]buildpack.Buildpack{mainBP}, depBPs...), descriptors)
 }
+
+func mustOpen(t *testing.T, path string) *os.File {
+	t.Helper()
+
+	f, err := os.Open(path)
+	h.AssertNil(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func writeSigningKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	h.AssertNil(t, err)
+
+	f, err := ioutil.TempFile("", "signing-key")
+	h.AssertNil(t, err)
+	defer f.Close()
+
+	h.AssertNil(t, pem.Encode(f, &pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	return f.Name()
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/provenance"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+const (
+	provenanceBuilderID = "https://buildpacks.io/pack"
+	provenanceBuildType = "https://buildpacks.io/pack/build@v1"
+)
+
+// buildProvenance holds what is known about a build before it runs, to describe it once the app image is exported.
+type buildProvenance struct {
+	startedOn        time.Time
+	builder          projectTypes.LockedImage
+	runImage         projectTypes.LockedImage
+	lifecycleVersion string
+	source           *platform.ProjectSource
+}
+
+// writesProvenance returns whether a provenance statement is written for the build.
+func writesProvenance(opts BuildOptions, prov *buildProvenance) bool {
+	return prov != nil && opts.ProvenanceFile != ""
+}
+
+// writeProvenance writes the provenance statement of the exported app image to opts.ProvenanceFile, and attaches it
+// to the published image.
+func (c *Client) writeProvenance(opts BuildOptions, imageRef name.Reference, img imgutil.Image, prov *buildProvenance) error {
+	if !writesProvenance(opts, prov) {
+		return nil
+	}
+
+	built, err := lockedImage(imageRef.Name(), img)
+	if err != nil {
+		return err
+	}
+
+	var buildMD platform.BuildMetadata
+	if _, err := dist.GetLabel(img, platform.BuildMetadataLabel, &buildMD); err != nil {
+		return err
+	}
+
+	statement, err := c.provenanceStatement(opts, imageRef, built.Digest, buildMD, *prov)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding provenance statement")
+	}
+	if err := ioutil.WriteFile(opts.ProvenanceFile, data, 0644); err != nil {
+		return errors.Wrapf(err, "writing provenance file %s", style.Symbol(opts.ProvenanceFile))
+	}
+	c.logger.Infof("Wrote provenance file %s", style.Symbol(opts.ProvenanceFile))
+
+	if !opts.Publish {
+		return nil
+	}
+
+	attTag, err := provenance.Attach(imageRef.Context().Digest(built.Digest), statement, c.keychain)
+	if err != nil {
+		return errors.Wrap(err, "attaching provenance")
+	}
+	c.logger.Infof("Attached provenance %s", style.Symbol(attTag.Name()))
+
+	return nil
+}
+
+func (c *Client) provenanceStatement(opts BuildOptions, imageRef name.Reference, digest string, buildMD platform.BuildMetadata, prov buildProvenance) (provenance.Statement, error) {
+	subjectDigest, err := provenance.DigestSet(digest)
+	if err != nil {
+		return provenance.Statement{}, err
+	}
+
+	var buildpacks []map[string]string
+	for _, bp := range buildMD.Buildpacks {
+		buildpacks = append(buildpacks, map[string]string{"id": bp.ID, "version": bp.Version})
+	}
+
+	var materials []provenance.Material
+	for _, img := range []projectTypes.LockedImage{prov.builder, prov.runImage} {
+		material := provenance.Material{URI: img.Image}
+		if digestSet, err := provenance.DigestSet(img.Digest); err == nil {
+			material.Digest = digestSet
+		}
+		materials = append(materials, material)
+	}
+
+	var configSource provenance.ConfigSource
+	if commit, ok := gitCommit(prov.source); ok {
+		if remoteURL, ok := prov.source.Metadata["url"].(string); ok {
+			configSource.URI = "git+" + remoteURL
+			if branch, ok := prov.source.Metadata["branch"].(string); ok {
+				configSource.URI += "@refs/heads/" + branch
+			}
+			materials = append(materials, provenance.Material{URI: "git+" + remoteURL, Digest: map[string]string{"sha1": commit}})
+		}
+		configSource.Digest = map[string]string{"sha1": commit}
+	}
+
+	finishedOn := time.Now().UTC()
+	startedOn := prov.startedOn.UTC()
+
+	return provenance.Statement{
+		Type: provenance.StatementType,
+		Subject: []provenance.Subject{
+			{Name: imageRef.Context().Name(), Digest: subjectDigest},
+		},
+		PredicateType: provenance.PredicateType,
+		Predicate: provenance.Predicate{
+			Builder:   provenance.Builder{ID: provenanceBuilderID + "@" + c.version},
+			BuildType: provenanceBuildType,
+			Invocation: provenance.Invocation{
+				ConfigSource: configSource,
+				Parameters:   provenanceParameters(opts),
+			},
+			BuildConfig: map[string]interface{}{
+				"builder":          prov.builder,
+				"runImage":         prov.runImage,
+				"lifecycleVersion": prov.lifecycleVersion,
+				"buildpacks":       buildpacks,
+				"source":           prov.source,
+			},
+			Metadata: provenance.Metadata{
+				BuildStartedOn:  &startedOn,
+				BuildFinishedOn: &finishedOn,
+			},
+			Materials: materials,
+		},
+	}, nil
+}
+
+// provenanceParameters returns the options the build was configured with. Only the names of environment variables
+// and the IDs of secrets are recorded, since their values may be sensitive.
+func provenanceParameters(opts BuildOptions) map[string]interface{} {
+	params := map[string]interface{}{
+		"image":   opts.Image,
+		"builder": opts.Builder,
+		"publish": opts.Publish,
+	}
+
+	if opts.RunImage != "" {
+		params["runImage"] = opts.RunImage
+	}
+	if len(opts.AdditionalTags) > 0 {
+		params["additionalTags"] = opts.AdditionalTags
+	}
+	if len(opts.Buildpacks) > 0 {
+		params["buildpacks"] = opts.Buildpacks
+	}
+	if len(opts.Platforms) > 0 {
+		params["platforms"] = opts.Platforms
+	}
+	if opts.DefaultProcessType != "" {
+		params["defaultProcessType"] = opts.DefaultProcessType
+	}
+	if opts.ContainerConfig.Network != "" {
+		params["network"] = opts.ContainerConfig.Network
+	}
+	if opts.ClearCache {
+		params["clearCache"] = true
+	}
+	if len(opts.Labels) > 0 {
+		params["labels"] = opts.Labels
+	}
+
+	var envNames []string
+	for key := range opts.Env {
+		envNames = append(envNames, key)
+	}
+	if len(envNames) > 0 {
+		sort.Strings(envNames)
+		params["env"] = envNames
+	}
+
+	var secretIDs []string
+	for _, secret := range opts.Secrets {
+		secretIDs = append(secretIDs, secret.ID)
+	}
+	if len(secretIDs) > 0 {
+		params["secrets"] = secretIDs
+	}
+
+	return params
+}
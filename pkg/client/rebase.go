@@ -34,6 +34,9 @@ type RebaseOptions struct {
 	// AdditionalMirrors gives us inputs to recalculate the 'best' run image
 	// based on the registry we are publishing to.
 	AdditionalMirrors map[string][]string
+
+	// MetadataFile is the path to write the metadata of the rebased image to, as JSON. See ImageMetadata.
+	MetadataFile string
 }
 
 // Rebase updates the run image layers in an app image.
@@ -44,7 +47,8 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 		return errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
 	}
 
-	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, image.FetchOptions{Daemon: !opts.Publish, PullPolicy: opts.PullPolicy})
+	// the app image was built by pack, only the run image it is rebased on is verified
+	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, image.FetchOptions{Daemon: !opts.Publish, PullPolicy: opts.PullPolicy, SkipVerification: true})
 	if err != nil {
 		return err
 	}
@@ -91,5 +95,15 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 	}
 
 	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
+
+	if opts.MetadataFile != "" {
+		metadata, err := imageMetadata(imageRef.Name(), appImage, nil, "")
+		if err != nil {
+			return err
+		}
+
+		return c.writeMetadataFile(opts.MetadataFile, metadata)
+	}
+
 	return nil
 }
@@ -3,6 +3,10 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/buildpacks/imgutil/fakes"
@@ -219,6 +223,36 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("a metadata file is provided", func() {
+			var tmpDir string
+
+			it.Before(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "rebase-metadata")
+				h.AssertNil(t, err)
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(tmpDir))
+			})
+
+			it("writes the metadata of the rebased image", func() {
+				metadataFile := filepath.Join(tmpDir, "metadata.json")
+				h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+					RepoName:     "some/app",
+					MetadataFile: metadataFile,
+				}))
+
+				data, err := ioutil.ReadFile(metadataFile)
+				h.AssertNil(t, err)
+				var metadata ImageMetadata
+				h.AssertNil(t, json.Unmarshal(data, &metadata))
+				h.AssertEq(t, metadata.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, metadata.Digest, "app-image")
+				h.AssertEq(t, metadata.RunImage, RunImageMetadata{Image: "some/run", Reference: "run-image-digest"})
+			})
+		})
 	})
 }
 
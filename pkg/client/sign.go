@@ -0,0 +1,61 @@
+package client
+
+import (
+	"crypto"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/signature"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// loadSigningKey loads the private key at keyPath, so that invalid keys are reported before images are created.
+// No key is returned when keyPath is empty.
+func loadSigningKey(keyPath string, publish bool) (crypto.Signer, error) {
+	if keyPath == "" {
+		return nil, nil
+	}
+
+	if !publish {
+		return nil, errors.New("signing requires publishing the image")
+	}
+
+	key, err := signature.LoadPrivateKey(keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading signing key")
+	}
+
+	return key, nil
+}
+
+// signImages signs the image with digest, published as each of imageNames, with key, publishing cosign compatible
+// signatures next to it. The digest is the one pack published, the names aren't resolved again as their tags may have
+// been moved since.
+func (c *Client) signImages(key crypto.Signer, digest string, imageNames ...string) error {
+	for _, imageName := range imageNames {
+		ref, err := name.ParseReference(imageName, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid image name %s", style.Symbol(imageName))
+		}
+
+		signed := ref.Context().Digest(digest)
+		if err := signature.Sign(signed, key, c.keychain); err != nil {
+			return errors.Wrapf(err, "signing image %s", style.Symbol(imageName))
+		}
+
+		c.logger.Infof("Signed image %s", style.Symbol(signed.Name()))
+	}
+
+	return nil
+}
+
+// imageDigest returns the digest of img, published as imageName.
+func imageDigest(imageName string, img imgutil.Image) (string, error) {
+	locked, err := lockedImage(imageName, img)
+	if err != nil {
+		return "", err
+	}
+	return locked.Digest, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// withTimeout returns a copy of ctx that is done once timeout has passed. A zero timeout never ends the context.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// asTimeoutError returns a TimeoutError when err results from a phase timing out, or from ctx, which ends after
+// timeout, timing out. Other errors are returned as is.
+func asTimeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	var phaseTimeout build.PhaseTimeoutError
+	if errors.As(err, &phaseTimeout) {
+		return TimeoutError{Phase: phaseTimeout.Phase, Timeout: phaseTimeout.Timeout}
+	}
+
+	if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return TimeoutError{Timeout: timeout}
+	}
+
+	return err
+}
+
+// hasSeparatePhaseTimeouts returns whether phaseTimeouts has a timeout for a phase run in its own container, which
+// the creator running all phases in a single container would ignore.
+func hasSeparatePhaseTimeouts(phaseTimeouts map[string]time.Duration) bool {
+	for phase := range phaseTimeouts {
+		if phase != "create" {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePhaseTimeouts(phaseTimeouts map[string]time.Duration) error {
+	for phase, timeout := range phaseTimeouts {
+		if !contains(build.PhaseNames, phase) {
+			return errors.Errorf("unknown phase %s in phase timeouts, accepted phases are %s", style.Symbol(phase), strings.Join(build.PhaseNames, ", "))
+		}
+		if timeout <= 0 {
+			return errors.Errorf("timeout of phase %s must be positive", style.Symbol(phase))
+		}
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package dist
 
 import (
 	"github.com/buildpacks/lifecycle/api"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/buildpacks/pack/internal/imageindex"
 )
 
 const BuildpackLayersLabel = "io.buildpacks.buildpack.layers"
@@ -31,6 +34,18 @@ type Platform struct {
 	OS string `toml:"os"`
 }
 
+// Target is an operating system and architecture, with an optional variant, that an image is built for.
+type Target struct {
+	OS      string `toml:"os"`
+	Arch    string `toml:"arch"`
+	Variant string `toml:"variant,omitempty"`
+}
+
+// String returns the target in the form 'os/arch[/variant]'.
+func (t Target) String() string {
+	return imageindex.PlatformString(v1.Platform{OS: t.OS, Architecture: t.Arch, Variant: t.Variant})
+}
+
 type Order []OrderEntry
 
 type OrderEntry struct {
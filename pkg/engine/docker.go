@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/docker/docker/client"
+)
+
+// Docker is the container engine of a Docker daemon.
+type Docker struct {
+	client.CommonAPIClient
+}
+
+// NewDocker returns the container engine of the Docker daemon that docker connects to.
+func NewDocker(docker client.CommonAPIClient) *Docker {
+	return &Docker{CommonAPIClient: docker}
+}
+
+func (d *Docker) Name() string {
+	return "docker"
+}
+
+// PreservesOwnership always returns true, as Docker keeps the ownership of copied files, including in rootless mode.
+func (d *Docker) PreservesOwnership(_ context.Context) (bool, error) {
+	return true, nil
+}
+
+func (d *Docker) APIClient() client.CommonAPIClient {
+	return d.CommonAPIClient
+}
@@ -0,0 +1,54 @@
+// Package engine provides the container engines, such as Docker and Podman, used by pack to run the lifecycle.
+package engine
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ContainerEngine is the API of a container engine used by pack to run lifecycle phases and manage their volumes
+// and images. Its methods follow the Docker Engine API, which Podman serves as well.
+type ContainerEngine interface {
+	ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error)
+	ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error)
+	ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error
+	ContainerWait(ctx context.Context, container string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error)
+	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
+	ContainerStop(ctx context.Context, container string, timeout *time.Duration) error
+	ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error
+	CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error
+	CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
+
+	VolumeCreate(ctx context.Context, options volume.VolumeCreateBody) (types.Volume, error)
+	VolumeList(ctx context.Context, filter filters.Args) (volume.VolumeListOKBody, error)
+	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+
+	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
+	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
+	ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error)
+	ImageRemove(ctx context.Context, image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+
+	Info(ctx context.Context) (types.Info, error)
+	DiskUsage(ctx context.Context) (types.DiskUsage, error)
+
+	// Name returns the name of the engine, such as 'docker' or 'podman'.
+	Name() string
+
+	// PreservesOwnership reports whether files copied into containers keep the ownership set in the copied archive,
+	// and whether the user containers run as can write to the volumes they mount. When it doesn't, as with rootless
+	// Podman, pack changes the ownership from a helper container running as root.
+	PreservesOwnership(ctx context.Context) (bool, error)
+
+	// APIClient returns a client of the Docker compatible API of the engine, for libraries requiring one, such as
+	// imgutil for images on the daemon.
+	APIClient() client.CommonAPIClient
+}
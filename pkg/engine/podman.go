@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PodmanHostEnvVar is the environment variable Podman reads the address of its API socket from.
+const PodmanHostEnvVar = "CONTAINER_HOST"
+
+// podmanAPIVersion is the version of the libpod API used to query Podman specific information.
+const podmanAPIVersion = "v3.0.0"
+
+// Podman is the container engine of a Podman API service: the Docker client pointed at the Podman socket, as Podman
+// serves the Docker compatible API. Only whether the service runs rootless is read from the libpod API.
+type Podman struct {
+	client.CommonAPIClient
+
+	apiURL     string
+	httpClient *http.Client
+
+	rootlessMu    sync.Mutex
+	rootlessKnown bool
+	rootless      bool
+}
+
+// NewPodman returns the container engine of the Podman API service listening at host, such as
+// 'unix:///run/user/1000/podman/podman.sock'. When host is empty, the socket is discovered with PodmanSocket.
+// Additional options configure the Docker compatible client, such as its API version.
+func NewPodman(host string, opts ...client.Opt) (*Podman, error) {
+	if host == "" {
+		var err error
+		if host, err = PodmanSocket(); err != nil {
+			return nil, err
+		}
+	}
+
+	hostURL, err := client.ParseHostURL(host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing podman host %s", style.Symbol(host))
+	}
+
+	docker, err := client.NewClientWithOpts(append([]client.Opt{client.WithHost(host)}, opts...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating podman client")
+	}
+
+	// the transport of the client dials the socket of unix hosts whatever the host of the request url
+	apiURL := "http://podman"
+	if hostURL.Scheme == "tcp" {
+		apiURL = "http://" + hostURL.Host
+	}
+
+	return &Podman{
+		CommonAPIClient: docker,
+		apiURL:          apiURL,
+		httpClient:      docker.HTTPClient(),
+	}, nil
+}
+
+// PodmanSocket returns the address of the Podman API socket, read from the CONTAINER_HOST environment variable or
+// found at the default locations of the sockets of rootless and rootful Podman services.
+func PodmanSocket() (string, error) {
+	if host := os.Getenv(PodmanHostEnvVar); host != "" {
+		return host, nil
+	}
+
+	var candidates []string
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates,
+		filepath.Join("/run", "user", fmt.Sprint(os.Getuid()), "podman", "podman.sock"),
+		filepath.Join("/run", "podman", "podman.sock"),
+	)
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return "unix://" + candidate, nil
+		}
+	}
+
+	return "", errors.Errorf("no podman socket found, start the podman service with %s or set %s", style.Symbol("systemctl --user start podman.socket"), style.Symbol(PodmanHostEnvVar))
+}
+
+func (p *Podman) Name() string {
+	return "podman"
+}
+
+// PreservesOwnership returns false for rootless Podman, which maps the root user of containers to the user running
+// the service. Files copied into containers are then owned by root, and volumes are created owned by root as well.
+func (p *Podman) PreservesOwnership(ctx context.Context) (bool, error) {
+	rootless, err := p.Rootless(ctx)
+	return !rootless, err
+}
+
+// Rootless reports whether the Podman service runs as an unprivileged user. The result is queried until a query
+// succeeds, so that the error of a cancelled query isn't returned to later callers.
+func (p *Podman) Rootless(ctx context.Context) (bool, error) {
+	p.rootlessMu.Lock()
+	defer p.rootlessMu.Unlock()
+
+	if !p.rootlessKnown {
+		rootless, err := p.queryRootless(ctx)
+		if err != nil {
+			return false, err
+		}
+		p.rootless, p.rootlessKnown = rootless, true
+	}
+	return p.rootless, nil
+}
+
+func (p *Podman) queryRootless(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/libpod/info", p.apiURL, podmanAPIVersion), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, errors.Wrap(err, "querying podman info")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("querying podman info: unexpected status %s", resp.Status)
+	}
+
+	var info struct {
+		Host struct {
+			Security struct {
+				Rootless bool `json:"rootless"`
+			} `json:"security"`
+		} `json:"host"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return false, errors.Wrap(err, "decoding podman info")
+	}
+
+	return info.Host.Security.Rootless, nil
+}
+
+func (p *Podman) APIClient() client.CommonAPIClient {
+	return p.CommonAPIClient
+}
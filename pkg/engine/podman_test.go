@@ -0,0 +1,185 @@
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/engine"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPodman(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Podman", testPodman, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testPodman(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir              string
+		oldContainerHost    string
+		oldXDGRuntimeDir    string
+		hadContainerHost    bool
+		hadXDGRuntimeDirVar bool
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "podman")
+		h.AssertNil(t, err)
+
+		oldContainerHost, hadContainerHost = os.LookupEnv(engine.PodmanHostEnvVar)
+		oldXDGRuntimeDir, hadXDGRuntimeDirVar = os.LookupEnv("XDG_RUNTIME_DIR")
+		h.AssertNil(t, os.Unsetenv(engine.PodmanHostEnvVar))
+		h.AssertNil(t, os.Setenv("XDG_RUNTIME_DIR", tmpDir))
+	})
+
+	it.After(func() {
+		if hadContainerHost {
+			h.AssertNil(t, os.Setenv(engine.PodmanHostEnvVar, oldContainerHost))
+		} else {
+			h.AssertNil(t, os.Unsetenv(engine.PodmanHostEnvVar))
+		}
+		if hadXDGRuntimeDirVar {
+			h.AssertNil(t, os.Setenv("XDG_RUNTIME_DIR", oldXDGRuntimeDir))
+		} else {
+			h.AssertNil(t, os.Unsetenv("XDG_RUNTIME_DIR"))
+		}
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#PodmanSocket", func() {
+		it("reads the host from CONTAINER_HOST", func() {
+			h.AssertNil(t, os.Setenv(engine.PodmanHostEnvVar, "tcp://localhost:8888"))
+
+			host, err := engine.PodmanSocket()
+			h.AssertNil(t, err)
+			h.AssertEq(t, host, "tcp://localhost:8888")
+		})
+
+		it("finds the socket of the rootless service", func() {
+			socket := filepath.Join(tmpDir, "podman", "podman.sock")
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(socket), 0755))
+			h.AssertNil(t, ioutil.WriteFile(socket, nil, 0600))
+
+			host, err := engine.PodmanSocket()
+			h.AssertNil(t, err)
+			h.AssertEq(t, host, "unix://"+socket)
+		})
+
+		it("fails when no socket is found", func() {
+			if _, err := os.Stat("/run/podman/podman.sock"); err == nil {
+				t.Skip("podman service is running")
+			}
+			if _, err := os.Stat(fmt.Sprintf("/run/user/%d/podman/podman.sock", os.Getuid())); err == nil {
+				t.Skip("podman service is running")
+			}
+
+			_, err := engine.PodmanSocket()
+			h.AssertError(t, err, "no podman socket found")
+		})
+	})
+
+	when("#Rootless", func() {
+		var (
+			server   *httptest.Server
+			rootless bool
+			requests int
+		)
+
+		it.Before(func() {
+			requests = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v3.0.0/libpod/info" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				requests++
+				fmt.Fprintf(w, `{"host": {"security": {"rootless": %t}}}`, rootless)
+			}))
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		newPodman := func() *engine.Podman {
+			podman, err := engine.NewPodman("tcp://" + strings.TrimPrefix(server.URL, "http://"))
+			h.AssertNil(t, err)
+			return podman
+		}
+
+		it("doesn't preserve ownership for rootless services", func() {
+			rootless = true
+			podman := newPodman()
+
+			preserves, err := podman.PreservesOwnership(context.TODO())
+			h.AssertNil(t, err)
+			h.AssertFalse(t, preserves)
+		})
+
+		it("preserves ownership for rootful services", func() {
+			rootless = false
+			podman := newPodman()
+
+			preserves, err := podman.PreservesOwnership(context.TODO())
+			h.AssertNil(t, err)
+			h.AssertTrue(t, preserves)
+		})
+
+		it("queries the service once", func() {
+			rootless = true
+			podman := newPodman()
+
+			_, err := podman.Rootless(context.TODO())
+			h.AssertNil(t, err)
+			_, err = podman.Rootless(context.TODO())
+			h.AssertNil(t, err)
+			h.AssertEq(t, requests, 1)
+		})
+
+		it("fails when the service errors", func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			podman := newPodman()
+
+			_, err := podman.Rootless(context.TODO())
+			h.AssertError(t, err, "querying podman info: unexpected status 500")
+		})
+
+		it("queries the service again after a cancelled query", func() {
+			rootless = true
+			podman := newPodman()
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			_, err := podman.Rootless(ctx)
+			h.AssertNotNil(t, err)
+
+			isRootless, err := podman.Rootless(context.TODO())
+			h.AssertNil(t, err)
+			h.AssertTrue(t, isRootless)
+			h.AssertEq(t, requests, 1)
+		})
+	})
+
+	when("#Name", func() {
+		it("is podman", func() {
+			podman, err := engine.NewPodman("unix:///some/podman.sock")
+			h.AssertNil(t, err)
+			h.AssertEq(t, podman.Name(), "podman")
+		})
+	})
+}
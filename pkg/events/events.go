@@ -0,0 +1,97 @@
+// Package events defines the typed events emitted while an app image is being built.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// Type identifies the kind of an Event.
+type Type string
+
+const (
+	// PhaseStarted is emitted right before a lifecycle phase container is started.
+	PhaseStarted Type = "phase-started"
+	// PhaseFinished is emitted once a lifecycle phase container has exited.
+	PhaseFinished Type = "phase-finished"
+	// BuildpackDetected is emitted for each buildpack in the group selected during detection.
+	BuildpackDetected Type = "buildpack-detected"
+	// LayerExported is emitted for each layer recorded on the exported app image.
+	LayerExported Type = "layer-exported"
+	// ImageExported is emitted once the app image has been exported.
+	ImageExported Type = "image-exported"
+)
+
+// Event describes something that happened during a build.
+// Only the fields relevant to the event Type are populated.
+type Event struct {
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Phase is the name of the lifecycle phase the event relates to, e.g. `detector`.
+	Phase string `json:"phase,omitempty"`
+
+	// ExitCode is the exit code of the phase container.
+	// It is nil when the container did not exit normally.
+	ExitCode *int64 `json:"exitCode,omitempty"`
+
+	// Duration is the wall-clock time taken by the phase.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Stats describes the resources used by the phase.
+	Stats *PhaseStats `json:"stats,omitempty"`
+
+	// Error is the error message of a failed phase.
+	Error string `json:"error,omitempty"`
+
+	// Buildpack is the buildpack that was detected, or that contributed a layer.
+	Buildpack *dist.BuildpackInfo `json:"buildpack,omitempty"`
+
+	// Layer is the name of an exported layer.
+	Layer string `json:"layer,omitempty"`
+
+	// Image is the name of the exported image.
+	Image string `json:"image,omitempty"`
+
+	// Digest is the digest of the exported layer or image.
+	// For images exported to a daemon this is the image ID.
+	Digest string `json:"digest,omitempty"`
+}
+
+// PhaseStats describes the time and resources spent running a lifecycle phase container.
+type PhaseStats struct {
+	// ContainerCreate is the time taken to create the phase container.
+	ContainerCreate time.Duration `json:"containerCreate"`
+
+	// ContainerStart is the time taken to start the phase container.
+	ContainerStart time.Duration `json:"containerStart"`
+
+	// ContainerWait is the time spent waiting for the phase container to exit once started.
+	ContainerWait time.Duration `json:"containerWait"`
+
+	// BytesCopied is the number of bytes copied into the phase container, such as the app source.
+	BytesCopied int64 `json:"bytesCopied"`
+
+	// LayersSize is the size in bytes of the layers volume once the phase finished.
+	// It is only measured when requested, as it requires querying the disk usage of the daemon.
+	LayersSize int64 `json:"layersSize,omitempty"`
+}
+
+// Handler receives build events as they happen.
+type Handler func(Event)
+
+// NewJSONLHandler returns a Handler that writes each event to w as a single line of JSON.
+func NewJSONLHandler(w io.Writer) Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_ = encoder.Encode(e)
+	}
+}
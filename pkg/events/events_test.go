@@ -0,0 +1,58 @@
+package events_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestEvents(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Events", testEvents, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testEvents(t *testing.T, when spec.G, it spec.S) {
+	var (
+		assert    = h.NewAssertionManager(t)
+		eventTime = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	when("#NewJSONLHandler", func() {
+		it("writes one JSON document per line", func() {
+			var w bytes.Buffer
+			exitCode := int64(0)
+
+			handler := events.NewJSONLHandler(&w)
+			handler(events.Event{Type: events.PhaseStarted, Time: eventTime, Phase: "detector"})
+			handler(events.Event{Type: events.PhaseFinished, Time: eventTime, Phase: "detector", ExitCode: &exitCode, Duration: time.Second})
+
+			lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+			assert.Equal(len(lines), 2)
+			assert.EqualJSON(lines[0], `{"type":"phase-started","time":"2022-01-01T00:00:00Z","phase":"detector"}`)
+			assert.EqualJSON(lines[1], `{"type":"phase-finished","time":"2022-01-01T00:00:00Z","phase":"detector","exitCode":0,"duration":1000000000}`)
+		})
+
+		it("includes buildpack information", func() {
+			var w bytes.Buffer
+
+			handler := events.NewJSONLHandler(&w)
+			handler(events.Event{
+				Type:      events.BuildpackDetected,
+				Time:      eventTime,
+				Buildpack: &dist.BuildpackInfo{ID: "some/bp", Version: "1.2.3"},
+			})
+
+			assert.EqualJSON(w.String(), `{"type":"buildpack-detected","time":"2022-01-01T00:00:00Z","buildpack":{"id":"some/bp","version":"1.2.3"}}`)
+		})
+	})
+}
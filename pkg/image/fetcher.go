@@ -15,6 +15,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	pname "github.com/buildpacks/pack/internal/name"
@@ -34,16 +35,33 @@ func WithRegistryMirrors(registryMirrors map[string]string) FetcherOption {
 	}
 }
 
+// WithVerifier verifies the signatures of the images that are fetched.
+func WithVerifier(verifier Verifier) FetcherOption {
+	return func(c *Fetcher) {
+		c.verifier = verifier
+	}
+}
+
+// Verifier verifies the signature of an image before it is used.
+type Verifier interface {
+	// Verify returns an error when the image fetched as imageName, resolved to digest, is not trusted.
+	Verify(imageName string, digest name.Digest) error
+}
+
 type Fetcher struct {
 	docker          client.CommonAPIClient
 	logger          logging.Logger
 	registryMirrors map[string]string
+	verifier        Verifier
 }
 
 type FetchOptions struct {
 	Daemon     bool
 	Platform   string
 	PullPolicy PullPolicy
+	// SkipVerification fetches the image without verifying its signature, for images built by pack itself, such as
+	// the app image after it is exported, rather than images used to build.
+	SkipVerification bool
 }
 
 func NewFetcher(logger logging.Logger, docker client.CommonAPIClient, opts ...FetcherOption) *Fetcher {
@@ -67,8 +85,21 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 		return nil, err
 	}
 
+	img, err := f.fetch(ctx, name, options)
+	if err != nil || f.verifier == nil || options.SkipVerification {
+		return img, err
+	}
+
+	if err := f.verify(ctx, name, img, options.Daemon); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+func (f *Fetcher) fetch(ctx context.Context, name string, options FetchOptions) (imgutil.Image, error) {
 	if !options.Daemon {
-		return f.fetchRemoteImage(name)
+		return f.fetchRemoteImage(name, options.Platform)
 	}
 
 	switch options.PullPolicy {
@@ -77,13 +108,16 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 		return img, err
 	case PullIfNotPresent:
 		img, err := f.fetchDaemonImage(name)
-		if err == nil || !errors.Is(err, ErrNotFound) {
+		// the daemon stores a single platform per tag, pull again when the local image was pulled for another platform
+		if err == nil && !matchesPlatform(img, options.Platform) {
+			f.logger.Debugf("Image %s on the daemon does not match platform %s", style.Symbol(name), style.Symbol(options.Platform))
+		} else if err == nil || !errors.Is(err, ErrNotFound) {
 			return img, err
 		}
 	}
 
 	f.logger.Debugf("Pulling image %s", style.Symbol(name))
-	err = f.pullImage(ctx, name, options.Platform)
+	err := f.pullImage(ctx, name, options.Platform)
 	if err != nil && !errors.Is(err, ErrNotFound) {
 		return nil, err
 	}
@@ -104,8 +138,17 @@ func (f *Fetcher) fetchDaemonImage(name string) (imgutil.Image, error) {
 	return image, nil
 }
 
-func (f *Fetcher) fetchRemoteImage(name string) (imgutil.Image, error) {
-	image, err := remote.NewImage(name, authn.DefaultKeychain, remote.FromBaseImage(name))
+func (f *Fetcher) fetchRemoteImage(name string, platform string) (imgutil.Image, error) {
+	opts := []remote.ImageOption{remote.FromBaseImage(name)}
+	if platform != "" {
+		parts := strings.Split(platform, "/")
+		if len(parts) < 2 {
+			return nil, errors.Errorf("invalid platform %s, expected the form os/arch[/variant]", style.Symbol(platform))
+		}
+		opts = append(opts, remote.WithDefaultPlatform(imgutil.Platform{OS: parts[0], Architecture: parts[1]}))
+	}
+
+	image, err := remote.NewImage(name, authn.DefaultKeychain, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +160,72 @@ func (f *Fetcher) fetchRemoteImage(name string) (imgutil.Image, error) {
 	return image, nil
 }
 
+// verify verifies the signature of img, using its digest in the registry.
+// Images on the daemon are resolved to the digest they were pulled with.
+func (f *Fetcher) verify(ctx context.Context, imageName string, img imgutil.Image, daemon bool) error {
+	digest, err := f.imageDigest(ctx, imageName, img, daemon)
+	if err != nil {
+		return errors.Wrapf(err, "verifying image %s", style.Symbol(imageName))
+	}
+
+	f.logger.Debugf("Verifying signature of image %s", style.Symbol(digest.Name()))
+	if err := f.verifier.Verify(imageName, digest); err != nil {
+		return errors.Wrapf(err, "verifying image %s", style.Symbol(imageName))
+	}
+
+	return nil
+}
+
+func (f *Fetcher) imageDigest(ctx context.Context, imageName string, img imgutil.Image, daemon bool) (name.Digest, error) {
+	if !daemon {
+		identifier, err := img.Identifier()
+		if err != nil {
+			return name.Digest{}, err
+		}
+
+		return name.NewDigest(identifier.String(), name.WeakValidation)
+	}
+
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	inspect, _, err := f.docker.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		digest, err := name.NewDigest(repoDigest, name.WeakValidation)
+		if err == nil && digest.Context().Name() == ref.Context().Name() {
+			return digest, nil
+		}
+	}
+
+	return name.Digest{}, errors.New("the image has no registry digest, images must be pulled from a registry to be verified")
+}
+
+// matchesPlatform returns whether the OS and architecture of img match a platform in the form 'os/arch[/variant]'.
+// Any image matches an empty platform.
+func matchesPlatform(img imgutil.Image, platform string) bool {
+	if platform == "" {
+		return true
+	}
+
+	imgOS, err := img.OS()
+	if err != nil {
+		return false
+	}
+	imgArch, err := img.Architecture()
+	if err != nil {
+		return false
+	}
+
+	parts := strings.Split(platform, "/")
+	return len(parts) >= 2 && parts[0] == imgOS && parts[1] == imgArch
+}
+
 func (f *Fetcher) pullImage(ctx context.Context, imageID string, platform string) error {
 	regAuth, err := registryAuth(imageID)
 	if err != nil {
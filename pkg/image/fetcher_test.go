@@ -222,8 +222,9 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 			when("PullIfNotPresent", func() {
 				when("there is a remote image", func() {
 					var (
-						label          = "label"
-						remoteImgLabel string
+						label             = "label"
+						remoteImgLabel    string
+						remoteImgPlatform string
 					)
 
 					it.Before(func() {
@@ -241,6 +242,12 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 						remoteImgLabel, err = remoteImg.Label(label)
 						h.AssertNil(t, err)
+
+						remoteImgOS, err := remoteImg.OS()
+						h.AssertNil(t, err)
+						remoteImgArch, err := remoteImg.Architecture()
+						h.AssertNil(t, err)
+						remoteImgPlatform = fmt.Sprintf("%s/%s", remoteImgOS, remoteImgArch)
 					})
 
 					it.After(func() {
@@ -278,6 +285,29 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 						})
 					})
 
+					when("there is a local image for another platform", func() {
+						it.Before(func() {
+							localImg, err := local.NewImage(repoName, docker)
+							h.AssertNil(t, err)
+							h.AssertNil(t, localImg.SetLabel(label, "2"))
+							h.AssertNil(t, localImg.SetArchitecture("some-other-arch"))
+							h.AssertNil(t, localImg.Save())
+						})
+
+						it.After(func() {
+							h.DockerRmi(docker, repoName)
+						})
+
+						it("pulls the image for the platform", func() {
+							fetchedImg, err := imageFetcher.Fetch(context.TODO(), repoName, image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent, Platform: remoteImgPlatform})
+							h.AssertNil(t, err)
+
+							fetchedImgLabel, err := fetchedImg.Label(label)
+							h.AssertNil(t, err)
+							h.AssertEq(t, fetchedImgLabel, remoteImgLabel)
+						})
+					})
+
 					when("there is no local image", func() {
 						it("returns the remote image", func() {
 							fetchedImg, err := imageFetcher.Fetch(context.TODO(), repoName, image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent})
@@ -0,0 +1,37 @@
+package project
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+)
+
+// LockFileName is the name of the lock file written next to the project descriptor.
+const LockFileName = "project.lock"
+
+const lockFileHeader = "# This file is generated by 'pack build --lock'. Do not edit it manually.\n\n"
+
+// ReadLock reads the lock file at pathToFile.
+func ReadLock(pathToFile string) (types.Lock, error) {
+	var lock types.Lock
+	if _, err := toml.DecodeFile(filepath.Clean(pathToFile), &lock); err != nil {
+		return types.Lock{}, err
+	}
+
+	return lock, nil
+}
+
+// WriteLock writes lock to the lock file at pathToFile.
+func WriteLock(pathToFile string, lock types.Lock) error {
+	buf := bytes.NewBufferString(lockFileHeader)
+	if err := toml.NewEncoder(buf).Encode(lock); err != nil {
+		return errors.Wrap(err, "encoding lock file")
+	}
+
+	return ioutil.WriteFile(pathToFile, buf.Bytes(), 0644)
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/project/types"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLock(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Lock", testLock, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLock(t *testing.T, when spec.G, it spec.S) {
+	var tmpDir string
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "project-lock")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#WriteLock", func() {
+		it("writes a lock file that can be read back", func() {
+			lock := types.Lock{
+				Builder:        types.LockedImage{Image: "some/builder", Digest: "sha256:builder"},
+				RunImage:       types.LockedImage{Image: "some/run", Digest: "sha256:run"},
+				LifecycleImage: &types.LockedImage{Image: "some/lifecycle", Digest: "sha256:lifecycle"},
+				Buildpacks: []types.LockedBuildpack{
+					{URI: "docker://some/buildpack", ID: "some-id", Version: "1.0.0", Digest: "sha256:buildpack"},
+				},
+			}
+			path := filepath.Join(tmpDir, LockFileName)
+
+			h.AssertNil(t, WriteLock(path, lock))
+
+			contents, err := ioutil.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(contents), "# This file is generated by 'pack build --lock'")
+			h.AssertContains(t, string(contents), "[run-image]")
+
+			readLock, err := ReadLock(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, readLock, lock)
+		})
+
+		it("omits the lifecycle image when it isn't resolved", func() {
+			path := filepath.Join(tmpDir, LockFileName)
+
+			h.AssertNil(t, WriteLock(path, types.Lock{Builder: types.LockedImage{Image: "some/builder", Digest: "sha256:builder"}}))
+
+			contents, err := ioutil.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertNotContains(t, string(contents), "lifecycle-image")
+		})
+	})
+
+	when("#ReadLock", func() {
+		it("fails when the lock file doesn't exist", func() {
+			_, err := ReadLock(filepath.Join(tmpDir, LockFileName))
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -73,7 +73,39 @@ func validate(p types.Descriptor) error {
 		}
 	}
 
-	for _, bp := range p.Build.Buildpacks {
+	if err := validateBuildpacks(p.Build.Buildpacks); err != nil {
+		return err
+	}
+
+	for _, secret := range p.Build.Secrets {
+		if secret.ID == "" || secret.Source == "" {
+			return errors.New("project.toml: secrets must have an id and src defined")
+		}
+	}
+
+	images := map[string]bool{}
+	for _, app := range p.Apps {
+		if app.Path == "" || app.Image == "" {
+			return errors.New("project.toml: apps must have a path and image defined")
+		}
+		if images[app.Image] {
+			return errors.Errorf("project.toml: apps cannot share the image %s", app.Image)
+		}
+		images[app.Image] = true
+
+		if app.Exclude != nil && app.Include != nil {
+			return errors.Errorf("project.toml: app %s cannot have both include and exclude defined", app.Path)
+		}
+		if err := validateBuildpacks(app.Buildpacks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateBuildpacks(bps []types.Buildpack) error {
+	for _, bp := range bps {
 		if bp.ID == "" && bp.URI == "" {
 			return errors.New("project.toml: buildpacks must have an id or url defined")
 		}
@@ -14,6 +14,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/pkg/project/types"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -309,6 +310,167 @@ version = "1.2.3"
 			}
 		})
 
+		it("should parse secrets", func() {
+			projectToml := `
+[project]
+name = "secrets"
+
+[[build.secrets]]
+id = "npm"
+src = "./npmrc"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []types.Secret{{ID: "npm", Source: "./npmrc"}}
+			if !reflect.DeepEqual(expected, projectDescriptor.Build.Secrets) {
+				t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
+					expected, projectDescriptor.Build.Secrets)
+			}
+		})
+
+		it("should parse labels", func() {
+			projectToml := `
+[project]
+name = "labels"
+
+[build.labels]
+"com.example.team" = "payments"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := map[string]string{"com.example.team": "payments"}
+			if !reflect.DeepEqual(expected, projectDescriptor.Build.Labels) {
+				t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
+					expected, projectDescriptor.Build.Labels)
+			}
+		})
+
+		it("should parse apps of a v0.2 project.toml file", func() {
+			projectToml := `
+[_]
+name = "monorepo"
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+name = "api"
+path = "services/api"
+image = "registry.example.com/api"
+exclude = [ "*.md" ]
+[[io.buildpacks.apps.group]]
+id = "example/go"
+version = "1.0"
+[[io.buildpacks.apps.env.build]]
+name = "CGO_ENABLED"
+value = "0"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "registry.example.com/web"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []types.App{
+				{
+					Name:       "api",
+					Path:       "services/api",
+					Image:      "registry.example.com/api",
+					Exclude:    []string{"*.md"},
+					Env:        []types.EnvVar{{Name: "CGO_ENABLED", Value: "0"}},
+					Buildpacks: []types.Buildpack{{ID: "example/go", Version: "1.0"}},
+				},
+				{
+					Path:  "services/web",
+					Image: "registry.example.com/web",
+				},
+			}
+			if !reflect.DeepEqual(expected, projectDescriptor.Apps) {
+				t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
+					expected, projectDescriptor.Apps)
+			}
+		})
+
+		it("should require a path and image for apps", func() {
+			projectToml := `
+[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+			h.AssertError(t, err, "project.toml: apps must have a path and image defined")
+		})
+
+		it("should not allow apps to share an image", func() {
+			projectToml := `
+[_]
+schema-version = "0.2"
+
+[[io.buildpacks.apps]]
+path = "services/api"
+image = "registry.example.com/app"
+
+[[io.buildpacks.apps]]
+path = "services/web"
+image = "registry.example.com/app"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+			h.AssertError(t, err, "project.toml: apps cannot share the image registry.example.com/app")
+		})
+
+		it("should require an id and src for secrets", func() {
+			projectToml := `
+[project]
+name = "secrets should have an id and src defined"
+
+[[build.secrets]]
+id = "npm"
+`
+			tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+			if err == nil {
+				t.Fatal("Expected error for NOT having an id and src defined for a secret")
+			}
+		})
+
 		it("should require either a type or uri for licenses", func() {
 			projectToml := `
 [project]
@@ -22,12 +22,30 @@ type EnvVar struct {
 	Value string `toml:"value"`
 }
 
+type Secret struct {
+	ID     string `toml:"id"`
+	Source string `toml:"src"`
+}
+
 type Build struct {
+	Include    []string          `toml:"include"`
+	Exclude    []string          `toml:"exclude"`
+	Buildpacks []Buildpack       `toml:"buildpacks"`
+	Env        []EnvVar          `toml:"env"`
+	Builder    string            `toml:"builder"`
+	Secrets    []Secret          `toml:"secrets"`
+	Labels     map[string]string `toml:"labels"`
+}
+
+// App is one of several apps of a project, such as a service of a monorepo, built to its own image.
+type App struct {
+	Name       string      `toml:"name"`
+	Path       string      `toml:"path"`
+	Image      string      `toml:"image"`
 	Include    []string    `toml:"include"`
 	Exclude    []string    `toml:"exclude"`
-	Buildpacks []Buildpack `toml:"buildpacks"`
 	Env        []EnvVar    `toml:"env"`
-	Builder    string      `toml:"builder"`
+	Buildpacks []Buildpack `toml:"buildpacks"`
 }
 
 type Project struct {
@@ -45,6 +63,27 @@ type License struct {
 type Descriptor struct {
 	Project       Project                `toml:"project"`
 	Build         Build                  `toml:"build"`
+	Apps          []App                  `toml:"apps"`
 	Metadata      map[string]interface{} `toml:"metadata"`
 	SchemaVersion *api.Version
 }
+
+// Lock records the digests of the images and buildpacks resolved by a build.
+type Lock struct {
+	Builder        LockedImage       `toml:"builder"`
+	RunImage       LockedImage       `toml:"run-image"`
+	LifecycleImage *LockedImage      `toml:"lifecycle-image,omitempty"`
+	Buildpacks     []LockedBuildpack `toml:"buildpacks,omitempty"`
+}
+
+type LockedImage struct {
+	Image  string `toml:"image"`
+	Digest string `toml:"digest"`
+}
+
+type LockedBuildpack struct {
+	URI     string `toml:"uri,omitempty"`
+	ID      string `toml:"id"`
+	Version string `toml:"version"`
+	Digest  string `toml:"digest"`
+}
@@ -13,6 +13,19 @@ type Buildpacks struct {
 	Group   []types.Buildpack `toml:"group"`
 	Env     Env               `toml:"env"`
 	Builder string            `toml:"builder"`
+	Secrets []types.Secret    `toml:"secrets"`
+	Labels  map[string]string `toml:"labels"`
+	Apps    []App             `toml:"apps"`
+}
+
+type App struct {
+	Name    string            `toml:"name"`
+	Path    string            `toml:"path"`
+	Image   string            `toml:"image"`
+	Include []string          `toml:"include"`
+	Exclude []string          `toml:"exclude"`
+	Group   []types.Buildpack `toml:"group"`
+	Env     Env               `toml:"env"`
 }
 
 type Env struct {
@@ -42,6 +55,19 @@ func NewDescriptor(projectTomlContents string) (types.Descriptor, error) {
 		return types.Descriptor{}, err
 	}
 
+	var apps []types.App
+	for _, app := range versionedDescriptor.IO.Buildpacks.Apps {
+		apps = append(apps, types.App{
+			Name:       app.Name,
+			Path:       app.Path,
+			Image:      app.Image,
+			Include:    app.Include,
+			Exclude:    app.Exclude,
+			Env:        app.Env.Build,
+			Buildpacks: app.Group,
+		})
+	}
+
 	return types.Descriptor{
 		Project: types.Project{
 			Name:     versionedDescriptor.Project.Name,
@@ -53,7 +79,10 @@ func NewDescriptor(projectTomlContents string) (types.Descriptor, error) {
 			Buildpacks: versionedDescriptor.IO.Buildpacks.Group,
 			Env:        versionedDescriptor.IO.Buildpacks.Env.Build,
 			Builder:    versionedDescriptor.IO.Buildpacks.Builder,
+			Secrets:    versionedDescriptor.IO.Buildpacks.Secrets,
+			Labels:     versionedDescriptor.IO.Buildpacks.Labels,
 		},
+		Apps:          apps,
 		Metadata:      versionedDescriptor.Project.Metadata,
 		SchemaVersion: api.MustParse("0.2"),
 	}, nil